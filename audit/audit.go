@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// Acciones que se registran en la auditoría
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Campos que cambian en cada escritura y no aportan información al diff
+var ignoredFields = map[string]bool{
+	"UpdatedAt": true,
+}
+
+// Change es el valor anterior y el nuevo de un campo modificado
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// flatten convierte un valor JSON en un mapa de rutas separadas por puntos, de modo que los
+// objetos anidados (por ejemplo el usuario de un empleado) se comparan campo a campo
+func flatten(prefix string, value interface{}, out map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok || (prefix != "" && len(object) == 0) {
+		out[prefix] = value
+		return
+	}
+	for key, field := range object {
+		if prefix == "" && ignoredFields[key] {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flatten(path, field, out)
+	}
+}
+
+// snapshot serializa un registro a JSON y lo aplana; un registro nulo produce un mapa vacío
+func snapshot(record interface{}) (string, map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if record == nil || (reflect.ValueOf(record).Kind() == reflect.Ptr && reflect.ValueOf(record).IsNil()) {
+		return "", fields, nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", nil, err
+	}
+	flatten("", decoded, fields)
+	return string(data), fields, nil
+}
+
+// Diff devuelve los campos que cambiaron entre dos versiones de un registro
+func Diff(before, after interface{}) (map[string]Change, error) {
+	_, old, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	_, current, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for path, value := range current {
+		if previous, ok := old[path]; !ok || !reflect.DeepEqual(previous, value) {
+			changes[path] = Change{Before: old[path], After: value}
+		}
+	}
+	for path, previous := range old {
+		if _, ok := current[path]; !ok {
+			changes[path] = Change{Before: previous}
+		}
+	}
+	return changes, nil
+}
+
+// Record guarda una entrada de auditoría dentro de la transacción indicada, para que la
+// modificación y su registro se confirmen o se descarten juntos
+func Record(tx *gorm.DB, r *http.Request, action, resourceType string, resourceID uint, before, after interface{}) error {
+	beforeJSON, _, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, _, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+	changes, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	// Las tareas en segundo plano registran sus cambios sin solicitud
+	actor, requestID := "system", ""
+	if r != nil {
+		actor = middleware.PrincipalFromRequest(r).Actor
+		requestID = middleware.RequestIDFromRequest(r)
+	}
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	entry := models.AuditLog{
+		Actor:        actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Before:       beforeJSON,
+		After:        afterJSON,
+		Diff:         string(diff),
+		RequestID:    requestID,
+	}
+	return tx.Create(&entry).Error
+}
+
+// Tipos de recurso que se registran en la auditoría
+const (
+	ResourceUser                = "user"
+	ResourceReservation         = "reservation"
+	ResourceConsultation        = "consultation"
+	ResourceEmployee            = "employee"
+	ResourcePayment             = "payment"
+	ResourceFolioEntry          = "folio_entry"
+	ResourceInvoice             = "invoice"
+	ResourceRatePlan            = "rate_plan"
+	ResourceCancellationPolicy  = "cancellation_policy"
+	ResourceRoomType            = "room_type"
+	ResourceGroupBlock          = "group_block"
+	ResourceRoom                = "room"
+	ResourceRoomAssignment      = "room_assignment"
+	ResourceHousekeepingTask    = "housekeeping_task"
+	ResourceWorkOrder           = "work_order"
+	ResourceRoomOutage          = "room_outage"
+	ResourceConsultationMessage = "consultation_message"
+	ResourceWebhookSubscription = "webhook_subscription"
+	ResourceChannel             = "channel"
+	ResourceRestriction         = "restriction"
+	ResourceChannelConflict     = "channel_conflict"
+	ResourceWebhookDelivery     = "webhook_delivery"
+	ResourceDomainEvent         = "domain_event"
+	ResourceNotification        = "notification"
+)
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffReportsChangedFields(t *testing.T) {
+	before := models.Employee{User: &models.User{FirstName: "Carlos", LastName: "Gómez"}, Position: "Recepcionista", Salary: 1000}
+	after := before
+	after.User = &models.User{FirstName: "Carlos", LastName: "Gomez"}
+	after.Salary = 1200
+
+	changes, err := Diff(&before, &after)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, Change{Before: 1000.0, After: 1200.0}, changes["salary"])
+	assert.Equal(t, Change{Before: "Gómez", After: "Gomez"}, changes["user.last_name"])
+}
+
+func TestDiffOnCreateAndDelete(t *testing.T) {
+	consultation := &models.Consultation{Phone: "123456789", MoreInfo: true}
+
+	created, err := Diff(nil, consultation)
+	assert.NoError(t, err)
+	assert.Equal(t, Change{Before: nil, After: "123456789"}, created["phone"])
+
+	var none *models.Consultation
+	deleted, err := Diff(consultation, none)
+	assert.NoError(t, err)
+	assert.Equal(t, Change{Before: true, After: nil}, deleted["more_info"])
+}
@@ -0,0 +1,134 @@
+package channels
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockChannelManager simula la API de un channel manager: guarda las actualizaciones ARI recibidas y
+// devuelve las reservas a partir de la posición pedida
+type mockChannelManager struct {
+	pushes   []ARIRequest
+	bookings []Booking
+	failPush bool
+}
+
+func (m *mockChannelManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer key-123" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/ari":
+		if m.failPush {
+			http.Error(w, "rate below minimum", http.StatusUnprocessableEntity)
+			return
+		}
+		var request ARIRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		m.pushes = append(m.pushes, request)
+	case r.Method == "GET" && r.URL.Path == "/reservations":
+		since := 0
+		if r.URL.Query().Get("since") == "2" {
+			since = 2
+		}
+		json.NewEncoder(w).Encode(PullResponse{Reservations: m.bookings[since:], Cursor: "2"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestClientAgainstMockServer(t *testing.T) {
+	mock := &mockChannelManager{bookings: []Booking{
+		{Reference: "BK-1", Status: BookingNew, RoomCode: "DBL", CheckIn: "2025-03-10", CheckOut: "2025-03-12", Rooms: 1},
+		{Reference: "BK-1", Status: BookingModified, RoomCode: "DBL", CheckIn: "2025-03-10", CheckOut: "2025-03-13", Rooms: 1},
+	}}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	client := NewClient(models.Channel{Endpoint: server.URL + "/", APIKey: "key-123"})
+
+	err := client.PushARI(ARIRequest{HotelCode: "H1", Updates: []ARIUpdate{{RoomCode: "DBL", Date: "2025-03-10", Available: 3}}})
+	assert.NoError(t, err)
+	assert.Len(t, mock.pushes, 1)
+	assert.Equal(t, "H1", mock.pushes[0].HotelCode)
+	assert.Equal(t, 3, mock.pushes[0].Updates[0].Available)
+
+	response, err := client.PullReservations("H1", "")
+	assert.NoError(t, err)
+	assert.Len(t, response.Reservations, 2)
+	assert.Equal(t, "2", response.Cursor)
+
+	// Desde la última posición no hay reservas nuevas
+	response, err = client.PullReservations("H1", response.Cursor)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Reservations)
+
+	// Los errores del channel manager incluyen el estado y el mensaje
+	mock.failPush = true
+	err = client.PushARI(ARIRequest{HotelCode: "H1"})
+	assert.EqualError(t, err, "channel manager returned 422: rate below minimum")
+
+	client.APIKey = "wrong"
+	_, err = client.PullReservations("H1", "")
+	assert.Error(t, err)
+}
+
+func TestBuildUpdates(t *testing.T) {
+	from := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	rooms := []models.ChannelRoomMapping{
+		{ChannelRoomCode: "DBL", RoomType: "double"},
+		{ChannelRoomCode: "STE", RoomType: "suite"}, // Sin inventario configurado
+	}
+	rates := []models.ChannelRateMapping{{ChannelRateCode: "BAR", RatePlanID: 1}, {ChannelRateCode: "NRF", RatePlanID: 2}}
+	ratePlans := map[uint]models.RatePlan{1: {NightlyRate: 120}, 2: {NightlyRate: 100}}
+	availability := map[string][]int{"double": {4, 0}, "suite": nil}
+	restrictions := map[string]map[string]models.Restriction{
+		"double": {"2025-03-11": {MinStay: 2, ClosedToArrival: true}},
+	}
+
+	updates := buildUpdates(rooms, rates, ratePlans, availability, restrictions, from)
+	assert.Equal(t, []ARIUpdate{
+		{RoomCode: "DBL", RateCode: "BAR", Date: "2025-03-10", Available: 4, Rate: 120},
+		{RoomCode: "DBL", RateCode: "NRF", Date: "2025-03-10", Available: 4, Rate: 100},
+		{RoomCode: "DBL", RateCode: "BAR", Date: "2025-03-11", Available: 0, Rate: 120, MinStay: 2, ClosedToArrival: true},
+		{RoomCode: "DBL", RateCode: "NRF", Date: "2025-03-11", Available: 0, Rate: 100, MinStay: 2, ClosedToArrival: true},
+	}, updates)
+
+	// Sin tarifas mapeadas solo se envía la disponibilidad
+	updates = buildUpdates(rooms[:1], nil, nil, availability, nil, from)
+	assert.Equal(t, []ARIUpdate{
+		{RoomCode: "DBL", Date: "2025-03-10", Available: 4},
+		{RoomCode: "DBL", Date: "2025-03-11", Available: 0},
+	}, updates)
+}
+
+func TestBookingDates(t *testing.T) {
+	checkin, checkout, err := Booking{CheckIn: "2025-03-10", CheckOut: "2025-03-12"}.Dates()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, int(checkout.Sub(checkin).Hours()/24))
+
+	_, _, err = Booking{CheckIn: "2025-03-12", CheckOut: "2025-03-12"}.Dates()
+	assert.Error(t, err)
+	_, _, err = Booking{CheckIn: "10/03/2025", CheckOut: "2025-03-12"}.Dates()
+	assert.Error(t, err)
+}
+
+func TestConflictError(t *testing.T) {
+	err := conflict(models.ConflictUnmappedRoom, `room code "TWN" is not mapped`)
+	assert.EqualError(t, err, `unmapped_room: room code "TWN" is not mapped`)
+}
+
+func TestSameRatePlan(t *testing.T) {
+	one, two, otherOne := uint(1), uint(2), uint(1)
+	assert.True(t, sameRatePlan(nil, nil))
+	assert.True(t, sameRatePlan(&one, &otherOne))
+	// Un cambio solo de tarifa también es una modificación
+	assert.False(t, sameRatePlan(&one, &two))
+	assert.False(t, sameRatePlan(nil, &one))
+}
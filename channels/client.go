@@ -0,0 +1,147 @@
+package channels
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// Estados de una reserva enviada por el channel manager
+const (
+	BookingNew       = "new"
+	BookingModified  = "modified"
+	BookingCancelled = "cancelled"
+)
+
+// HTTPClient es el cliente HTTP con el que se llama a los channel managers
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// ARIUpdate es la disponibilidad, la tarifa y las restricciones de un producto del canal en una fecha
+type ARIUpdate struct {
+	RoomCode          string  `json:"room_code"`
+	RateCode          string  `json:"rate_code,omitempty"`
+	Date              string  `json:"date"` // YYYY-MM-DD
+	Available         int     `json:"available"`
+	Rate              float64 `json:"rate,omitempty"`
+	MinStay           int     `json:"min_stay,omitempty"`
+	ClosedToArrival   bool    `json:"closed_to_arrival"`
+	ClosedToDeparture bool    `json:"closed_to_departure"`
+	StopSell          bool    `json:"stop_sell"`
+}
+
+// ARIRequest es el cuerpo de POST {endpoint}/ari
+type ARIRequest struct {
+	HotelCode string      `json:"hotel_code"`
+	Updates   []ARIUpdate `json:"updates"`
+}
+
+// Booking es una reserva, modificación o cancelación recibida del canal
+type Booking struct {
+	Reference string  `json:"reference"` // Número de reserva en el canal
+	Status    string  `json:"status"`    // new, modified o cancelled
+	RoomCode  string  `json:"room_code"`
+	RateCode  string  `json:"rate_code,omitempty"`
+	CheckIn   string  `json:"check_in"` // YYYY-MM-DD
+	CheckOut  string  `json:"check_out"`
+	Rooms     int     `json:"rooms"`
+	Adults    int     `json:"adults"`
+	Children  int     `json:"children"`
+	GuestName string  `json:"guest_name"`
+	Email     string  `json:"email"`
+	Total     float64 `json:"total,omitempty"`
+}
+
+// Dates devuelve las fechas de llegada y salida de la reserva
+func (b Booking) Dates() (time.Time, time.Time, error) {
+	checkin, err := time.Parse("2006-01-02", b.CheckIn)
+	if err != nil {
+		return checkin, checkin, fmt.Errorf("invalid check_in %q", b.CheckIn)
+	}
+	checkout, err := time.Parse("2006-01-02", b.CheckOut)
+	if err != nil {
+		return checkin, checkout, fmt.Errorf("invalid check_out %q", b.CheckOut)
+	}
+	if !checkout.After(checkin) {
+		return checkin, checkout, fmt.Errorf("check_out %s is not after check_in %s", b.CheckOut, b.CheckIn)
+	}
+	return checkin, checkout, nil
+}
+
+// PullResponse es la respuesta de GET {endpoint}/reservations
+type PullResponse struct {
+	Reservations []Booking `json:"reservations"`
+	Cursor       string    `json:"cursor"` // Posición desde la que pedir la próxima vez
+}
+
+// Client habla con la API JSON de un channel manager autenticándose con su clave
+type Client struct {
+	Endpoint string
+	APIKey   string
+	HTTP     *http.Client
+}
+
+// NewClient crea el cliente del canal indicado
+func NewClient(channel models.Channel) *Client {
+	return &Client{Endpoint: strings.TrimRight(channel.Endpoint, "/"), APIKey: channel.APIKey, HTTP: HTTPClient}
+}
+
+// do envía la solicitud y decodifica la respuesta en out. Las respuestas fuera del rango 2xx son errores
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.Endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("channel manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// PushARI envía disponibilidad, tarifas y restricciones
+func (c *Client) PushARI(request ARIRequest) error {
+	return c.do("POST", "/ari", request, nil)
+}
+
+// PullReservations pide las reservas nuevas, modificadas y canceladas desde la posición indicada
+func (c *Client) PullReservations(hotelCode, cursor string) (PullResponse, error) {
+	query := url.Values{}
+	query.Set("hotel_code", hotelCode)
+	if cursor != "" {
+		query.Set("since", cursor)
+	}
+	var response PullResponse
+	err := c.do("GET", "/reservations?"+query.Encode(), nil, &response)
+	return response, err
+}
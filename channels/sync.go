@@ -0,0 +1,405 @@
+package channels
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleReservation se devuelve si la reserva cambió mientras se importaba una modificación del canal
+var ErrStaleReservation = errors.New("reservation was modified concurrently")
+
+// ConflictError es una reserva del canal que no se puede importar y queda registrada como conflicto
+type ConflictError struct {
+	Kind    string
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Kind + ": " + e.Message
+}
+
+func conflict(kind, message string) error {
+	return &ConflictError{Kind: kind, Message: message}
+}
+
+// PullResult resume una importación de reservas
+type PullResult struct {
+	Created   int `json:"created"`
+	Modified  int `json:"modified"`
+	Cancelled int `json:"cancelled"`
+	Unchanged int `json:"unchanged"`
+	Conflicts int `json:"conflicts"` // Incluye las reservas importadas sin disponibilidad
+}
+
+// ARIDays es la cantidad de días hacia adelante que se envían al canal, configurable con CHANNEL_ARI_DAYS
+func ARIDays() int {
+	if days, err := strconv.Atoi(os.Getenv("CHANNEL_ARI_DAYS")); err == nil && days > 0 {
+		return days
+	}
+	return 180
+}
+
+// buildUpdates arma una actualización por producto (habitación y tarifa mapeadas) y fecha. Los tipos de
+// habitación sin inventario configurado no se envían
+func buildUpdates(rooms []models.ChannelRoomMapping, rates []models.ChannelRateMapping, ratePlans map[uint]models.RatePlan,
+	availability map[string][]int, restrictions map[string]map[string]models.Restriction, from time.Time) []ARIUpdate {
+	if len(rates) == 0 {
+		rates = []models.ChannelRateMapping{{}} // Sin tarifas mapeadas solo se envía la disponibilidad
+	}
+
+	var updates []ARIUpdate
+	for _, room := range rooms {
+		days, ok := availability[room.RoomType]
+		if !ok || days == nil {
+			continue
+		}
+		for i, available := range days {
+			date := from.AddDate(0, 0, i).Format("2006-01-02")
+			restriction := restrictions[room.RoomType][date]
+			for _, rate := range rates {
+				updates = append(updates, ARIUpdate{
+					RoomCode:          room.ChannelRoomCode,
+					RateCode:          rate.ChannelRateCode,
+					Date:              date,
+					Available:         available,
+					Rate:              ratePlans[rate.RatePlanID].NightlyRate,
+					MinStay:           restriction.MinStay,
+					ClosedToArrival:   restriction.ClosedToArrival,
+					ClosedToDeparture: restriction.ClosedToDeparture,
+					StopSell:          restriction.StopSell,
+				})
+			}
+		}
+	}
+	return updates
+}
+
+// BuildARI calcula la disponibilidad, las tarifas y las restricciones de los productos mapeados del canal
+// para cada noche entre from y to. El canal debe tener sus mapeos cargados
+func BuildARI(tx *gorm.DB, channel models.Channel, from, to time.Time) ([]ARIUpdate, error) {
+	from, to = inventory.Day(from), inventory.Day(to)
+	availability := make(map[string][]int)
+	restrictions := make(map[string]map[string]models.Restriction)
+	for _, room := range channel.RoomMappings {
+		if _, ok := availability[room.RoomType]; ok {
+			continue
+		}
+		days, err := inventory.DailyAvailability(tx, room.RoomType, from, to)
+		if err != nil {
+			return nil, err
+		}
+		availability[room.RoomType] = days
+
+		var dayRestrictions []models.Restriction
+		if err := tx.Where("room_type = ? AND date >= ? AND date < ?", room.RoomType, from, to).Find(&dayRestrictions).Error; err != nil {
+			return nil, err
+		}
+		restrictions[room.RoomType] = make(map[string]models.Restriction)
+		for _, restriction := range dayRestrictions {
+			restrictions[room.RoomType][inventory.Day(restriction.Date).Format("2006-01-02")] = restriction
+		}
+	}
+
+	ratePlans := make(map[uint]models.RatePlan)
+	for _, rate := range channel.RateMappings {
+		var plan models.RatePlan
+		if err := tx.Limit(1).Find(&plan, rate.RatePlanID).Error; err != nil {
+			return nil, err
+		}
+		ratePlans[rate.RatePlanID] = plan
+	}
+	return buildUpdates(channel.RoomMappings, channel.RateMappings, ratePlans, availability, restrictions, from), nil
+}
+
+// recordSync guarda el resultado de la última sincronización del canal
+func recordSync(db *gorm.DB, channel models.Channel, column string, err error) {
+	values := map[string]interface{}{"last_error": ""}
+	if err != nil {
+		values["last_error"] = err.Error()
+	} else {
+		values[column] = time.Now()
+	}
+	db.Model(&channel).Updates(values)
+}
+
+// Push envía al canal la disponibilidad, las tarifas y las restricciones entre from y to y devuelve la
+// cantidad de actualizaciones enviadas
+func Push(db *gorm.DB, client *Client, channel models.Channel, from, to time.Time) (int, error) {
+	updates, err := BuildARI(db, channel, from, to)
+	if err == nil {
+		err = client.PushARI(ARIRequest{HotelCode: channel.HotelCode, Updates: updates})
+	}
+	recordSync(db, channel, "last_push_at", err)
+	return len(updates), err
+}
+
+// Pull importa las reservas del canal desde la última posición. Cada reserva se importa en su propio
+// punto de guardado: las que no se pueden importar quedan registradas como conflictos y el resto se
+// confirma junto con la nueva posición
+func Pull(db *gorm.DB, client *Client, channel models.Channel, r *http.Request) (PullResult, error) {
+	var result PullResult
+	response, err := client.PullReservations(channel.HotelCode, channel.PullCursor)
+	if err != nil {
+		recordSync(db, channel, "last_pull_at", err)
+		return result, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, booking := range response.Reservations {
+			var outcome string
+			var reservationID *uint
+			err := tx.Transaction(func(sp *gorm.DB) error {
+				var err error
+				outcome, reservationID, err = Ingest(sp, r, channel, booking)
+				// Otra importación simultánea guardó la misma referencia: se revisa como conflicto en lugar
+				// de detener el lote, que si no se reintentaría sin fin sin avanzar el cursor
+				if err != nil && strings.Contains(err.Error(), "SQLSTATE 23505") {
+					return conflict(models.ConflictDuplicate, "reference "+strconv.Quote(booking.Reference)+" is already in use")
+				}
+				return err
+			})
+			var conflictErr *ConflictError
+			if errors.As(err, &conflictErr) {
+				result.Conflicts++
+				if err := recordConflict(tx, channel, booking, conflictErr, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			switch outcome {
+			case BookingNew:
+				result.Created++
+			case BookingModified:
+				result.Modified++
+			case BookingCancelled:
+				result.Cancelled++
+			default:
+				result.Unchanged++
+			}
+			// La reserva se importó aunque no había disponibilidad: hay que reubicar al huésped
+			if reservationID != nil {
+				result.Conflicts++
+				overbooking := &ConflictError{Kind: models.ConflictOverbooking, Message: "no availability for " + booking.RoomCode}
+				if err := recordConflict(tx, channel, booking, overbooking, reservationID); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Model(&channel).Update("pull_cursor", response.Cursor).Error
+	})
+	recordSync(db, channel, "last_pull_at", err)
+	return result, err
+}
+
+// recordConflict registra una reserva del canal que requiere revisión
+func recordConflict(tx *gorm.DB, channel models.Channel, booking Booking, err *ConflictError, reservationID *uint) error {
+	payload, _ := json.Marshal(booking)
+	return tx.Create(&models.ChannelConflict{
+		ChannelID:     channel.ID,
+		Reference:     booking.Reference,
+		Kind:          err.Kind,
+		Message:       err.Message,
+		Payload:       string(payload),
+		ReservationID: reservationID,
+		Status:        models.ConflictOpen,
+	}).Error
+}
+
+// reserve ocupa el inventario de la reserva del canal. El canal ya vendió la habitación, así que la
+// reserva se importa igual cuando no hay disponibilidad y se informa la sobreventa
+func reserve(tx *gorm.DB, roomType string, from, to time.Time, rooms int) (bool, error) {
+	err := inventory.Reserve(tx, roomType, from, to, rooms)
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		return true, nil
+	}
+	return false, err
+}
+
+// save guarda la reserva comprobando que nadie la haya modificado desde que se leyó
+func save(tx *gorm.DB, reservation *models.Reservation) error {
+	current := reservation.Version
+	reservation.Version++
+	result := tx.Model(reservation).Where("version = ?", current).Select("*").Omit(clause.Associations).Updates(reservation)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrStaleReservation
+	}
+	return result.Error
+}
+
+// sameRatePlan indica si dos reservas tienen la misma tarifa, o ninguna
+func sameRatePlan(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Ingest aplica una reserva, modificación o cancelación del canal y devuelve qué hizo: new, modified,
+// cancelled o unchanged. Reenviar la misma reserva no la duplica. Si la reserva quedó sobrevendida
+// devuelve su ID para informarlo
+func Ingest(tx *gorm.DB, r *http.Request, channel models.Channel, booking Booking) (string, *uint, error) {
+	if booking.Reference == "" {
+		return "", nil, conflict(models.ConflictInvalid, "missing reference")
+	}
+	// La referencia sigue siendo única aunque la reserva se haya eliminado, así que también se buscan
+	// las eliminadas
+	var reservation models.Reservation
+	if err := tx.Unscoped().Where("channel_id = ? AND channel_ref = ?", channel.ID, booking.Reference).Limit(1).Find(&reservation).Error; err != nil {
+		return "", nil, err
+	}
+	if reservation.DeletedAt.Valid {
+		return "", nil, conflict(models.ConflictNotModifiable, "reservation "+strconv.Quote(booking.Reference)+" was deleted")
+	}
+	inHouse := reservation.Status == models.ReservationCheckedIn || reservation.Status == models.ReservationCheckedOut
+
+	switch booking.Status {
+	case BookingCancelled:
+		if reservation.ID == 0 {
+			return "", nil, conflict(models.ConflictUnknownReservation, "cancellation of unknown reservation "+booking.Reference)
+		}
+		if reservation.Status == models.ReservationCancelled {
+			return "unchanged", nil, nil
+		}
+		if inHouse {
+			return "", nil, conflict(models.ConflictNotModifiable, "guest already checked in")
+		}
+		now := time.Now()
+		before := reservation
+		reservation.Status = models.ReservationCancelled
+		reservation.CancelledAt = &now
+		if err := save(tx, &reservation); err != nil {
+			return "", nil, err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+			return "", nil, err
+		}
+		return BookingCancelled, nil, events.PublishReservation(tx, events.ReservationCancelled, reservation)
+	case BookingNew, BookingModified:
+	default:
+		return "", nil, conflict(models.ConflictInvalid, "unknown status "+strconv.Quote(booking.Status))
+	}
+
+	checkin, checkout, err := booking.Dates()
+	if err != nil {
+		return "", nil, conflict(models.ConflictInvalid, err.Error())
+	}
+	roomType, ok := "", false
+	for _, mapping := range channel.RoomMappings {
+		if mapping.ChannelRoomCode == booking.RoomCode {
+			roomType, ok = mapping.RoomType, true
+		}
+	}
+	if !ok {
+		return "", nil, conflict(models.ConflictUnmappedRoom, "room code "+strconv.Quote(booking.RoomCode)+" is not mapped")
+	}
+	var ratePlanID *uint
+	if booking.RateCode != "" {
+		for _, mapping := range channel.RateMappings {
+			if mapping.ChannelRateCode == booking.RateCode {
+				id := mapping.RatePlanID
+				ratePlanID = &id
+			}
+		}
+		if ratePlanID == nil {
+			return "", nil, conflict(models.ConflictUnmappedRate, "rate code "+strconv.Quote(booking.RateCode)+" is not mapped")
+		}
+	}
+	rooms := booking.Rooms
+	if rooms < 1 {
+		rooms = 1
+	}
+
+	// Reserva nueva, o una modificación de una reserva que todavía no se había importado
+	if reservation.ID == 0 {
+		overbooked, err := reserve(tx, roomType, checkin, checkout, rooms)
+		if err != nil {
+			return "", nil, err
+		}
+		channelID, reference := channel.ID, booking.Reference
+		reservation = models.Reservation{
+			Checkin:       checkin,
+			Checkout:      checkout,
+			NumberOfRooms: rooms,
+			Adults:        booking.Adults,
+			Children:      booking.Children,
+			RoomType:      roomType,
+			RatePlanID:    ratePlanID,
+			GuestName:     booking.GuestName,
+			Email:         booking.Email,
+			Status:        models.ReservationConfirmed,
+			ChannelID:     &channelID,
+			ChannelRef:    &reference,
+		}
+		if err := tx.Create(&reservation).Error; err != nil {
+			return "", nil, err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceReservation, reservation.ID, nil, &reservation); err != nil {
+			return "", nil, err
+		}
+		if err := events.PublishReservation(tx, events.ReservationCreated, reservation); err != nil {
+			return "", nil, err
+		}
+		if overbooked {
+			return BookingNew, &reservation.ID, nil
+		}
+		return BookingNew, nil, nil
+	}
+
+	if reservation.Status == models.ReservationCancelled || inHouse {
+		return "", nil, conflict(models.ConflictNotModifiable, "reservation is "+reservation.Status)
+	}
+	if reservation.Checkin.Equal(checkin) && reservation.Checkout.Equal(checkout) && reservation.NumberOfRooms == rooms &&
+		reservation.RoomType == roomType && sameRatePlan(reservation.RatePlanID, ratePlanID) && reservation.Adults == booking.Adults &&
+		reservation.Children == booking.Children && reservation.GuestName == booking.GuestName && reservation.Email == booking.Email {
+		return "unchanged", nil, nil
+	}
+
+	before := reservation
+	// Liberar lo que ocupa la reserva antes de comprobar la disponibilidad de los datos nuevos
+	if err := tx.Model(&reservation).UpdateColumn("status", models.ReservationCancelled).Error; err != nil {
+		return "", nil, err
+	}
+	overbooked, err := reserve(tx, roomType, checkin, checkout, rooms)
+	if err != nil {
+		return "", nil, err
+	}
+	reservation.Checkin = checkin
+	reservation.Checkout = checkout
+	reservation.NumberOfRooms = rooms
+	reservation.RoomType = roomType
+	reservation.RatePlanID = ratePlanID
+	reservation.Adults = booking.Adults
+	reservation.Children = booking.Children
+	reservation.GuestName = booking.GuestName
+	reservation.Email = booking.Email
+	reservation.Status = models.ReservationConfirmed
+	if err := save(tx, &reservation); err != nil {
+		return "", nil, err
+	}
+	if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+		return "", nil, err
+	}
+	if err := events.PublishReservation(tx, events.ReservationModified, reservation); err != nil {
+		return "", nil, err
+	}
+	if overbooked {
+		return BookingModified, &reservation.ID, nil
+	}
+	return BookingModified, nil, nil
+}
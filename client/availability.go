@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Availability es la cantidad de habitaciones libres de un tipo en un rango de fechas
+type Availability struct {
+	RoomType  string    `json:"room_type"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Available int       `json:"available"`
+}
+
+// GetAvailability informa cuántas habitaciones del tipo indicado quedan libres entre la llegada y la salida
+func (c *Client) GetAvailability(ctx context.Context, roomType string, from, to time.Time) (Availability, error) {
+	query := url.Values{}
+	query.Set("room_type", roomType)
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+	var availability Availability
+	err := c.do(ctx, request{method: http.MethodGet, path: "/availability", query: query}, &availability)
+	return availability, err
+}
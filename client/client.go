@@ -0,0 +1,214 @@
+// Package client es un cliente tipado de la API del hotel para otros servicios escritos en Go. Cada
+// llamada recibe un context.Context, los errores de la API se devuelven como *APIError y las llamadas
+// idempotentes se reintentan con espera exponencial cuando fallan la red o el servidor
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores por defecto de los reintentos
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryWait  = 250 * time.Millisecond
+	DefaultMaxWait    = 5 * time.Second
+)
+
+// IdempotencyKeyHeader es la cabecera con la que la API ejecuta una sola vez los POST reintentados
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Client llama a la API del hotel autenticándose con un token de API_TOKENS
+type Client struct {
+	BaseURL    string
+	Token      string // Vacío para llamar como anónimo
+	HTTP       *http.Client
+	MaxRetries int           // Reintentos después del primer intento; 0 no reintenta
+	RetryWait  time.Duration // Espera antes del primer reintento; se duplica en cada uno
+	MaxWait    time.Duration // Espera máxima entre reintentos
+}
+
+// New crea un cliente de la API publicada en baseURL, por ejemplo http://localhost:10000
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTP:       &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: DefaultMaxRetries,
+		RetryWait:  DefaultRetryWait,
+		MaxWait:    DefaultMaxWait,
+	}
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey fija la clave de idempotencia de los POST hechos con el contexto. Sirve para que
+// el reintento de una operación completa (por ejemplo tras reiniciar el proceso) no la repita; sin
+// ella cada llamada genera su propia clave
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// Backoff devuelve la espera antes del reintento indicado (1, 2, 3...): RetryWait, el doble, el
+// cuádruple... sin superar MaxWait
+func (c *Client) Backoff(retry int) time.Duration {
+	wait := c.RetryWait
+	for i := 1; i < retry && wait < c.MaxWait; i++ {
+		wait *= 2
+	}
+	if c.MaxWait > 0 && wait > c.MaxWait {
+		wait = c.MaxWait
+	}
+	return wait
+}
+
+// request describe una llamada a la API
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        interface{}
+	contentType string // Por defecto application/json
+	ifMatch     uint   // Versión esperada del registro; 0 no envía If-Match
+}
+
+// do envía la solicitud y decodifica la respuesta JSON en out. GET, PUT y DELETE son idempotentes y
+// se reintentan; los POST llevan una clave de idempotencia para que la API no los ejecute dos veces,
+// así que también se reintentan. PATCH nunca se reintenta
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	var body []byte
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return err
+		}
+		body = data
+	}
+
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	if body != nil {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		header.Set("Content-Type", contentType)
+	}
+	if c.Token != "" {
+		header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if req.ifMatch > 0 {
+		header.Set("If-Match", "\""+strconv.FormatUint(uint64(req.ifMatch), 10)+"\"")
+	}
+	retryable := req.method == http.MethodGet || req.method == http.MethodPut || req.method == http.MethodDelete
+	if req.method == http.MethodPost {
+		key, _ := ctx.Value(idempotencyKey{}).(string)
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+		header.Set(IdempotencyKeyHeader, key)
+		retryable = true
+	}
+
+	target := c.BaseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, req.method, target, header, body, out)
+		if err == nil || !retryable || attempt >= c.MaxRetries || !temporary(err) || ctx.Err() != nil {
+			return err
+		}
+		timer := time.NewTimer(c.Backoff(attempt + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// send hace un intento de la solicitud
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	httpReq.Header = header.Clone()
+
+	resp, err := c.HTTP.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(method, httpReq.URL.Path, resp)
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response to %s %s: %w", method, httpReq.URL.Path, err)
+	}
+	return nil
+}
+
+// temporary indica si vale la pena reintentar: errores de red, 429 y los errores de un proxy o de un
+// servidor no disponible. Los demás errores de la API se repetirían igual
+func temporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// http.Client.Do devuelve *url.Error cuando no pudo enviar la solicitud o leer la respuesta. La
+	// cancelación del contexto del llamador se revisa aparte
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// newIdempotencyKey genera una clave aleatoria para un POST
+func newIdempotencyKey() string {
+	key := make([]byte, 16)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+// idPath arma la ruta de un recurso, por ejemplo /users/7/restore
+func idPath(collection string, id uint, suffix string) string {
+	return "/" + collection + "/" + strconv.FormatUint(uint64(id), 10) + suffix
+}
+
+// ListOptions son los filtros comunes de los listados
+type ListOptions struct {
+	IncludeDeleted bool // Incluir los registros eliminados; requiere el rol admin
+}
+
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+	if o != nil && o.IncludeDeleted {
+		query.Set("include_deleted", "true")
+	}
+	return query
+}
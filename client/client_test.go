@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tokens con los que se autentican los clientes de las pruebas
+var testTokens = map[string]middleware.Principal{
+	"admin-token": {Role: middleware.RoleAdmin, Actor: "admin@example.com"},
+}
+
+// newAPI envuelve el enrutador real con la autenticación y la validación de la especificación, igual
+// que main.go, y con el middleware inner si se indica. Las respuestas también se validan contra la
+// especificación
+func newAPI(inner func(http.Handler) http.Handler) http.Handler {
+	var handler http.Handler = routes.NewRouter()
+	if inner != nil {
+		handler = inner(handler)
+	}
+	return middleware.AuthWithTokens(testTokens, middleware.ValidationWithResponses(routes.OpenAPI, true, handler))
+}
+
+// flakyServer responde 503 a las primeras solicitudes antes de dejarlas pasar y registra cada intento
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	attempts []*http.Request
+}
+
+func (f *flakyServer) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.attempts = append(f.attempts, r)
+		fail := len(f.attempts) <= f.failures
+		f.mu.Unlock()
+		if fail {
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newTestClient(url, token string) *Client {
+	client := New(url, token)
+	client.RetryWait = time.Millisecond
+	return client
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := httptest.NewServer(newAPI(nil))
+	defer server.Close()
+	ctx := context.Background()
+	client := newTestClient(server.URL, "")
+
+	// La validación de la especificación rechaza el ID antes de llegar al handler
+	_, err := client.GetUser(ctx, 0)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.Equal(t, "Invalid request: path parameter id: must be at least 1", apiErr.Message)
+		assert.Equal(t, "GET", apiErr.Method)
+		assert.Equal(t, "/users/0", apiErr.Path)
+	}
+	assert.EqualError(t, err, "GET /users/0: hotel API returned 400: Invalid request: path parameter id: must be at least 1")
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	// Los errores de los handlers llegan con su mensaje
+	from := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	_, err = client.GetAvailability(ctx, "DBL", from, from.AddDate(0, 0, -2))
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	assert.Contains(t, err.Error(), "Invalid to date")
+
+	// Sin token no se pueden ver los eliminados ni recuperar registros
+	_, err = client.ListUsers(ctx, &ListOptions{IncludeDeleted: true})
+	assert.True(t, errors.Is(err, ErrForbidden))
+	_, err = client.RestoreReservation(ctx, 1)
+	assert.True(t, errors.Is(err, ErrForbidden))
+
+	// Un token desconocido se rechaza
+	_, err = newTestClient(server.URL, "wrong").ListEmployees(ctx, nil)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.False(t, errors.Is(err, ErrServer))
+}
+
+func TestRetries(t *testing.T) {
+	flaky := &flakyServer{failures: 2}
+	server := httptest.NewServer(flaky.wrap(newAPI(nil)))
+	defer server.Close()
+	ctx := context.Background()
+	client := newTestClient(server.URL, "")
+
+	// Un GET se reintenta hasta que el servidor responde
+	_, err := client.GetUser(ctx, 0)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	assert.Len(t, flaky.attempts, 3)
+
+	// Un POST se reintenta con la misma clave de idempotencia
+	flaky.attempts, flaky.failures = nil, 2
+	_, err = client.RestoreConsultation(ctx, 1)
+	assert.True(t, errors.Is(err, ErrForbidden))
+	if assert.Len(t, flaky.attempts, 3) {
+		key := flaky.attempts[0].Header.Get(IdempotencyKeyHeader)
+		assert.NotEmpty(t, key)
+		assert.Equal(t, key, flaky.attempts[2].Header.Get(IdempotencyKeyHeader))
+	}
+
+	// La clave puede fijarse desde el contexto
+	flaky.attempts, flaky.failures = nil, 0
+	client.RestoreEmployee(WithIdempotencyKey(ctx, "restore-employee-1"), 1)
+	if assert.Len(t, flaky.attempts, 1) {
+		assert.Equal(t, "restore-employee-1", flaky.attempts[0].Header.Get(IdempotencyKeyHeader))
+	}
+
+	// Un PATCH no es idempotente y no se reintenta
+	flaky.attempts, flaky.failures = nil, 2
+	_, err = client.PatchUser(ctx, 1, map[string]interface{}{"first_name": "Jane"})
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Len(t, flaky.attempts, 1)
+	assert.Equal(t, "application/merge-patch+json", flaky.attempts[0].Header.Get("Content-Type"))
+
+	// Agotados los reintentos se devuelve el último error
+	flaky.attempts, flaky.failures = nil, 10
+	client.MaxRetries = 1
+	err = client.DeleteUser(ctx, 1)
+	assert.True(t, errors.Is(err, ErrServer))
+	assert.Len(t, flaky.attempts, 2)
+}
+
+func TestRetriesStopWhenContextEnds(t *testing.T) {
+	flaky := &flakyServer{failures: 100}
+	server := httptest.NewServer(flaky.wrap(http.NotFoundHandler()))
+	defer server.Close()
+	client := newTestClient(server.URL, "")
+	client.RetryWait = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.ListReservations(ctx, nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, flaky.attempts, 1)
+}
+
+func TestBackoff(t *testing.T) {
+	client := New("http://localhost:10000", "")
+	assert.Equal(t, 250*time.Millisecond, client.Backoff(1))
+	assert.Equal(t, 500*time.Millisecond, client.Backoff(2))
+	assert.Equal(t, 2*time.Second, client.Backoff(4))
+	assert.Equal(t, 5*time.Second, client.Backoff(10))
+}
+
+// TestClientAgainstDatabase recorre el ciclo de vida de un usuario contra la base de datos de
+// DATABASE_URL; sin ella la prueba se omite
+func TestClientAgainstDatabase(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.User{}, &models.Reservation{}, &models.Consultation{}, &models.AuditLog{}, &models.IdempotencyKey{})
+	defer db.DB.Exec("DELETE FROM users WHERE email = ?", "sdk@example.com")
+
+	server := httptest.NewServer(newAPI(middleware.Idempotency))
+	defer server.Close()
+	ctx := context.Background()
+	client := newTestClient(server.URL, "admin-token")
+
+	user, err := client.CreateUser(ctx, models.User{FirstName: "Ana", LastName: "Gómez", Email: "sdk@example.com"})
+	assert.NoError(t, err)
+	assert.NotZero(t, user.ID)
+
+	fetched, err := client.GetUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", fetched.FirstName)
+
+	// La versión leída viaja como If-Match: una segunda escritura con la misma versión se rechaza
+	fetched.FirstName = "Ana María"
+	updated, err := client.UpdateUser(ctx, user.ID, fetched)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana María", updated.FirstName)
+	_, err = client.UpdateUser(ctx, user.ID, fetched)
+	assert.True(t, errors.Is(err, ErrPreconditionFailed))
+
+	patched, err := client.PatchUser(ctx, user.ID, map[string]interface{}{"last_name": "Pérez"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Pérez", patched.LastName)
+
+	assert.NoError(t, client.DeleteUser(ctx, user.ID))
+	_, err = client.GetUser(ctx, user.ID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	restored, err := client.RestoreUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, restored.ID)
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// ConsultationFilter son los filtros de la bandeja de consultas
+type ConsultationFilter struct {
+	IncludeDeleted     bool
+	Status             string // open, pending, answered o closed
+	AssignedEmployeeID *uint
+	Unassigned         bool // Solo las consultas abiertas sin asignar
+	Overdue            bool // Solo las consultas con el plazo vencido
+}
+
+// ConsultationMessage es un mensaje nuevo para el hilo de una consulta
+type ConsultationMessage struct {
+	Author     string `json:"author"` // guest o staff; el personal autenticado siempre responde como staff
+	Body       string `json:"body"`
+	EmployeeID *uint  `json:"employee_id"`
+}
+
+// ListConsultations devuelve las consultas que cumplen el filtro; nil las devuelve todas
+func (c *Client) ListConsultations(ctx context.Context, filter *ConsultationFilter) ([]models.Consultation, error) {
+	opts := &ListOptions{}
+	if filter != nil {
+		opts.IncludeDeleted = filter.IncludeDeleted
+	}
+	query := opts.query()
+	if filter != nil {
+		if filter.Status != "" {
+			query.Set("status", filter.Status)
+		}
+		if filter.AssignedEmployeeID != nil {
+			query.Set("assigned_employee_id", strconv.FormatUint(uint64(*filter.AssignedEmployeeID), 10))
+		}
+		if filter.Unassigned {
+			query.Set("unassigned", "true")
+		}
+		if filter.Overdue {
+			query.Set("overdue", "true")
+		}
+	}
+	var consultations []models.Consultation
+	err := c.do(ctx, request{method: http.MethodGet, path: "/consultations", query: query}, &consultations)
+	return consultations, err
+}
+
+// GetConsultation devuelve una consulta con los mensajes de su hilo
+func (c *Client) GetConsultation(ctx context.Context, id uint) (models.Consultation, error) {
+	var consultation models.Consultation
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("consultations", id, "")}, &consultation)
+	return consultation, err
+}
+
+// CreateConsultation crea una consulta
+func (c *Client) CreateConsultation(ctx context.Context, consultation models.Consultation) (models.Consultation, error) {
+	var created models.Consultation
+	err := c.do(ctx, request{method: http.MethodPost, path: "/consultations", body: consultation}, &created)
+	return created, err
+}
+
+// UpdateConsultation modifica una consulta
+func (c *Client) UpdateConsultation(ctx context.Context, id uint, consultation models.Consultation) (models.Consultation, error) {
+	var updated models.Consultation
+	err := c.do(ctx, request{method: http.MethodPut, path: idPath("consultations", id, ""), body: consultation}, &updated)
+	return updated, err
+}
+
+// DeleteConsultation elimina una consulta
+func (c *Client) DeleteConsultation(ctx context.Context, id uint) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: idPath("consultations", id, "")}, nil)
+}
+
+// RestoreConsultation recupera una consulta eliminada; requiere el rol admin
+func (c *Client) RestoreConsultation(ctx context.Context, id uint) (models.Consultation, error) {
+	var consultation models.Consultation
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("consultations", id, "/restore")}, &consultation)
+	return consultation, err
+}
+
+// ListConsultationMessages devuelve los mensajes del hilo de una consulta
+func (c *Client) ListConsultationMessages(ctx context.Context, id uint) ([]models.ConsultationMessage, error) {
+	var messages []models.ConsultationMessage
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("consultations", id, "/messages")}, &messages)
+	return messages, err
+}
+
+// PostConsultationMessage agrega un mensaje al hilo y actualiza el estado de la consulta
+func (c *Client) PostConsultationMessage(ctx context.Context, id uint, message ConsultationMessage) (models.ConsultationMessage, error) {
+	var created models.ConsultationMessage
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("consultations", id, "/messages"), body: message}, &created)
+	return created, err
+}
+
+// AssignConsultation asigna la consulta a un empleado, o la deja sin asignar con nil; requiere el rol staff
+func (c *Client) AssignConsultation(ctx context.Context, id uint, employeeID *uint) (models.Consultation, error) {
+	var consultation models.Consultation
+	body := map[string]*uint{"employee_id": employeeID}
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("consultations", id, "/assign"), body: body}, &consultation)
+	return consultation, err
+}
+
+// SetConsultationStatus cambia el estado de la consulta; requiere el rol staff
+func (c *Client) SetConsultationStatus(ctx context.Context, id uint, status string) (models.Consultation, error) {
+	var consultation models.Consultation
+	body := map[string]string{"status": status}
+	err := c.do(ctx, request{method: http.MethodPut, path: idPath("consultations", id, "/status"), body: body}, &consultation)
+	return consultation, err
+}
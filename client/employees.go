@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// ListEmployees devuelve los empleados
+func (c *Client) ListEmployees(ctx context.Context, opts *ListOptions) ([]models.Employee, error) {
+	var employees []models.Employee
+	err := c.do(ctx, request{method: http.MethodGet, path: "/employees", query: opts.query()}, &employees)
+	return employees, err
+}
+
+// GetEmployee devuelve un empleado
+func (c *Client) GetEmployee(ctx context.Context, id uint) (models.Employee, error) {
+	var employee models.Employee
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("employees", id, "")}, &employee)
+	return employee, err
+}
+
+// CreateEmployee crea un empleado
+func (c *Client) CreateEmployee(ctx context.Context, employee models.Employee) (models.Employee, error) {
+	var created models.Employee
+	err := c.do(ctx, request{method: http.MethodPost, path: "/employees", body: employee}, &created)
+	return created, err
+}
+
+// UpdateEmployee reemplaza un empleado. Si employee.User.Version no es cero se envía como If-Match
+func (c *Client) UpdateEmployee(ctx context.Context, id uint, employee models.Employee) (models.Employee, error) {
+	var version uint
+	if employee.User != nil {
+		version = employee.User.Version
+	}
+	var updated models.Employee
+	err := c.do(ctx, request{method: http.MethodPut, path: idPath("employees", id, ""), body: employee, ifMatch: version}, &updated)
+	return updated, err
+}
+
+// PatchEmployee modifica solo los campos indicados con JSON Merge Patch
+func (c *Client) PatchEmployee(ctx context.Context, id uint, patch map[string]interface{}) (models.Employee, error) {
+	var updated models.Employee
+	err := c.do(ctx, request{method: http.MethodPatch, path: idPath("employees", id, ""), body: patch, contentType: mergePatchType}, &updated)
+	return updated, err
+}
+
+// DeleteEmployee elimina un empleado
+func (c *Client) DeleteEmployee(ctx context.Context, id uint) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: idPath("employees", id, "")}, nil)
+}
+
+// RestoreEmployee recupera un empleado eliminado; requiere el rol admin
+func (c *Client) RestoreEmployee(ctx context.Context, id uint) (models.Employee, error) {
+	var employee models.Employee
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("employees", id, "/restore")}, &employee)
+	return employee, err
+}
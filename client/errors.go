@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBody es la cantidad de bytes del cuerpo de un error que se conservan en el mensaje
+const maxErrorBody = 4096
+
+// APIError es una respuesta de error de la API. La API responde los errores con texto plano en
+// inglés, por ejemplo "User not found" o "Invalid request: path parameter id: must be an integer"
+type APIError struct {
+	StatusCode int
+	Message    string
+	Method     string
+	Path       string
+}
+
+func (e *APIError) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("hotel API returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: hotel API returned %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Is permite comparar con errors.Is contra los errores por estado (ErrNotFound, ErrConflict...).
+// ErrServer coincide con cualquier estado 5xx
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok || t.Method != "" {
+		return false
+	}
+	if t == ErrServer {
+		return e.StatusCode >= 500
+	}
+	return t.StatusCode == e.StatusCode
+}
+
+// Errores por estado para usar con errors.Is
+var (
+	ErrBadRequest         = &APIError{StatusCode: http.StatusBadRequest}          // Parámetros o cuerpo inválidos
+	ErrUnauthorized       = &APIError{StatusCode: http.StatusUnauthorized}        // Token desconocido
+	ErrForbidden          = &APIError{StatusCode: http.StatusForbidden}           // El token no tiene el rol requerido
+	ErrNotFound           = &APIError{StatusCode: http.StatusNotFound}            // El registro no existe o está eliminado
+	ErrConflict           = &APIError{StatusCode: http.StatusConflict}            // La operación choca con el estado actual, por ejemplo sin disponibilidad
+	ErrPreconditionFailed = &APIError{StatusCode: http.StatusPreconditionFailed}  // El registro cambió desde que se leyó (If-Match)
+	ErrUnprocessable      = &APIError{StatusCode: http.StatusUnprocessableEntity} // Clave de idempotencia reutilizada con otra solicitud
+	ErrServer             = &APIError{StatusCode: http.StatusInternalServerError} // Cualquier error 5xx
+)
+
+// newAPIError lee el mensaje de una respuesta de error
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: message, Method: method, Path: path}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// CancellationQuote es la penalización que corresponde si la reserva se cancela ahora
+type CancellationQuote struct {
+	Reservation models.Reservation         `json:"reservation"`
+	Policy      *models.CancellationPolicy `json:"policy,omitempty"`
+	FreeUntil   *time.Time                 `json:"free_until,omitempty"` // Hasta cuándo se cancela sin cargo
+	Penalty     float64                    `json:"penalty"`
+}
+
+// ListReservations devuelve las reservas
+func (c *Client) ListReservations(ctx context.Context, opts *ListOptions) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := c.do(ctx, request{method: http.MethodGet, path: "/reservations", query: opts.query()}, &reservations)
+	return reservations, err
+}
+
+// GetReservation devuelve una reserva
+func (c *Client) GetReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("reservations", id, "")}, &reservation)
+	return reservation, err
+}
+
+// CreateReservation crea una reserva. Si no hay disponibilidad la API responde ErrConflict
+func (c *Client) CreateReservation(ctx context.Context, reservation models.Reservation) (models.Reservation, error) {
+	var created models.Reservation
+	err := c.do(ctx, request{method: http.MethodPost, path: "/reservations", body: reservation}, &created)
+	return created, err
+}
+
+// UpdateReservation reemplaza una reserva. Si reservation.Version no es cero se envía como If-Match
+func (c *Client) UpdateReservation(ctx context.Context, id uint, reservation models.Reservation) (models.Reservation, error) {
+	var updated models.Reservation
+	err := c.do(ctx, request{method: http.MethodPut, path: idPath("reservations", id, ""), body: reservation, ifMatch: reservation.Version}, &updated)
+	return updated, err
+}
+
+// PatchReservation modifica solo los campos indicados con JSON Merge Patch
+func (c *Client) PatchReservation(ctx context.Context, id uint, patch map[string]interface{}) (models.Reservation, error) {
+	var updated models.Reservation
+	err := c.do(ctx, request{method: http.MethodPatch, path: idPath("reservations", id, ""), body: patch, contentType: mergePatchType}, &updated)
+	return updated, err
+}
+
+// DeleteReservation elimina una reserva
+func (c *Client) DeleteReservation(ctx context.Context, id uint) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: idPath("reservations", id, "")}, nil)
+}
+
+// RestoreReservation recupera una reserva eliminada; requiere el rol admin
+func (c *Client) RestoreReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("reservations", id, "/restore")}, &reservation)
+	return reservation, err
+}
+
+// GetCancellationQuote calcula la penalización por cancelar la reserva sin cancelarla
+func (c *Client) GetCancellationQuote(ctx context.Context, id uint) (CancellationQuote, error) {
+	var quote CancellationQuote
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("reservations", id, "/cancellation")}, &quote)
+	return quote, err
+}
+
+// CancelReservation cancela la reserva cobrando la penalización que corresponda
+func (c *Client) CancelReservation(ctx context.Context, id uint) (CancellationQuote, error) {
+	var quote CancellationQuote
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("reservations", id, "/cancel")}, &quote)
+	return quote, err
+}
+
+// ConfirmReservation confirma una reserva tentativa
+func (c *Client) ConfirmReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	var reservation models.Reservation
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("reservations", id, "/confirm")}, &reservation)
+	return reservation, err
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// mergePatchType es el tipo de contenido de las modificaciones parciales
+const mergePatchType = "application/merge-patch+json"
+
+// ListUsers devuelve los usuarios
+func (c *Client) ListUsers(ctx context.Context, opts *ListOptions) ([]models.User, error) {
+	var users []models.User
+	err := c.do(ctx, request{method: http.MethodGet, path: "/users", query: opts.query()}, &users)
+	return users, err
+}
+
+// GetUser devuelve un usuario con sus reservas y consultas
+func (c *Client) GetUser(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	err := c.do(ctx, request{method: http.MethodGet, path: idPath("users", id, "")}, &user)
+	return user, err
+}
+
+// CreateUser crea un usuario
+func (c *Client) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	var created models.User
+	err := c.do(ctx, request{method: http.MethodPost, path: "/users", body: user}, &created)
+	return created, err
+}
+
+// UpdateUser reemplaza un usuario. Si user.Version no es cero se envía como If-Match y la API
+// responde ErrPreconditionFailed si otro cliente lo modificó desde que se leyó
+func (c *Client) UpdateUser(ctx context.Context, id uint, user models.User) (models.User, error) {
+	var updated models.User
+	err := c.do(ctx, request{method: http.MethodPut, path: idPath("users", id, ""), body: user, ifMatch: user.Version}, &updated)
+	return updated, err
+}
+
+// PatchUser modifica solo los campos indicados con JSON Merge Patch; un valor nil borra el campo
+func (c *Client) PatchUser(ctx context.Context, id uint, patch map[string]interface{}) (models.User, error) {
+	var updated models.User
+	err := c.do(ctx, request{method: http.MethodPatch, path: idPath("users", id, ""), body: patch, contentType: mergePatchType}, &updated)
+	return updated, err
+}
+
+// DeleteUser elimina un usuario
+func (c *Client) DeleteUser(ctx context.Context, id uint) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: idPath("users", id, "")}, nil)
+}
+
+// RestoreUser recupera un usuario eliminado; requiere el rol admin
+func (c *Client) RestoreUser(ctx context.Context, id uint) (models.User, error) {
+	var user models.User
+	err := c.do(ctx, request{method: http.MethodPost, path: idPath("users", id, "/restore")}, &user)
+	return user, err
+}
@@ -0,0 +1,211 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Tipos de evento de dominio
+const (
+	ReservationCreated   = "ReservationCreated"
+	ReservationModified  = "ReservationModified"
+	ReservationConfirmed = "ReservationConfirmed"
+	ReservationCancelled = "ReservationCancelled"
+	ReservationDeleted   = "ReservationDeleted" // Eliminación lógica; puede recuperarse hasta la purga
+	GuestCheckedIn       = "GuestCheckedIn"
+	GuestCheckedOut      = "GuestCheckedOut"
+	ConsultationOpened   = "ConsultationOpened"
+	ConsultationAnswered = "ConsultationAnswered"
+	ConsultationUpdated  = "ConsultationUpdated" // Asignación o cambio de estado
+	RoomStatusChanged    = "RoomStatusChanged"   // Cambio de estado de servicio o de limpieza
+)
+
+// Types son todos los tipos de evento publicados
+var Types = []string{
+	ReservationCreated, ReservationModified, ReservationConfirmed, ReservationCancelled, ReservationDeleted,
+	GuestCheckedIn, GuestCheckedOut, ConsultationOpened, ConsultationAnswered, ConsultationUpdated,
+	RoomStatusChanged,
+}
+
+// Known indica si el tipo de evento existe
+func Known(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipos de agregado
+const (
+	AggregateReservation  = "reservation"
+	AggregateConsultation = "consultation"
+	AggregateRoom         = "room"
+)
+
+// All suscribe un manejador a todos los tipos de evento
+const All = "*"
+
+// MaxAttempts es la cantidad de intentos antes de marcar un evento como fallido
+const MaxAttempts = 8
+
+// ConsultationPayload es el contenido de los eventos de una consulta
+type ConsultationPayload struct {
+	Consultation models.Consultation `json:"consultation"`
+	Message      string              `json:"message,omitempty"` // Mensaje que originó el evento
+}
+
+// Handler procesa un evento dentro de la transacción en la que se marca como procesado. Un evento
+// puede entregarse más de una vez, así que los manejadores deben ser idempotentes
+type Handler func(tx *gorm.DB, event models.DomainEvent) error
+
+type subscription struct {
+	name      string
+	eventType string
+	handler   Handler
+}
+
+var (
+	mu            sync.RWMutex
+	subscriptions []subscription
+)
+
+// Subscribe registra un manejador para el tipo de evento indicado, o para todos con All. El nombre
+// identifica al suscriptor para registrar qué eventos ya procesó
+func Subscribe(eventType, name string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	subscriptions = append(subscriptions, subscription{name: name, eventType: eventType, handler: handler})
+}
+
+// subscribers devuelve las suscripciones al tipo de evento indicado
+func subscribers(eventType string) []subscription {
+	mu.RLock()
+	defer mu.RUnlock()
+	var matching []subscription
+	for _, s := range subscriptions {
+		if s.eventType == eventType || s.eventType == All {
+			matching = append(matching, s)
+		}
+	}
+	return matching
+}
+
+// Publish guarda el evento en la bandeja de salida dentro de la transacción indicada, de modo que
+// solo se despacha si el cambio de estado se confirma
+func Publish(tx *gorm.DB, eventType, aggregateType string, aggregateID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	event := models.DomainEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(data),
+		Status:        models.EventPending,
+		NextAttemptAt: time.Now(),
+	}
+	return tx.Create(&event).Error
+}
+
+// PublishReservation publica un evento de la reserva con su estado actual
+func PublishReservation(tx *gorm.DB, eventType string, reservation models.Reservation) error {
+	return Publish(tx, eventType, AggregateReservation, reservation.ID, reservation)
+}
+
+// PublishConsultation publica un evento de la consulta con su estado actual y el mensaje que lo originó
+func PublishConsultation(tx *gorm.DB, eventType string, consultation models.Consultation, message string) error {
+	consultation.Messages = nil
+	return Publish(tx, eventType, AggregateConsultation, consultation.ID, ConsultationPayload{Consultation: consultation, Message: message})
+}
+
+// PublishRoom publica un evento de la habitación con su estado actual
+func PublishRoom(tx *gorm.DB, eventType string, room models.Room) error {
+	return Publish(tx, eventType, AggregateRoom, room.ID, room)
+}
+
+// Decode interpreta el contenido del evento
+func Decode(event models.DomainEvent, payload interface{}) error {
+	return json.Unmarshal([]byte(event.Payload), payload)
+}
+
+// Backoff devuelve la espera antes del siguiente intento: 30 segundos, 1, 2, 4... minutos
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return time.Duration(1<<uint(attempts-1)) * 30 * time.Second
+}
+
+// handle entrega el evento a los suscriptores que todavía no lo procesaron. Cada manejador corre en
+// su propio punto de guardado, así que el fallo de uno no deshace lo que hicieron los demás
+func handle(tx *gorm.DB, event *models.DomainEvent) error {
+	var failures []error
+	for _, s := range subscribers(event.Type) {
+		if event.HandledBy(s.name) {
+			continue
+		}
+		err := tx.Transaction(func(sp *gorm.DB) error {
+			return s.handler(sp, *event)
+		})
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		event.MarkHandled(s.name)
+	}
+	return errors.Join(failures...)
+}
+
+// Dispatch entrega a los suscriptores los eventos pendientes cuyo próximo intento ya venció, en orden
+// de creación. Cada evento se bloquea mientras se procesa para que dos procesos no lo despachen a la vez
+func Dispatch(db *gorm.DB, now time.Time) (int, error) {
+	var ids []uint
+	if err := db.Model(&models.DomainEvent{}).
+		Where("status = ? AND next_attempt_at <= ?", models.EventPending, now).
+		Order("id asc").Limit(100).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var event models.DomainEvent
+			result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("status = ?", models.EventPending).Limit(1).Find(&event, id)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+
+			event.Attempts++
+			if err := handle(tx, &event); err != nil {
+				event.LastError = err.Error()
+				event.NextAttemptAt = now.Add(Backoff(event.Attempts))
+				if event.Attempts >= MaxAttempts {
+					event.Status = models.EventFailed
+				}
+				log.Printf("event %d %s failed (attempt %d): %v", event.ID, event.Type, event.Attempts, err)
+			} else {
+				event.Status = models.EventDispatched
+				event.DispatchedAt = &now
+				event.LastError = ""
+				dispatched++
+			}
+			return tx.Save(&event).Error
+		})
+		if err != nil {
+			return dispatched, err
+		}
+	}
+	return dispatched, nil
+}
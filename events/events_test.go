@@ -0,0 +1,63 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSubscribers(t *testing.T) {
+	defer func(saved []subscription) { subscriptions = saved }(subscriptions)
+	subscriptions = nil
+
+	noop := func(tx *gorm.DB, event models.DomainEvent) error { return nil }
+	Subscribe(ReservationCreated, "notifications", noop)
+	Subscribe(All, "webhooks", noop)
+
+	var names []string
+	for _, s := range subscribers(ReservationCreated) {
+		names = append(names, s.name)
+	}
+	assert.Equal(t, []string{"notifications", "webhooks"}, names)
+
+	// Los suscriptores a todos los eventos reciben también los que nadie más escucha
+	matching := subscribers(GuestCheckedIn)
+	assert.Len(t, matching, 1)
+	assert.Equal(t, "webhooks", matching[0].name)
+}
+
+func TestHandledBy(t *testing.T) {
+	event := models.DomainEvent{}
+	assert.False(t, event.HandledBy("notifications"))
+
+	event.MarkHandled("notifications")
+	event.MarkHandled("webhooks")
+	event.MarkHandled("notifications")
+	assert.Equal(t, "notifications,webhooks", event.Handled)
+	assert.True(t, event.HandledBy("webhooks"))
+	assert.False(t, event.HandledBy("hooks"))
+}
+
+func TestDecode(t *testing.T) {
+	consultation := models.Consultation{Consultation: "¿Tienen cuna?", Status: models.ConsultationOpen}
+	consultation.ID = 4
+	data, err := json.Marshal(ConsultationPayload{Consultation: consultation, Message: "Sí"})
+	assert.NoError(t, err)
+
+	var payload ConsultationPayload
+	assert.NoError(t, Decode(models.DomainEvent{Payload: string(data)}, &payload))
+	assert.Equal(t, uint(4), payload.Consultation.ID)
+	assert.Equal(t, "¿Tienen cuna?", payload.Consultation.Consultation)
+	assert.Equal(t, "Sí", payload.Message)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(0))
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 4*time.Minute, Backoff(4))
+}
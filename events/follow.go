@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// FollowInterval es cada cuánto Follow busca eventos nuevos en la bandeja de salida
+var FollowInterval = time.Second
+
+// Los ids de los eventos se asignan al insertarlos, pero las transacciones se confirman en otro orden:
+// un evento con id menor puede hacerse visible después de uno mayor, y retomar con "id > afterID" lo
+// saltaría. Por eso la bandeja de salida se lee en el orden (tx_id, id) y solo hasta los eventos de
+// transacciones anteriores a la más antigua que sigue abierta (txid_snapshot_xmin): ningún evento que
+// se confirme después puede quedar antes de ese punto. Una transacción larga demora el stream, pero no
+// se pierde ningún evento
+const confirmed = "tx_id < txid_snapshot_xmin(txid_current_snapshot())"
+
+// position es el lugar de un evento en el orden de lectura de la bandeja de salida
+type position struct {
+	TxID uint64
+	ID   uint
+}
+
+// positionOf devuelve la posición del evento afterID. Si ese evento no existe se usa la del último
+// evento con un id menor, y el comienzo de la bandeja de salida si no hay ninguno
+func positionOf(db *gorm.DB, afterID uint) (position, error) {
+	var pos position
+	if afterID == 0 {
+		return pos, nil
+	}
+	err := db.Model(&models.DomainEvent{}).Select("tx_id, id").Where("id <= ?", afterID).
+		Order("id desc").Limit(1).Scan(&pos).Error
+	return pos, err
+}
+
+// since devuelve, en orden, hasta limit eventos confirmados posteriores a la posición indicada
+func since(db *gorm.DB, after position, limit int, aggregateTypes ...string) ([]models.DomainEvent, error) {
+	conn := db.Where("(tx_id, id) > (?, ?)", after.TxID, after.ID).Where(confirmed)
+	if len(aggregateTypes) > 0 {
+		conn = conn.Where("aggregate_type IN ?", aggregateTypes)
+	}
+	var found []models.DomainEvent
+	err := conn.Order("tx_id asc, id asc").Limit(limit).Find(&found).Error
+	return found, err
+}
+
+// Since devuelve, en orden, hasta limit eventos posteriores a afterID de los tipos de agregado indicados
+// (de todos si no se indica ninguno)
+func Since(db *gorm.DB, afterID uint, limit int, aggregateTypes ...string) ([]models.DomainEvent, error) {
+	after, err := positionOf(db, afterID)
+	if err != nil {
+		return nil, err
+	}
+	return since(db, after, limit, aggregateTypes...)
+}
+
+// LastID devuelve el id del último evento que ya puede leerse en orden, o 0 si no hay ninguno. Los
+// eventos posteriores, incluidos los de transacciones que aún no se confirmaron, los entrega Since
+func LastID(db *gorm.DB) (uint, error) {
+	var id uint
+	err := db.Model(&models.DomainEvent{}).Select("id").Where(confirmed).
+		Order("tx_id desc, id desc").Limit(1).Scan(&id).Error
+	return id, err
+}
+
+// Follow entrega a fn, en orden, los eventos posteriores a afterID de los tipos de agregado indicados y
+// sigue esperando eventos nuevos hasta que se cancele el contexto o fn devuelva un error. Lee la bandeja
+// de salida, así que recibe los cambios confirmados por cualquier instancia de la API, se hayan
+// despachado o no a los suscriptores
+func Follow(ctx context.Context, db *gorm.DB, afterID uint, aggregateTypes []string, fn func(models.DomainEvent) error) error {
+	after, err := positionOf(db.WithContext(ctx), afterID)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	for {
+		found, err := since(db.WithContext(ctx), after, 100, aggregateTypes...)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		for _, event := range found {
+			if err := fn(event); err != nil {
+				return err
+			}
+			after = position{TxID: event.TxID, ID: event.ID}
+		}
+		// Una página completa indica que quedan eventos: se piden sin esperar
+		if len(found) == 100 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
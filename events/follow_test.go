@@ -0,0 +1,54 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinceWaitsForEarlierTransactions(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.DomainEvent{})
+	defer db.DB.Exec("DELETE FROM domain_events")
+
+	lastID, err := LastID(db.DB)
+	assert.NoError(t, err)
+
+	// La primera transacción guarda su evento (id menor) y sigue abierta mientras la segunda se confirma
+	first := db.DB.Begin()
+	defer first.Rollback()
+	assert.NoError(t, Publish(first, ReservationCreated, AggregateReservation, 1, map[string]int{"id": 1}))
+	second := db.DB.Begin()
+	assert.NoError(t, Publish(second, ReservationCreated, AggregateReservation, 2, map[string]int{"id": 2}))
+	assert.NoError(t, second.Commit().Error)
+
+	// Entregar el evento de la segunda haría que un cliente que retoma desde él se salte el de la primera
+	found, err := Since(db.DB, lastID, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+	pending, err := LastID(db.DB)
+	assert.NoError(t, err)
+	assert.Equal(t, lastID, pending)
+
+	assert.NoError(t, first.Commit().Error)
+	found, err = Since(db.DB, lastID, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, uint(1), found[0].AggregateID)
+		assert.Equal(t, uint(2), found[1].AggregateID)
+		assert.Less(t, found[0].ID, found[1].ID)
+
+		// Retomar desde el primero entrega el segundo, y desde el segundo ya no queda nada
+		rest, err := Since(db.DB, found[0].ID, 100)
+		assert.NoError(t, err)
+		assert.Len(t, rest, 1)
+		rest, err = Since(db.DB, found[1].ID, 100)
+		assert.NoError(t, err)
+		assert.Empty(t, rest)
+	}
+}
@@ -0,0 +1,192 @@
+// Package export escribe tablas en CSV o en Excel (xlsx) fila por fila, para exportar listados
+// grandes sin tenerlos completos en memoria
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Formatos de exportación
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// ErrUnknownFormat indica que el formato pedido no es csv ni xlsx
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// contentTypes es el tipo de contenido de cada formato
+var contentTypes = map[string]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ContentType devuelve el tipo de contenido del formato
+func ContentType(format string) (string, error) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return "", ErrUnknownFormat
+	}
+	return contentType, nil
+}
+
+// Writer escribe las filas de una tabla. Los valores pueden ser textos, números, booleanos, fechas o
+// punteros a ellos; un puntero nulo deja la celda vacía
+type Writer interface {
+	Write(row []interface{}) error
+	// Close termina el archivo. Hasta entonces una planilla xlsx no está completa
+	Close() error
+}
+
+// NewWriter crea un Writer del formato indicado que escribe en out la fila de encabezados y luego las
+// filas que se le pasen. sheet es el nombre de la hoja en xlsx
+func NewWriter(format string, out io.Writer, sheet string, header []string) (Writer, error) {
+	row := make([]interface{}, len(header))
+	for i, name := range header {
+		row[i] = name
+	}
+
+	var writer Writer
+	switch format {
+	case CSV:
+		writer = &csvWriter{csv: csv.NewWriter(out)}
+	case XLSX:
+		xlsx, err := newXLSXWriter(out, sheet)
+		if err != nil {
+			return nil, err
+		}
+		writer = xlsx
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err := writer.Write(row); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// deref reemplaza los punteros por el valor al que apuntan, o nil si son nulos
+func deref(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return value
+}
+
+// csvWriter escribe CSV. El paquete csv usa un buffer, así que las filas se envían al cliente a
+// medida que se llena en lugar de al final
+type csvWriter struct {
+	csv    *csv.Writer
+	record []string
+}
+
+func (w *csvWriter) Write(row []interface{}) error {
+	w.record = w.record[:0]
+	for _, value := range row {
+		w.record = append(w.record, csvCell(deref(value)))
+	}
+	return w.csv.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// csvCell convierte el valor en texto. Los textos que una planilla interpretaría como fórmula llevan
+// un apóstrofo delante, porque pueden venir de los huéspedes
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// xlsxWriter escribe una planilla con el StreamWriter de excelize, que pasa a un archivo temporal las
+// filas que no entran en su buffer. El archivo se envía completo al cerrarlo
+type xlsxWriter struct {
+	out       io.Writer
+	file      *excelize.File
+	stream    *excelize.StreamWriter
+	dateStyle int
+	rows      int
+}
+
+func newXLSXWriter(out io.Writer, sheet string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// Formato 22 de Excel: fecha y hora
+	dateStyle, err := file.NewStyle(&excelize.Style{NumFmt: 22})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: file, stream: stream, dateStyle: dateStyle}, nil
+}
+
+func (w *xlsxWriter) Write(row []interface{}) error {
+	cells := make([]interface{}, len(row))
+	for i, value := range row {
+		value = deref(value)
+		if t, ok := value.(time.Time); ok {
+			value = excelize.Cell{StyleID: w.dateStyle, Value: t}
+		}
+		cells[i] = value
+	}
+	w.rows++
+	cell, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err != nil {
+		return err
+	}
+	return w.stream.SetRow(cell, cells)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
@@ -0,0 +1,59 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func writeTable(t *testing.T, format string) *bytes.Buffer {
+	var out bytes.Buffer
+	writer, err := NewWriter(format, &out, "reservations", []string{"id", "guest_name", "check_in", "rate_plan_id", "cancellation_fee"})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	ratePlan := uint(3)
+	checkin := time.Date(2030, 6, 1, 14, 0, 0, 0, time.UTC)
+	assert.NoError(t, writer.Write([]interface{}{uint(1), "Grace Hopper", checkin, &ratePlan, 12.5}))
+	assert.NoError(t, writer.Write([]interface{}{uint(2), "=HYPERLINK(\"http://example.com\")", checkin, (*uint)(nil), 0.0}))
+	assert.NoError(t, writer.Close())
+	return &out
+}
+
+func TestCSV(t *testing.T) {
+	out := writeTable(t, CSV)
+	assert.Equal(t, "id,guest_name,check_in,rate_plan_id,cancellation_fee\n"+
+		"1,Grace Hopper,2030-06-01T14:00:00Z,3,12.5\n"+
+		"2,\"'=HYPERLINK(\"\"http://example.com\"\")\",2030-06-01T14:00:00Z,,0\n", out.String())
+}
+
+func TestXLSX(t *testing.T) {
+	out := writeTable(t, XLSX)
+	file, err := excelize.OpenReader(out)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer file.Close()
+	rows, err := file.GetRows("reservations")
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, []string{"id", "guest_name", "check_in", "rate_plan_id", "cancellation_fee"}, rows[0])
+		assert.Equal(t, "Grace Hopper", rows[1][1])
+		assert.Equal(t, "3", rows[1][3])
+		// Las fórmulas se guardan como texto
+		assert.Equal(t, "=HYPERLINK(\"http://example.com\")", rows[2][1])
+		assert.Equal(t, "", rows[2][3])
+	}
+	formula, _ := file.GetCellFormula("reservations", "B3")
+	assert.Equal(t, "", formula)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{}, "users", []string{"id"})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	_, err = ContentType("pdf")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
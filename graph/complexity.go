@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultListSize es la cantidad de elementos que se estima para una lista sin argumento limit
+const DefaultListSize = 10
+
+// MaxListSize es el mayor valor del argumento limit que se tiene en cuenta al estimar una lista
+const MaxListSize = 100
+
+// Cost es el tamaño estimado de una operación
+type Cost struct {
+	Depth      int // Niveles de campos anidados
+	Complexity int // Campos que se resolverían, multiplicando los de cada lista por su tamaño
+}
+
+// Analyze estima el costo de la operación antes de ejecutarla. Cada campo cuenta 1 y los campos de
+// una lista se multiplican por su argumento limit (el indicado, el valor por defecto del esquema o
+// DefaultListSize si la lista no tiene limit)
+func Analyze(schema *graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}) Cost {
+	a := analyzer{schema: schema, variables: variables, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			a.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var root *graphql.Object
+	switch operation.Operation {
+	case ast.OperationTypeMutation:
+		root = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = schema.SubscriptionType()
+	default:
+		root = schema.QueryType()
+	}
+	complexity, depth := a.selectionSet(root, operation.SelectionSet, map[string]bool{})
+	return Cost{Depth: depth, Complexity: complexity}
+}
+
+// analyzer recorre la consulta junto con los tipos del esquema
+type analyzer struct {
+	schema    *graphql.Schema
+	variables map[string]interface{}
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// selectionSet devuelve la complejidad y la profundidad de los campos pedidos sobre el tipo indicado.
+// visited corta los ciclos de fragmentos, que la validación ya rechaza
+func (a analyzer) selectionSet(parent graphql.Type, set *ast.SelectionSet, visited map[string]bool) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+	complexity, depth := 0, 0
+	for _, selection := range set.Selections {
+		var c, d int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c, d = a.field(parent, selection, visited)
+		case *ast.InlineFragment:
+			typ := parent
+			if selection.TypeCondition != nil {
+				typ = a.schema.Type(selection.TypeCondition.Name.Value)
+			}
+			c, d = a.selectionSet(typ, selection.SelectionSet, visited)
+		case *ast.FragmentSpread:
+			fragment, ok := a.fragments[selection.Name.Value]
+			if !ok || visited[fragment.Name.Value] {
+				continue
+			}
+			visited[fragment.Name.Value] = true
+			c, d = a.selectionSet(a.schema.Type(fragment.TypeCondition.Name.Value), fragment.SelectionSet, visited)
+			delete(visited, fragment.Name.Value)
+		}
+		complexity += c
+		if d > depth {
+			depth = d
+		}
+	}
+	return complexity, depth
+}
+
+func (a analyzer) field(parent graphql.Type, field *ast.Field, visited map[string]bool) (int, int) {
+	// Los campos de introspección y los desconocidos no tienen definición: cuentan sin multiplicar
+	var definition *graphql.FieldDefinition
+	if object, ok := parent.(*graphql.Object); ok {
+		definition = object.Fields()[field.Name.Value]
+	}
+	if definition == nil {
+		children, depth := a.selectionSet(nil, field.SelectionSet, visited)
+		return 1 + children, 1 + depth
+	}
+
+	typ, list := unwrap(definition.Type)
+	children, depth := a.selectionSet(typ, field.SelectionSet, visited)
+	if list {
+		children *= a.listSize(definition, field)
+	}
+	return 1 + children, 1 + depth
+}
+
+// listSize es el argumento limit del campo, o su valor por defecto, entre 1 y MaxListSize. Un limit
+// fuera de ese rango lo rechaza el resolver, pero no puede abaratar la estimación
+func (a analyzer) listSize(definition *graphql.FieldDefinition, field *ast.Field) int {
+	size := a.limit(definition, field)
+	if size < 1 {
+		return 1
+	}
+	if size > MaxListSize {
+		return MaxListSize
+	}
+	return size
+}
+
+// limit es el argumento limit del campo, o su valor por defecto
+func (a analyzer) limit(definition *graphql.FieldDefinition, field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			if size, err := strconv.Atoi(value.Value); err == nil {
+				return size
+			}
+		case *ast.Variable:
+			if size, ok := a.variables[value.Name.Value].(float64); ok {
+				return int(size)
+			}
+			if size, ok := a.variables[value.Name.Value].(int); ok {
+				return size
+			}
+		}
+	}
+	for _, argument := range definition.Args {
+		if argument.PrivateName == "limit" {
+			if size, ok := argument.DefaultValue.(int); ok {
+				return size
+			}
+		}
+	}
+	return DefaultListSize
+}
+
+// unwrap quita los NonNull y las listas de un tipo e indica si era una lista
+func unwrap(typ graphql.Type) (graphql.Type, bool) {
+	list := false
+	for {
+		switch t := typ.(type) {
+		case *graphql.NonNull:
+			typ = t.OfType
+		case *graphql.List:
+			typ = t.OfType
+			list = true
+		default:
+			return typ, list
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package graph ejecuta consultas GraphQL con límites de profundidad y complejidad, y agrupa las
+// cargas de los resolvers con loaders por solicitud
+package graph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request es una solicitud GraphQL, en el cuerpo de un POST o en los parámetros de un GET
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Options son los límites que se aplican a cada operación antes de ejecutarla
+type Options struct {
+	MaxDepth      int
+	MaxComplexity int
+	QueryOnly     bool // Rechazar las mutaciones, por ejemplo en las solicitudes GET
+}
+
+// OptionsFromEnv lee los límites de GRAPHQL_MAX_DEPTH (10 por defecto) y GRAPHQL_MAX_COMPLEXITY
+// (5000 por defecto)
+func OptionsFromEnv() Options {
+	return Options{
+		MaxDepth:      envInt("GRAPHQL_MAX_DEPTH", 10),
+		MaxComplexity: envInt("GRAPHQL_MAX_COMPLEXITY", 5000),
+	}
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Execute analiza la operación, la rechaza si supera los límites y la ejecuta. Los errores de sintaxis,
+// de validación y de límites se devuelven en Errors sin datos, como en cualquier servidor GraphQL
+func Execute(ctx context.Context, schema graphql.Schema, request Request, options Options) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	operation := findOperation(doc, request.OperationName)
+	if operation == nil && request.OperationName == "" {
+		return errorResult("operationName is required when the document has several operations")
+	}
+	if operation == nil {
+		return errorResult("unknown operation %q", request.OperationName)
+	}
+	if options.QueryOnly && operation.Operation != ast.OperationTypeQuery {
+		return errorResult("%s operations are only allowed with POST", operation.Operation)
+	}
+	cost := Analyze(&schema, doc, operation, request.Variables)
+	if options.MaxDepth > 0 && cost.Depth > options.MaxDepth {
+		return errorResult("query depth %d exceeds the limit of %d", cost.Depth, options.MaxDepth)
+	}
+	if options.MaxComplexity > 0 && cost.Complexity > options.MaxComplexity {
+		return errorResult("query complexity %d exceeds the limit of %d", cost.Complexity, options.MaxComplexity)
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+}
+
+// findOperation busca la operación con el nombre indicado; sin nombre el documento debe tener una sola
+func findOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = operation
+			continue
+		}
+		if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+	return found
+}
+
+func errorResult(format string, args ...interface{}) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(fmt.Sprintf(format, args...))}}
+}
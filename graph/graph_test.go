@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// guest es un huésped de prueba; sus reservas se cargan con un loader
+type guest struct {
+	ID   uint
+	Name string
+}
+
+type stay struct {
+	ID      uint
+	GuestID uint
+	Room    string
+}
+
+type loadersKey struct{}
+
+// testSchema arma un esquema con huéspedes y reservas; las reservas se cargan con el loader del contexto
+func testSchema() graphql.Schema {
+	var guestType *graphql.Object
+	stayType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stay",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"room": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(stay).Room, nil
+				}},
+				"guest": &graphql.Field{Type: guestType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return guest{ID: p.Source.(stay).GuestID}, nil
+				}},
+			}
+		}),
+	})
+	guestType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Guest",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(guest).Name, nil
+			}},
+			"stays": &graphql.Field{Type: graphql.NewList(stayType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				loader := p.Context.Value(loadersKey{}).(*Loader)
+				return loader.Load(p.Source.(guest).ID), nil
+			}},
+		},
+	})
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"guests": &graphql.Field{
+				Type: graphql.NewList(guestType),
+				Args: graphql.FieldConfigArgument{"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []guest{{1, "Ana"}, {2, "Luis"}, {3, "Eva"}}, nil
+				},
+			},
+			"names": &graphql.Field{Type: graphql.NewList(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return []string{"Ana"}, nil
+			}},
+		},
+	})
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"checkIn": &graphql.Field{Type: graphql.Boolean, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return true, nil
+			}},
+		},
+	})
+	schema, _ := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	return schema
+}
+
+// newStayLoader carga las reservas de los huéspedes; fetches cuenta las llamadas
+func newStayLoader(fetches *int) *Loader {
+	return NewLoader(func(keys []uint) (map[uint]interface{}, error) {
+		*fetches++
+		values := make(map[uint]interface{})
+		for _, key := range keys {
+			if key == 2 {
+				values[key] = []stay{}
+				continue
+			}
+			values[key] = []stay{{ID: key * 10, GuestID: key, Room: "10" + string(rune('0'+key))}}
+		}
+		return values, nil
+	})
+}
+
+func TestLoaderBatchesOneLevel(t *testing.T) {
+	fetches := 0
+	schema := testSchema()
+	ctx := context.WithValue(context.Background(), loadersKey{}, newStayLoader(&fetches))
+
+	result := Execute(ctx, schema, Request{Query: "{ guests { name stays { room } } }"}, Options{})
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, fetches)
+	guests := result.Data.(map[string]interface{})["guests"].([]interface{})
+	assert.Len(t, guests, 3)
+	assert.Equal(t, []interface{}{map[string]interface{}{"room": "101"}}, guests[0].(map[string]interface{})["stays"])
+	assert.Equal(t, []interface{}{}, guests[1].(map[string]interface{})["stays"])
+
+	// Los valores ya cargados no se vuelven a pedir
+	result = Execute(ctx, schema, Request{Query: "{ guests { stays { room guest { stays { room } } } } }"}, Options{})
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1, fetches)
+}
+
+func TestLoaderErrors(t *testing.T) {
+	loader := NewLoader(func(keys []uint) (map[uint]interface{}, error) {
+		return nil, errors.New("database unavailable")
+	})
+	first, second := loader.Load(1), loader.Load(2)
+	_, err := first()
+	assert.EqualError(t, err, "database unavailable")
+	_, err = second()
+	assert.EqualError(t, err, "database unavailable")
+}
+
+func TestAnalyze(t *testing.T) {
+	schema := testSchema()
+	cost := func(query string, variables map[string]interface{}) Cost {
+		doc, err := parser.Parse(parser.ParseParams{Source: query})
+		assert.NoError(t, err)
+		return Analyze(&schema, doc, doc.Definitions[0].(*ast.OperationDefinition), variables)
+	}
+
+	// guests usa el limit por defecto (20) y stays, sin limit, DefaultListSize
+	assert.Equal(t, Cost{Depth: 2, Complexity: 1 + 20*1}, cost("{ guests { name } }", nil))
+	assert.Equal(t, Cost{Depth: 3, Complexity: 1 + 5*(1+1+10*1)}, cost("{ guests(limit: 5) { name stays { room } } }", nil))
+	assert.Equal(t, Cost{Depth: 3, Complexity: 1 + 2*(1+10)}, cost("query($n: Int) { guests(limit: $n) { stays { room } } }", map[string]interface{}{"n": float64(2)}))
+
+	// Un limit fuera de rango no abarata la estimación
+	assert.Equal(t, Cost{Depth: 2, Complexity: 1 + 1}, cost("{ guests(limit: -5) { name } }", nil))
+	assert.Equal(t, Cost{Depth: 2, Complexity: 1 + 100}, cost("{ guests(limit: 100000) { name } }", nil))
+
+	// Los fragmentos cuentan como si los campos estuvieran escritos en su lugar
+	assert.Equal(t, cost("{ guests(limit: 5) { name stays { room } } }", nil),
+		cost("{ guests(limit: 5) { ...info } } fragment info on Guest { name stays { room } }", nil))
+	assert.Equal(t, Cost{Depth: 1, Complexity: 2}, cost("{ __typename names }", nil))
+}
+
+func TestExecuteLimits(t *testing.T) {
+	fetches := 0
+	schema := testSchema()
+	ctx := context.WithValue(context.Background(), loadersKey{}, newStayLoader(&fetches))
+	deep := "{ guests { stays { guest { stays { room } } } } }"
+
+	result := Execute(ctx, schema, Request{Query: deep}, Options{MaxDepth: 4})
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "query depth 5 exceeds the limit of 4", result.Errors[0].Message)
+	}
+
+	result = Execute(ctx, schema, Request{Query: deep}, Options{MaxComplexity: 100})
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "query complexity 2421 exceeds the limit of 100", result.Errors[0].Message)
+	}
+	assert.Equal(t, 0, fetches)
+
+	result = Execute(ctx, schema, Request{Query: "mutation { checkIn }"}, Options{QueryOnly: true})
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "mutation operations are only allowed with POST", result.Errors[0].Message)
+	}
+	result = Execute(ctx, schema, Request{Query: "mutation { checkIn }"}, Options{})
+	assert.Empty(t, result.Errors)
+
+	// Los errores de sintaxis y de validación se devuelven sin ejecutar
+	result = Execute(ctx, schema, Request{Query: "{ guests { "}, Options{})
+	assert.NotEmpty(t, result.Errors)
+	result = Execute(ctx, schema, Request{Query: "{ rooms }"}, Options{})
+	if assert.Len(t, result.Errors, 1) {
+		assert.Contains(t, result.Errors[0].Message, `Cannot query field "rooms"`)
+	}
+	result = Execute(ctx, schema, Request{Query: "query a { names } query b { names }"}, Options{})
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "operationName is required when the document has several operations", result.Errors[0].Message)
+	}
+	result = Execute(ctx, schema, Request{Query: "query a { names } query b { names }", OperationName: "b"}, Options{})
+	assert.Empty(t, result.Errors)
+}
@@ -0,0 +1,75 @@
+package graph
+
+import "sync"
+
+// BatchFunc carga de una sola vez los valores de varias claves. Las claves sin valor quedan en nil
+type BatchFunc func(keys []uint) (map[uint]interface{}, error)
+
+// Loader agrupa las cargas pedidas por los resolvers de un mismo nivel de la consulta en una sola
+// llamada a BatchFunc y guarda los resultados para el resto de la solicitud. Evita el problema N+1:
+// listar 50 usuarios con sus reservas hace dos consultas a la base de datos y no 51. Cada solicitud
+// debe usar sus propios loaders
+type Loader struct {
+	fetch   BatchFunc
+	mu      sync.Mutex
+	pending []uint
+	values  map[uint]interface{}
+	errors  map[uint]error
+}
+
+// NewLoader crea un loader que carga las claves con fetch
+func NewLoader(fetch BatchFunc) *Loader {
+	return &Loader{fetch: fetch, values: make(map[uint]interface{}), errors: make(map[uint]error)}
+}
+
+// Load registra la clave y devuelve una función que resuelve su valor. graphql-go llama a estas funciones
+// recién después de recorrer todos los campos de un nivel de la consulta, y la primera llamada carga
+// todas las claves registradas hasta ese momento. La función debe devolverse sin convertir a otro
+// tipo: graphql-go solo reconoce func() (interface{}, error)
+func (l *Loader) Load(key uint) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.loaded(key) && !l.isPending(key) {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.loaded(key) {
+			l.dispatch()
+		}
+		return l.values[key], l.errors[key]
+	}
+}
+
+// dispatch carga las claves pendientes. Un error de la carga se guarda para todas ellas
+func (l *Loader) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errors[key] = err
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
+
+func (l *Loader) loaded(key uint) bool {
+	_, ok := l.values[key]
+	if !ok {
+		_, ok = l.errors[key]
+	}
+	return ok
+}
+
+func (l *Loader) isPending(key uint) bool {
+	for _, pending := range l.pending {
+		if pending == key {
+			return true
+		}
+	}
+	return false
+}
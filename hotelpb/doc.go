@@ -0,0 +1,2 @@
+// Package hotelpb contiene los mensajes y los servicios gRPC generados a partir de hotel.proto
+package hotelpb
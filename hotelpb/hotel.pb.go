@@ -0,0 +1,2917 @@
+// Servicios gRPC de la API del hotel. Comparten la lógica de negocio con los handlers REST de routes:
+// las altas, modificaciones, cancelaciones y bajas registran la misma auditoría y publican los mismos
+// eventos de dominio que REST, incluido ReservationDeleted al eliminar una reserva. Los usuarios y los
+// empleados no publican eventos.
+//
+// Para regenerar el código Go: make proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: hotelpb/hotel.proto
+
+package hotelpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Huésped
+type User struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	FirstName string                 `protobuf:"bytes,4,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName  string                 `protobuf:"bytes,5,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email     string                 `protobuf:"bytes,6,opt,name=email,proto3" json:"email,omitempty"`
+	// Idioma de las notificaciones
+	Locale string `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+	// Versión para el control de concurrencia optimista
+	Version       uint64 `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *User) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *User) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *User) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Reserva
+type Reservation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// tentative, confirmed, checked_in, checked_out o cancelled
+	Status          string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CheckIn         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=check_in,json=checkIn,proto3" json:"check_in,omitempty"`
+	CheckOut        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=check_out,json=checkOut,proto3" json:"check_out,omitempty"`
+	Adults          int32                  `protobuf:"varint,7,opt,name=adults,proto3" json:"adults,omitempty"`
+	Children        int32                  `protobuf:"varint,8,opt,name=children,proto3" json:"children,omitempty"`
+	NumberOfRooms   int32                  `protobuf:"varint,9,opt,name=number_of_rooms,json=numberOfRooms,proto3" json:"number_of_rooms,omitempty"`
+	RoomType        string                 `protobuf:"bytes,10,opt,name=room_type,json=roomType,proto3" json:"room_type,omitempty"`
+	GuestName       string                 `protobuf:"bytes,11,opt,name=guest_name,json=guestName,proto3" json:"guest_name,omitempty"`
+	Email           string                 `protobuf:"bytes,12,opt,name=email,proto3" json:"email,omitempty"`
+	UserId          uint64                 `protobuf:"varint,13,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RatePlanId      *uint64                `protobuf:"varint,14,opt,name=rate_plan_id,json=ratePlanId,proto3,oneof" json:"rate_plan_id,omitempty"`
+	GroupBlockId    *uint64                `protobuf:"varint,15,opt,name=group_block_id,json=groupBlockId,proto3,oneof" json:"group_block_id,omitempty"`
+	CancelledAt     *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CheckedInAt     *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=checked_in_at,json=checkedInAt,proto3" json:"checked_in_at,omitempty"`
+	CheckedOutAt    *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=checked_out_at,json=checkedOutAt,proto3" json:"checked_out_at,omitempty"`
+	CancellationFee float64                `protobuf:"fixed64,19,opt,name=cancellation_fee,json=cancellationFee,proto3" json:"cancellation_fee,omitempty"`
+	Version         uint64                 `protobuf:"varint,20,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Reservation) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Reservation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Reservation) GetCheckIn() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckIn
+	}
+	return nil
+}
+
+func (x *Reservation) GetCheckOut() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckOut
+	}
+	return nil
+}
+
+func (x *Reservation) GetAdults() int32 {
+	if x != nil {
+		return x.Adults
+	}
+	return 0
+}
+
+func (x *Reservation) GetChildren() int32 {
+	if x != nil {
+		return x.Children
+	}
+	return 0
+}
+
+func (x *Reservation) GetNumberOfRooms() int32 {
+	if x != nil {
+		return x.NumberOfRooms
+	}
+	return 0
+}
+
+func (x *Reservation) GetRoomType() string {
+	if x != nil {
+		return x.RoomType
+	}
+	return ""
+}
+
+func (x *Reservation) GetGuestName() string {
+	if x != nil {
+		return x.GuestName
+	}
+	return ""
+}
+
+func (x *Reservation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Reservation) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Reservation) GetRatePlanId() uint64 {
+	if x != nil && x.RatePlanId != nil {
+		return *x.RatePlanId
+	}
+	return 0
+}
+
+func (x *Reservation) GetGroupBlockId() uint64 {
+	if x != nil && x.GroupBlockId != nil {
+		return *x.GroupBlockId
+	}
+	return 0
+}
+
+func (x *Reservation) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetCheckedInAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedInAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetCheckedOutAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedOutAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetCancellationFee() float64 {
+	if x != nil {
+		return x.CancellationFee
+	}
+	return 0
+}
+
+func (x *Reservation) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Mensaje del hilo de una consulta
+type ConsultationMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// guest o staff
+	Author        string  `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	EmployeeId    *uint64 `protobuf:"varint,4,opt,name=employee_id,json=employeeId,proto3,oneof" json:"employee_id,omitempty"`
+	Body          string  `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsultationMessage) Reset() {
+	*x = ConsultationMessage{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsultationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsultationMessage) ProtoMessage() {}
+
+func (x *ConsultationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsultationMessage.ProtoReflect.Descriptor instead.
+func (*ConsultationMessage) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConsultationMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConsultationMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ConsultationMessage) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *ConsultationMessage) GetEmployeeId() uint64 {
+	if x != nil && x.EmployeeId != nil {
+		return *x.EmployeeId
+	}
+	return 0
+}
+
+func (x *ConsultationMessage) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+// Consulta de un huésped
+type Consultation struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Phone        string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Consultation string                 `protobuf:"bytes,5,opt,name=consultation,proto3" json:"consultation,omitempty"`
+	MoreInfo     bool                   `protobuf:"varint,6,opt,name=more_info,json=moreInfo,proto3" json:"more_info,omitempty"`
+	UserId       uint64                 `protobuf:"varint,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// open, pending, answered o closed
+	Status             string  `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	AssignedEmployeeId *uint64 `protobuf:"varint,9,opt,name=assigned_employee_id,json=assignedEmployeeId,proto3,oneof" json:"assigned_employee_id,omitempty"`
+	// Plazo para responder al huésped
+	DueAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	AnsweredAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=answered_at,json=answeredAt,proto3" json:"answered_at,omitempty"`
+	ClosedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	// Reserva creada a partir de la consulta
+	ReservationId *uint64 `protobuf:"varint,13,opt,name=reservation_id,json=reservationId,proto3,oneof" json:"reservation_id,omitempty"`
+	// Solo en GetConsultation
+	Messages      []*ConsultationMessage `protobuf:"bytes,14,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Consultation) Reset() {
+	*x = Consultation{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Consultation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Consultation) ProtoMessage() {}
+
+func (x *Consultation) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Consultation.ProtoReflect.Descriptor instead.
+func (*Consultation) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Consultation) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Consultation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Consultation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Consultation) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Consultation) GetConsultation() string {
+	if x != nil {
+		return x.Consultation
+	}
+	return ""
+}
+
+func (x *Consultation) GetMoreInfo() bool {
+	if x != nil {
+		return x.MoreInfo
+	}
+	return false
+}
+
+func (x *Consultation) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Consultation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Consultation) GetAssignedEmployeeId() uint64 {
+	if x != nil && x.AssignedEmployeeId != nil {
+		return *x.AssignedEmployeeId
+	}
+	return 0
+}
+
+func (x *Consultation) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *Consultation) GetAnsweredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AnsweredAt
+	}
+	return nil
+}
+
+func (x *Consultation) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Consultation) GetReservationId() uint64 {
+	if x != nil && x.ReservationId != nil {
+		return *x.ReservationId
+	}
+	return 0
+}
+
+func (x *Consultation) GetMessages() []*ConsultationMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// Empleado: sus datos personales y su versión están en el usuario embebido
+type Employee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Position      string                 `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	Salary        *float64               `protobuf:"fixed64,3,opt,name=salary,proto3,oneof" json:"salary,omitempty"` // Solo para el rol manager
+	Department    string                 `protobuf:"bytes,4,opt,name=department,proto3" json:"department,omitempty"`
+	HireDate      string                 `protobuf:"bytes,5,opt,name=hire_date,json=hireDate,proto3" json:"hire_date,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,6,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Employee) Reset() {
+	*x = Employee{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Employee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Employee) ProtoMessage() {}
+
+func (x *Employee) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Employee.ProtoReflect.Descriptor instead.
+func (*Employee) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Employee) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *Employee) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Employee) GetSalary() float64 {
+	if x != nil && x.Salary != nil {
+		return *x.Salary
+	}
+	return 0
+}
+
+func (x *Employee) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *Employee) GetHireDate() string {
+	if x != nil {
+		return x.HireDate
+	}
+	return ""
+}
+
+func (x *Employee) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+// Los listados se ordenan por id. page_size es 50 por defecto y como máximo 100; page_token es el
+// next_page_token de la página anterior, vacío en la última
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUserRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Locale        string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateUserRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+// Reemplaza los datos del usuario. Con version distinto de cero la escritura se rechaza con ABORTED
+// si el registro cambió desde esa versión, como If-Match en REST
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateUserRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateUserRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteUserRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListReservationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	UserId        *uint64                `protobuf:"varint,4,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReservationsRequest) Reset() {
+	*x = ListReservationsRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReservationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReservationsRequest) ProtoMessage() {}
+
+func (x *ListReservationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReservationsRequest.ProtoReflect.Descriptor instead.
+func (*ListReservationsRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListReservationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReservationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListReservationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListReservationsRequest) GetUserId() uint64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+type ListReservationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservations  []*Reservation         `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReservationsResponse) Reset() {
+	*x = ListReservationsResponse{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReservationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReservationsResponse) ProtoMessage() {}
+
+func (x *ListReservationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReservationsResponse.ProtoReflect.Descriptor instead.
+func (*ListReservationsResponse) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListReservationsResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+func (x *ListReservationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReservationRequest) Reset() {
+	*x = GetReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReservationRequest) ProtoMessage() {}
+
+func (x *GetReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReservationRequest.ProtoReflect.Descriptor instead.
+func (*GetReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetReservationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CheckIn       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=check_in,json=checkIn,proto3" json:"check_in,omitempty"`
+	CheckOut      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=check_out,json=checkOut,proto3" json:"check_out,omitempty"`
+	Adults        int32                  `protobuf:"varint,3,opt,name=adults,proto3" json:"adults,omitempty"`
+	Children      int32                  `protobuf:"varint,4,opt,name=children,proto3" json:"children,omitempty"`
+	NumberOfRooms int32                  `protobuf:"varint,5,opt,name=number_of_rooms,json=numberOfRooms,proto3" json:"number_of_rooms,omitempty"`
+	RoomType      string                 `protobuf:"bytes,6,opt,name=room_type,json=roomType,proto3" json:"room_type,omitempty"`
+	GuestName     string                 `protobuf:"bytes,7,opt,name=guest_name,json=guestName,proto3" json:"guest_name,omitempty"`
+	Email         string                 `protobuf:"bytes,8,opt,name=email,proto3" json:"email,omitempty"`
+	UserId        uint64                 `protobuf:"varint,9,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RatePlanId    *uint64                `protobuf:"varint,10,opt,name=rate_plan_id,json=ratePlanId,proto3,oneof" json:"rate_plan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReservationRequest) Reset() {
+	*x = CreateReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReservationRequest) ProtoMessage() {}
+
+func (x *CreateReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReservationRequest.ProtoReflect.Descriptor instead.
+func (*CreateReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateReservationRequest) GetCheckIn() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckIn
+	}
+	return nil
+}
+
+func (x *CreateReservationRequest) GetCheckOut() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckOut
+	}
+	return nil
+}
+
+func (x *CreateReservationRequest) GetAdults() int32 {
+	if x != nil {
+		return x.Adults
+	}
+	return 0
+}
+
+func (x *CreateReservationRequest) GetChildren() int32 {
+	if x != nil {
+		return x.Children
+	}
+	return 0
+}
+
+func (x *CreateReservationRequest) GetNumberOfRooms() int32 {
+	if x != nil {
+		return x.NumberOfRooms
+	}
+	return 0
+}
+
+func (x *CreateReservationRequest) GetRoomType() string {
+	if x != nil {
+		return x.RoomType
+	}
+	return ""
+}
+
+func (x *CreateReservationRequest) GetGuestName() string {
+	if x != nil {
+		return x.GuestName
+	}
+	return ""
+}
+
+func (x *CreateReservationRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateReservationRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateReservationRequest) GetRatePlanId() uint64 {
+	if x != nil && x.RatePlanId != nil {
+		return *x.RatePlanId
+	}
+	return 0
+}
+
+type UpdateReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	CheckIn       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=check_in,json=checkIn,proto3" json:"check_in,omitempty"`
+	CheckOut      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=check_out,json=checkOut,proto3" json:"check_out,omitempty"`
+	Adults        int32                  `protobuf:"varint,5,opt,name=adults,proto3" json:"adults,omitempty"`
+	Children      int32                  `protobuf:"varint,6,opt,name=children,proto3" json:"children,omitempty"`
+	NumberOfRooms int32                  `protobuf:"varint,7,opt,name=number_of_rooms,json=numberOfRooms,proto3" json:"number_of_rooms,omitempty"`
+	RoomType      string                 `protobuf:"bytes,8,opt,name=room_type,json=roomType,proto3" json:"room_type,omitempty"`
+	Email         string                 `protobuf:"bytes,9,opt,name=email,proto3" json:"email,omitempty"`
+	UserId        uint64                 `protobuf:"varint,10,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RatePlanId    *uint64                `protobuf:"varint,11,opt,name=rate_plan_id,json=ratePlanId,proto3,oneof" json:"rate_plan_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReservationRequest) Reset() {
+	*x = UpdateReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReservationRequest) ProtoMessage() {}
+
+func (x *UpdateReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReservationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateReservationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetCheckIn() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckIn
+	}
+	return nil
+}
+
+func (x *UpdateReservationRequest) GetCheckOut() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckOut
+	}
+	return nil
+}
+
+func (x *UpdateReservationRequest) GetAdults() int32 {
+	if x != nil {
+		return x.Adults
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetChildren() int32 {
+	if x != nil {
+		return x.Children
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetNumberOfRooms() int32 {
+	if x != nil {
+		return x.NumberOfRooms
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetRoomType() string {
+	if x != nil {
+		return x.RoomType
+	}
+	return ""
+}
+
+func (x *UpdateReservationRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateReservationRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UpdateReservationRequest) GetRatePlanId() uint64 {
+	if x != nil && x.RatePlanId != nil {
+		return *x.RatePlanId
+	}
+	return 0
+}
+
+type CancelReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReservationRequest) Reset() {
+	*x = CancelReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReservationRequest) ProtoMessage() {}
+
+func (x *CancelReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReservationRequest.ProtoReflect.Descriptor instead.
+func (*CancelReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelReservationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CancelReservationResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Reservation *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	// Penalización cargada en el folio
+	Penalty float64 `protobuf:"fixed64,2,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	// Hasta cuándo se podía cancelar sin cargo
+	FreeUntil     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=free_until,json=freeUntil,proto3" json:"free_until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReservationResponse) Reset() {
+	*x = CancelReservationResponse{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReservationResponse) ProtoMessage() {}
+
+func (x *CancelReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReservationResponse.ProtoReflect.Descriptor instead.
+func (*CancelReservationResponse) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+func (x *CancelReservationResponse) GetPenalty() float64 {
+	if x != nil {
+		return x.Penalty
+	}
+	return 0
+}
+
+func (x *CancelReservationResponse) GetFreeUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FreeUntil
+	}
+	return nil
+}
+
+type ConfirmReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmReservationRequest) Reset() {
+	*x = ConfirmReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmReservationRequest) ProtoMessage() {}
+
+func (x *ConfirmReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmReservationRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ConfirmReservationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReservationRequest) Reset() {
+	*x = DeleteReservationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReservationRequest) ProtoMessage() {}
+
+func (x *DeleteReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReservationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReservationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteReservationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Sin after_event_id se reciben solo los cambios posteriores a la llamada. Para retomar un stream
+// cortado se envía el event_id del último evento recibido
+type WatchReservationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AfterEventId  *uint64                `protobuf:"varint,1,opt,name=after_event_id,json=afterEventId,proto3,oneof" json:"after_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchReservationsRequest) Reset() {
+	*x = WatchReservationsRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchReservationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchReservationsRequest) ProtoMessage() {}
+
+func (x *WatchReservationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchReservationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchReservationsRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchReservationsRequest) GetAfterEventId() uint64 {
+	if x != nil && x.AfterEventId != nil {
+		return *x.AfterEventId
+	}
+	return 0
+}
+
+// Cambio de una reserva, con su estado después del cambio
+type ReservationEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId uint64                 `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// ReservationCreated, ReservationModified, ReservationConfirmed, ReservationCancelled,
+	// GuestCheckedIn o GuestCheckedOut
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Reservation   *Reservation           `protobuf:"bytes,4,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationEvent) Reset() {
+	*x = ReservationEvent{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationEvent) ProtoMessage() {}
+
+func (x *ReservationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationEvent.ProtoReflect.Descriptor instead.
+func (*ReservationEvent) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReservationEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *ReservationEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ReservationEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ReservationEvent) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+// Los mismos filtros que GET /consultations
+type ListConsultationsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	PageSize           int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken          string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Status             string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	AssignedEmployeeId *uint64                `protobuf:"varint,4,opt,name=assigned_employee_id,json=assignedEmployeeId,proto3,oneof" json:"assigned_employee_id,omitempty"`
+	Unassigned         bool                   `protobuf:"varint,5,opt,name=unassigned,proto3" json:"unassigned,omitempty"`
+	Overdue            bool                   `protobuf:"varint,6,opt,name=overdue,proto3" json:"overdue,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListConsultationsRequest) Reset() {
+	*x = ListConsultationsRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConsultationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConsultationsRequest) ProtoMessage() {}
+
+func (x *ListConsultationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConsultationsRequest.ProtoReflect.Descriptor instead.
+func (*ListConsultationsRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListConsultationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListConsultationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListConsultationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListConsultationsRequest) GetAssignedEmployeeId() uint64 {
+	if x != nil && x.AssignedEmployeeId != nil {
+		return *x.AssignedEmployeeId
+	}
+	return 0
+}
+
+func (x *ListConsultationsRequest) GetUnassigned() bool {
+	if x != nil {
+		return x.Unassigned
+	}
+	return false
+}
+
+func (x *ListConsultationsRequest) GetOverdue() bool {
+	if x != nil {
+		return x.Overdue
+	}
+	return false
+}
+
+type ListConsultationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consultations []*Consultation        `protobuf:"bytes,1,rep,name=consultations,proto3" json:"consultations,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConsultationsResponse) Reset() {
+	*x = ListConsultationsResponse{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConsultationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConsultationsResponse) ProtoMessage() {}
+
+func (x *ListConsultationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConsultationsResponse.ProtoReflect.Descriptor instead.
+func (*ListConsultationsResponse) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListConsultationsResponse) GetConsultations() []*Consultation {
+	if x != nil {
+		return x.Consultations
+	}
+	return nil
+}
+
+func (x *ListConsultationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetConsultationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConsultationRequest) Reset() {
+	*x = GetConsultationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConsultationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConsultationRequest) ProtoMessage() {}
+
+func (x *GetConsultationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConsultationRequest.ProtoReflect.Descriptor instead.
+func (*GetConsultationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetConsultationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateConsultationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phone         string                 `protobuf:"bytes,1,opt,name=phone,proto3" json:"phone,omitempty"`
+	Consultation  string                 `protobuf:"bytes,2,opt,name=consultation,proto3" json:"consultation,omitempty"`
+	MoreInfo      bool                   `protobuf:"varint,3,opt,name=more_info,json=moreInfo,proto3" json:"more_info,omitempty"`
+	UserId        uint64                 `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateConsultationRequest) Reset() {
+	*x = CreateConsultationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateConsultationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateConsultationRequest) ProtoMessage() {}
+
+func (x *CreateConsultationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateConsultationRequest.ProtoReflect.Descriptor instead.
+func (*CreateConsultationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateConsultationRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *CreateConsultationRequest) GetConsultation() string {
+	if x != nil {
+		return x.Consultation
+	}
+	return ""
+}
+
+func (x *CreateConsultationRequest) GetMoreInfo() bool {
+	if x != nil {
+		return x.MoreInfo
+	}
+	return false
+}
+
+func (x *CreateConsultationRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type UpdateConsultationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	Consultation  string                 `protobuf:"bytes,3,opt,name=consultation,proto3" json:"consultation,omitempty"`
+	MoreInfo      bool                   `protobuf:"varint,4,opt,name=more_info,json=moreInfo,proto3" json:"more_info,omitempty"`
+	UserId        uint64                 `protobuf:"varint,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConsultationRequest) Reset() {
+	*x = UpdateConsultationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConsultationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConsultationRequest) ProtoMessage() {}
+
+func (x *UpdateConsultationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConsultationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConsultationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UpdateConsultationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateConsultationRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *UpdateConsultationRequest) GetConsultation() string {
+	if x != nil {
+		return x.Consultation
+	}
+	return ""
+}
+
+func (x *UpdateConsultationRequest) GetMoreInfo() bool {
+	if x != nil {
+		return x.MoreInfo
+	}
+	return false
+}
+
+func (x *UpdateConsultationRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type DeleteConsultationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteConsultationRequest) Reset() {
+	*x = DeleteConsultationRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteConsultationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConsultationRequest) ProtoMessage() {}
+
+func (x *DeleteConsultationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConsultationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteConsultationRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteConsultationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListEmployeesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmployeesRequest) Reset() {
+	*x = ListEmployeesRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmployeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmployeesRequest) ProtoMessage() {}
+
+func (x *ListEmployeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmployeesRequest.ProtoReflect.Descriptor instead.
+func (*ListEmployeesRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListEmployeesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEmployeesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListEmployeesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Employees     []*Employee            `protobuf:"bytes,1,rep,name=employees,proto3" json:"employees,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEmployeesResponse) Reset() {
+	*x = ListEmployeesResponse{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEmployeesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEmployeesResponse) ProtoMessage() {}
+
+func (x *ListEmployeesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEmployeesResponse.ProtoReflect.Descriptor instead.
+func (*ListEmployeesResponse) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListEmployeesResponse) GetEmployees() []*Employee {
+	if x != nil {
+		return x.Employees
+	}
+	return nil
+}
+
+func (x *ListEmployeesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetEmployeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEmployeeRequest) Reset() {
+	*x = GetEmployeeRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEmployeeRequest) ProtoMessage() {}
+
+func (x *GetEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*GetEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetEmployeeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateEmployeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Position      string                 `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	Salary        float64                `protobuf:"fixed64,5,opt,name=salary,proto3" json:"salary,omitempty"`
+	Department    string                 `protobuf:"bytes,6,opt,name=department,proto3" json:"department,omitempty"`
+	HireDate      string                 `protobuf:"bytes,7,opt,name=hire_date,json=hireDate,proto3" json:"hire_date,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,8,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmployeeRequest) Reset() {
+	*x = CreateEmployeeRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmployeeRequest) ProtoMessage() {}
+
+func (x *CreateEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*CreateEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreateEmployeeRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetSalary() float64 {
+	if x != nil {
+		return x.Salary
+	}
+	return 0
+}
+
+func (x *CreateEmployeeRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetHireDate() string {
+	if x != nil {
+		return x.HireDate
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+type UpdateEmployeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Position      string                 `protobuf:"bytes,6,opt,name=position,proto3" json:"position,omitempty"`
+	Salary        float64                `protobuf:"fixed64,7,opt,name=salary,proto3" json:"salary,omitempty"`
+	Department    string                 `protobuf:"bytes,8,opt,name=department,proto3" json:"department,omitempty"`
+	HireDate      string                 `protobuf:"bytes,9,opt,name=hire_date,json=hireDate,proto3" json:"hire_date,omitempty"`
+	PhoneNumber   string                 `protobuf:"bytes,10,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEmployeeRequest) Reset() {
+	*x = UpdateEmployeeRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEmployeeRequest) ProtoMessage() {}
+
+func (x *UpdateEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateEmployeeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateEmployeeRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateEmployeeRequest) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetSalary() float64 {
+	if x != nil {
+		return x.Salary
+	}
+	return 0
+}
+
+func (x *UpdateEmployeeRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetHireDate() string {
+	if x != nil {
+		return x.HireDate
+	}
+	return ""
+}
+
+func (x *UpdateEmployeeRequest) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+type DeleteEmployeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEmployeeRequest) Reset() {
+	*x = DeleteEmployeeRequest{}
+	mi := &file_hotelpb_hotel_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEmployeeRequest) ProtoMessage() {}
+
+func (x *DeleteEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hotelpb_hotel_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_hotelpb_hotel_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteEmployeeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_hotelpb_hotel_proto protoreflect.FileDescriptor
+
+const file_hotelpb_hotel_proto_rawDesc = "" +
+	"\n" +
+	"\x13hotelpb/hotel.proto\x12\bhotel.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x90\x02\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x04 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x05 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x06 \x01(\tR\x05email\x12\x16\n" +
+	"\x06locale\x18\a \x01(\tR\x06locale\x12\x18\n" +
+	"\aversion\x18\b \x01(\x04R\aversion\"\xde\x06\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x125\n" +
+	"\bcheck_in\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\acheckIn\x127\n" +
+	"\tcheck_out\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bcheckOut\x12\x16\n" +
+	"\x06adults\x18\a \x01(\x05R\x06adults\x12\x1a\n" +
+	"\bchildren\x18\b \x01(\x05R\bchildren\x12&\n" +
+	"\x0fnumber_of_rooms\x18\t \x01(\x05R\rnumberOfRooms\x12\x1b\n" +
+	"\troom_type\x18\n" +
+	" \x01(\tR\broomType\x12\x1d\n" +
+	"\n" +
+	"guest_name\x18\v \x01(\tR\tguestName\x12\x14\n" +
+	"\x05email\x18\f \x01(\tR\x05email\x12\x17\n" +
+	"\auser_id\x18\r \x01(\x04R\x06userId\x12%\n" +
+	"\frate_plan_id\x18\x0e \x01(\x04H\x00R\n" +
+	"ratePlanId\x88\x01\x01\x12)\n" +
+	"\x0egroup_block_id\x18\x0f \x01(\x04H\x01R\fgroupBlockId\x88\x01\x01\x12=\n" +
+	"\fcancelled_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x12>\n" +
+	"\rchecked_in_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\vcheckedInAt\x12@\n" +
+	"\x0echecked_out_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\fcheckedOutAt\x12)\n" +
+	"\x10cancellation_fee\x18\x13 \x01(\x01R\x0fcancellationFee\x12\x18\n" +
+	"\aversion\x18\x14 \x01(\x04R\aversionB\x0f\n" +
+	"\r_rate_plan_idB\x11\n" +
+	"\x0f_group_block_id\"\xc2\x01\n" +
+	"\x13ConsultationMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12$\n" +
+	"\vemployee_id\x18\x04 \x01(\x04H\x00R\n" +
+	"employeeId\x88\x01\x01\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04bodyB\x0e\n" +
+	"\f_employee_id\"\x8f\x05\n" +
+	"\fConsultation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\"\n" +
+	"\fconsultation\x18\x05 \x01(\tR\fconsultation\x12\x1b\n" +
+	"\tmore_info\x18\x06 \x01(\bR\bmoreInfo\x12\x17\n" +
+	"\auser_id\x18\a \x01(\x04R\x06userId\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x125\n" +
+	"\x14assigned_employee_id\x18\t \x01(\x04H\x00R\x12assignedEmployeeId\x88\x01\x01\x121\n" +
+	"\x06due_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12;\n" +
+	"\vanswered_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"answeredAt\x127\n" +
+	"\tclosed_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x12*\n" +
+	"\x0ereservation_id\x18\r \x01(\x04H\x01R\rreservationId\x88\x01\x01\x129\n" +
+	"\bmessages\x18\x0e \x03(\v2\x1d.hotel.v1.ConsultationMessageR\bmessagesB\x17\n" +
+	"\x15_assigned_employee_idB\x11\n" +
+	"\x0f_reservation_id\"\xd2\x01\n" +
+	"\bEmployee\x12\"\n" +
+	"\x04user\x18\x01 \x01(\v2\x0e.hotel.v1.UserR\x04user\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\tR\bposition\x12\x1b\n" +
+	"\x06salary\x18\x03 \x01(\x01H\x00R\x06salary\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"department\x18\x04 \x01(\tR\n" +
+	"department\x12\x1b\n" +
+	"\thire_date\x18\x05 \x01(\tR\bhireDate\x12!\n" +
+	"\fphone_number\x18\x06 \x01(\tR\vphoneNumberB\t\n" +
+	"\a_salary\"N\n" +
+	"\x10ListUsersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"a\n" +
+	"\x11ListUsersResponse\x12$\n" +
+	"\x05users\x18\x01 \x03(\v2\x0e.hotel.v1.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\" \n" +
+	"\x0eGetUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"}\n" +
+	"\x11CreateUserRequest\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\"\x8f\x01\n" +
+	"\x11UpdateUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\"#\n" +
+	"\x11DeleteUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x97\x01\n" +
+	"\x17ListReservationsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1c\n" +
+	"\auser_id\x18\x04 \x01(\x04H\x00R\x06userId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_id\"}\n" +
+	"\x18ListReservationsResponse\x129\n" +
+	"\freservations\x18\x01 \x03(\v2\x15.hotel.v1.ReservationR\freservations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"'\n" +
+	"\x15GetReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x89\x03\n" +
+	"\x18CreateReservationRequest\x125\n" +
+	"\bcheck_in\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\acheckIn\x127\n" +
+	"\tcheck_out\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bcheckOut\x12\x16\n" +
+	"\x06adults\x18\x03 \x01(\x05R\x06adults\x12\x1a\n" +
+	"\bchildren\x18\x04 \x01(\x05R\bchildren\x12&\n" +
+	"\x0fnumber_of_rooms\x18\x05 \x01(\x05R\rnumberOfRooms\x12\x1b\n" +
+	"\troom_type\x18\x06 \x01(\tR\broomType\x12\x1d\n" +
+	"\n" +
+	"guest_name\x18\a \x01(\tR\tguestName\x12\x14\n" +
+	"\x05email\x18\b \x01(\tR\x05email\x12\x17\n" +
+	"\auser_id\x18\t \x01(\x04R\x06userId\x12%\n" +
+	"\frate_plan_id\x18\n" +
+	" \x01(\x04H\x00R\n" +
+	"ratePlanId\x88\x01\x01B\x0f\n" +
+	"\r_rate_plan_id\"\x94\x03\n" +
+	"\x18UpdateReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x125\n" +
+	"\bcheck_in\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\acheckIn\x127\n" +
+	"\tcheck_out\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bcheckOut\x12\x16\n" +
+	"\x06adults\x18\x05 \x01(\x05R\x06adults\x12\x1a\n" +
+	"\bchildren\x18\x06 \x01(\x05R\bchildren\x12&\n" +
+	"\x0fnumber_of_rooms\x18\a \x01(\x05R\rnumberOfRooms\x12\x1b\n" +
+	"\troom_type\x18\b \x01(\tR\broomType\x12\x14\n" +
+	"\x05email\x18\t \x01(\tR\x05email\x12\x17\n" +
+	"\auser_id\x18\n" +
+	" \x01(\x04R\x06userId\x12%\n" +
+	"\frate_plan_id\x18\v \x01(\x04H\x00R\n" +
+	"ratePlanId\x88\x01\x01B\x0f\n" +
+	"\r_rate_plan_id\"*\n" +
+	"\x18CancelReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xa9\x01\n" +
+	"\x19CancelReservationResponse\x127\n" +
+	"\vreservation\x18\x01 \x01(\v2\x15.hotel.v1.ReservationR\vreservation\x12\x18\n" +
+	"\apenalty\x18\x02 \x01(\x01R\apenalty\x129\n" +
+	"\n" +
+	"free_until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tfreeUntil\"+\n" +
+	"\x19ConfirmReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"*\n" +
+	"\x18DeleteReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"X\n" +
+	"\x18WatchReservationsRequest\x12)\n" +
+	"\x0eafter_event_id\x18\x01 \x01(\x04H\x00R\fafterEventId\x88\x01\x01B\x11\n" +
+	"\x0f_after_event_id\"\xb5\x01\n" +
+	"\x10ReservationEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\vreservation\x18\x04 \x01(\v2\x15.hotel.v1.ReservationR\vreservation\"\xf8\x01\n" +
+	"\x18ListConsultationsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x125\n" +
+	"\x14assigned_employee_id\x18\x04 \x01(\x04H\x00R\x12assignedEmployeeId\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"unassigned\x18\x05 \x01(\bR\n" +
+	"unassigned\x12\x18\n" +
+	"\aoverdue\x18\x06 \x01(\bR\aoverdueB\x17\n" +
+	"\x15_assigned_employee_id\"\x81\x01\n" +
+	"\x19ListConsultationsResponse\x12<\n" +
+	"\rconsultations\x18\x01 \x03(\v2\x16.hotel.v1.ConsultationR\rconsultations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"(\n" +
+	"\x16GetConsultationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x8b\x01\n" +
+	"\x19CreateConsultationRequest\x12\x14\n" +
+	"\x05phone\x18\x01 \x01(\tR\x05phone\x12\"\n" +
+	"\fconsultation\x18\x02 \x01(\tR\fconsultation\x12\x1b\n" +
+	"\tmore_info\x18\x03 \x01(\bR\bmoreInfo\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\x04R\x06userId\"\x9b\x01\n" +
+	"\x19UpdateConsultationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12\"\n" +
+	"\fconsultation\x18\x03 \x01(\tR\fconsultation\x12\x1b\n" +
+	"\tmore_info\x18\x04 \x01(\bR\bmoreInfo\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\x04R\x06userId\"+\n" +
+	"\x19DeleteConsultationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"R\n" +
+	"\x14ListEmployeesRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"q\n" +
+	"\x15ListEmployeesResponse\x120\n" +
+	"\temployees\x18\x01 \x03(\v2\x12.hotel.v1.EmployeeR\temployees\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"$\n" +
+	"\x12GetEmployeeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xfd\x01\n" +
+	"\x15CreateEmployeeRequest\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\tR\bposition\x12\x16\n" +
+	"\x06salary\x18\x05 \x01(\x01R\x06salary\x12\x1e\n" +
+	"\n" +
+	"department\x18\x06 \x01(\tR\n" +
+	"department\x12\x1b\n" +
+	"\thire_date\x18\a \x01(\tR\bhireDate\x12!\n" +
+	"\fphone_number\x18\b \x01(\tR\vphoneNumber\"\xa7\x02\n" +
+	"\x15UpdateEmployeeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\tR\bposition\x12\x16\n" +
+	"\x06salary\x18\a \x01(\x01R\x06salary\x12\x1e\n" +
+	"\n" +
+	"department\x18\b \x01(\tR\n" +
+	"department\x12\x1b\n" +
+	"\thire_date\x18\t \x01(\tR\bhireDate\x12!\n" +
+	"\fphone_number\x18\n" +
+	" \x01(\tR\vphoneNumber\"'\n" +
+	"\x15DeleteEmployeeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id2\xc1\x02\n" +
+	"\vUserService\x12D\n" +
+	"\tListUsers\x12\x1a.hotel.v1.ListUsersRequest\x1a\x1b.hotel.v1.ListUsersResponse\x123\n" +
+	"\aGetUser\x12\x18.hotel.v1.GetUserRequest\x1a\x0e.hotel.v1.User\x129\n" +
+	"\n" +
+	"CreateUser\x12\x1b.hotel.v1.CreateUserRequest\x1a\x0e.hotel.v1.User\x129\n" +
+	"\n" +
+	"UpdateUser\x12\x1b.hotel.v1.UpdateUserRequest\x1a\x0e.hotel.v1.User\x12A\n" +
+	"\n" +
+	"DeleteUser\x12\x1b.hotel.v1.DeleteUserRequest\x1a\x16.google.protobuf.Empty2\xb1\x05\n" +
+	"\x12ReservationService\x12Y\n" +
+	"\x10ListReservations\x12!.hotel.v1.ListReservationsRequest\x1a\".hotel.v1.ListReservationsResponse\x12H\n" +
+	"\x0eGetReservation\x12\x1f.hotel.v1.GetReservationRequest\x1a\x15.hotel.v1.Reservation\x12N\n" +
+	"\x11CreateReservation\x12\".hotel.v1.CreateReservationRequest\x1a\x15.hotel.v1.Reservation\x12N\n" +
+	"\x11UpdateReservation\x12\".hotel.v1.UpdateReservationRequest\x1a\x15.hotel.v1.Reservation\x12\\\n" +
+	"\x11CancelReservation\x12\".hotel.v1.CancelReservationRequest\x1a#.hotel.v1.CancelReservationResponse\x12P\n" +
+	"\x12ConfirmReservation\x12#.hotel.v1.ConfirmReservationRequest\x1a\x15.hotel.v1.Reservation\x12O\n" +
+	"\x11DeleteReservation\x12\".hotel.v1.DeleteReservationRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\x11WatchReservations\x12\".hotel.v1.WatchReservationsRequest\x1a\x1a.hotel.v1.ReservationEvent0\x012\xb9\x03\n" +
+	"\x13ConsultationService\x12\\\n" +
+	"\x11ListConsultations\x12\".hotel.v1.ListConsultationsRequest\x1a#.hotel.v1.ListConsultationsResponse\x12K\n" +
+	"\x0fGetConsultation\x12 .hotel.v1.GetConsultationRequest\x1a\x16.hotel.v1.Consultation\x12Q\n" +
+	"\x12CreateConsultation\x12#.hotel.v1.CreateConsultationRequest\x1a\x16.hotel.v1.Consultation\x12Q\n" +
+	"\x12UpdateConsultation\x12#.hotel.v1.UpdateConsultationRequest\x1a\x16.hotel.v1.Consultation\x12Q\n" +
+	"\x12DeleteConsultation\x12#.hotel.v1.DeleteConsultationRequest\x1a\x16.google.protobuf.Empty2\xfd\x02\n" +
+	"\x0fEmployeeService\x12P\n" +
+	"\rListEmployees\x12\x1e.hotel.v1.ListEmployeesRequest\x1a\x1f.hotel.v1.ListEmployeesResponse\x12?\n" +
+	"\vGetEmployee\x12\x1c.hotel.v1.GetEmployeeRequest\x1a\x12.hotel.v1.Employee\x12E\n" +
+	"\x0eCreateEmployee\x12\x1f.hotel.v1.CreateEmployeeRequest\x1a\x12.hotel.v1.Employee\x12E\n" +
+	"\x0eUpdateEmployee\x12\x1f.hotel.v1.UpdateEmployeeRequest\x1a\x12.hotel.v1.Employee\x12I\n" +
+	"\x0eDeleteEmployee\x12\x1f.hotel.v1.DeleteEmployeeRequest\x1a\x16.google.protobuf.EmptyB>Z<github.com/germancaradec/Go-API-REST-PostgresSQL.git/hotelpbb\x06proto3"
+
+var (
+	file_hotelpb_hotel_proto_rawDescOnce sync.Once
+	file_hotelpb_hotel_proto_rawDescData []byte
+)
+
+func file_hotelpb_hotel_proto_rawDescGZIP() []byte {
+	file_hotelpb_hotel_proto_rawDescOnce.Do(func() {
+		file_hotelpb_hotel_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hotelpb_hotel_proto_rawDesc), len(file_hotelpb_hotel_proto_rawDesc)))
+	})
+	return file_hotelpb_hotel_proto_rawDescData
+}
+
+var file_hotelpb_hotel_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
+var file_hotelpb_hotel_proto_goTypes = []any{
+	(*User)(nil),                      // 0: hotel.v1.User
+	(*Reservation)(nil),               // 1: hotel.v1.Reservation
+	(*ConsultationMessage)(nil),       // 2: hotel.v1.ConsultationMessage
+	(*Consultation)(nil),              // 3: hotel.v1.Consultation
+	(*Employee)(nil),                  // 4: hotel.v1.Employee
+	(*ListUsersRequest)(nil),          // 5: hotel.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),         // 6: hotel.v1.ListUsersResponse
+	(*GetUserRequest)(nil),            // 7: hotel.v1.GetUserRequest
+	(*CreateUserRequest)(nil),         // 8: hotel.v1.CreateUserRequest
+	(*UpdateUserRequest)(nil),         // 9: hotel.v1.UpdateUserRequest
+	(*DeleteUserRequest)(nil),         // 10: hotel.v1.DeleteUserRequest
+	(*ListReservationsRequest)(nil),   // 11: hotel.v1.ListReservationsRequest
+	(*ListReservationsResponse)(nil),  // 12: hotel.v1.ListReservationsResponse
+	(*GetReservationRequest)(nil),     // 13: hotel.v1.GetReservationRequest
+	(*CreateReservationRequest)(nil),  // 14: hotel.v1.CreateReservationRequest
+	(*UpdateReservationRequest)(nil),  // 15: hotel.v1.UpdateReservationRequest
+	(*CancelReservationRequest)(nil),  // 16: hotel.v1.CancelReservationRequest
+	(*CancelReservationResponse)(nil), // 17: hotel.v1.CancelReservationResponse
+	(*ConfirmReservationRequest)(nil), // 18: hotel.v1.ConfirmReservationRequest
+	(*DeleteReservationRequest)(nil),  // 19: hotel.v1.DeleteReservationRequest
+	(*WatchReservationsRequest)(nil),  // 20: hotel.v1.WatchReservationsRequest
+	(*ReservationEvent)(nil),          // 21: hotel.v1.ReservationEvent
+	(*ListConsultationsRequest)(nil),  // 22: hotel.v1.ListConsultationsRequest
+	(*ListConsultationsResponse)(nil), // 23: hotel.v1.ListConsultationsResponse
+	(*GetConsultationRequest)(nil),    // 24: hotel.v1.GetConsultationRequest
+	(*CreateConsultationRequest)(nil), // 25: hotel.v1.CreateConsultationRequest
+	(*UpdateConsultationRequest)(nil), // 26: hotel.v1.UpdateConsultationRequest
+	(*DeleteConsultationRequest)(nil), // 27: hotel.v1.DeleteConsultationRequest
+	(*ListEmployeesRequest)(nil),      // 28: hotel.v1.ListEmployeesRequest
+	(*ListEmployeesResponse)(nil),     // 29: hotel.v1.ListEmployeesResponse
+	(*GetEmployeeRequest)(nil),        // 30: hotel.v1.GetEmployeeRequest
+	(*CreateEmployeeRequest)(nil),     // 31: hotel.v1.CreateEmployeeRequest
+	(*UpdateEmployeeRequest)(nil),     // 32: hotel.v1.UpdateEmployeeRequest
+	(*DeleteEmployeeRequest)(nil),     // 33: hotel.v1.DeleteEmployeeRequest
+	(*timestamppb.Timestamp)(nil),     // 34: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 35: google.protobuf.Empty
+}
+var file_hotelpb_hotel_proto_depIdxs = []int32{
+	34, // 0: hotel.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	34, // 1: hotel.v1.User.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 2: hotel.v1.Reservation.created_at:type_name -> google.protobuf.Timestamp
+	34, // 3: hotel.v1.Reservation.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 4: hotel.v1.Reservation.check_in:type_name -> google.protobuf.Timestamp
+	34, // 5: hotel.v1.Reservation.check_out:type_name -> google.protobuf.Timestamp
+	34, // 6: hotel.v1.Reservation.cancelled_at:type_name -> google.protobuf.Timestamp
+	34, // 7: hotel.v1.Reservation.checked_in_at:type_name -> google.protobuf.Timestamp
+	34, // 8: hotel.v1.Reservation.checked_out_at:type_name -> google.protobuf.Timestamp
+	34, // 9: hotel.v1.ConsultationMessage.created_at:type_name -> google.protobuf.Timestamp
+	34, // 10: hotel.v1.Consultation.created_at:type_name -> google.protobuf.Timestamp
+	34, // 11: hotel.v1.Consultation.updated_at:type_name -> google.protobuf.Timestamp
+	34, // 12: hotel.v1.Consultation.due_at:type_name -> google.protobuf.Timestamp
+	34, // 13: hotel.v1.Consultation.answered_at:type_name -> google.protobuf.Timestamp
+	34, // 14: hotel.v1.Consultation.closed_at:type_name -> google.protobuf.Timestamp
+	2,  // 15: hotel.v1.Consultation.messages:type_name -> hotel.v1.ConsultationMessage
+	0,  // 16: hotel.v1.Employee.user:type_name -> hotel.v1.User
+	0,  // 17: hotel.v1.ListUsersResponse.users:type_name -> hotel.v1.User
+	1,  // 18: hotel.v1.ListReservationsResponse.reservations:type_name -> hotel.v1.Reservation
+	34, // 19: hotel.v1.CreateReservationRequest.check_in:type_name -> google.protobuf.Timestamp
+	34, // 20: hotel.v1.CreateReservationRequest.check_out:type_name -> google.protobuf.Timestamp
+	34, // 21: hotel.v1.UpdateReservationRequest.check_in:type_name -> google.protobuf.Timestamp
+	34, // 22: hotel.v1.UpdateReservationRequest.check_out:type_name -> google.protobuf.Timestamp
+	1,  // 23: hotel.v1.CancelReservationResponse.reservation:type_name -> hotel.v1.Reservation
+	34, // 24: hotel.v1.CancelReservationResponse.free_until:type_name -> google.protobuf.Timestamp
+	34, // 25: hotel.v1.ReservationEvent.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 26: hotel.v1.ReservationEvent.reservation:type_name -> hotel.v1.Reservation
+	3,  // 27: hotel.v1.ListConsultationsResponse.consultations:type_name -> hotel.v1.Consultation
+	4,  // 28: hotel.v1.ListEmployeesResponse.employees:type_name -> hotel.v1.Employee
+	5,  // 29: hotel.v1.UserService.ListUsers:input_type -> hotel.v1.ListUsersRequest
+	7,  // 30: hotel.v1.UserService.GetUser:input_type -> hotel.v1.GetUserRequest
+	8,  // 31: hotel.v1.UserService.CreateUser:input_type -> hotel.v1.CreateUserRequest
+	9,  // 32: hotel.v1.UserService.UpdateUser:input_type -> hotel.v1.UpdateUserRequest
+	10, // 33: hotel.v1.UserService.DeleteUser:input_type -> hotel.v1.DeleteUserRequest
+	11, // 34: hotel.v1.ReservationService.ListReservations:input_type -> hotel.v1.ListReservationsRequest
+	13, // 35: hotel.v1.ReservationService.GetReservation:input_type -> hotel.v1.GetReservationRequest
+	14, // 36: hotel.v1.ReservationService.CreateReservation:input_type -> hotel.v1.CreateReservationRequest
+	15, // 37: hotel.v1.ReservationService.UpdateReservation:input_type -> hotel.v1.UpdateReservationRequest
+	16, // 38: hotel.v1.ReservationService.CancelReservation:input_type -> hotel.v1.CancelReservationRequest
+	18, // 39: hotel.v1.ReservationService.ConfirmReservation:input_type -> hotel.v1.ConfirmReservationRequest
+	19, // 40: hotel.v1.ReservationService.DeleteReservation:input_type -> hotel.v1.DeleteReservationRequest
+	20, // 41: hotel.v1.ReservationService.WatchReservations:input_type -> hotel.v1.WatchReservationsRequest
+	22, // 42: hotel.v1.ConsultationService.ListConsultations:input_type -> hotel.v1.ListConsultationsRequest
+	24, // 43: hotel.v1.ConsultationService.GetConsultation:input_type -> hotel.v1.GetConsultationRequest
+	25, // 44: hotel.v1.ConsultationService.CreateConsultation:input_type -> hotel.v1.CreateConsultationRequest
+	26, // 45: hotel.v1.ConsultationService.UpdateConsultation:input_type -> hotel.v1.UpdateConsultationRequest
+	27, // 46: hotel.v1.ConsultationService.DeleteConsultation:input_type -> hotel.v1.DeleteConsultationRequest
+	28, // 47: hotel.v1.EmployeeService.ListEmployees:input_type -> hotel.v1.ListEmployeesRequest
+	30, // 48: hotel.v1.EmployeeService.GetEmployee:input_type -> hotel.v1.GetEmployeeRequest
+	31, // 49: hotel.v1.EmployeeService.CreateEmployee:input_type -> hotel.v1.CreateEmployeeRequest
+	32, // 50: hotel.v1.EmployeeService.UpdateEmployee:input_type -> hotel.v1.UpdateEmployeeRequest
+	33, // 51: hotel.v1.EmployeeService.DeleteEmployee:input_type -> hotel.v1.DeleteEmployeeRequest
+	6,  // 52: hotel.v1.UserService.ListUsers:output_type -> hotel.v1.ListUsersResponse
+	0,  // 53: hotel.v1.UserService.GetUser:output_type -> hotel.v1.User
+	0,  // 54: hotel.v1.UserService.CreateUser:output_type -> hotel.v1.User
+	0,  // 55: hotel.v1.UserService.UpdateUser:output_type -> hotel.v1.User
+	35, // 56: hotel.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	12, // 57: hotel.v1.ReservationService.ListReservations:output_type -> hotel.v1.ListReservationsResponse
+	1,  // 58: hotel.v1.ReservationService.GetReservation:output_type -> hotel.v1.Reservation
+	1,  // 59: hotel.v1.ReservationService.CreateReservation:output_type -> hotel.v1.Reservation
+	1,  // 60: hotel.v1.ReservationService.UpdateReservation:output_type -> hotel.v1.Reservation
+	17, // 61: hotel.v1.ReservationService.CancelReservation:output_type -> hotel.v1.CancelReservationResponse
+	1,  // 62: hotel.v1.ReservationService.ConfirmReservation:output_type -> hotel.v1.Reservation
+	35, // 63: hotel.v1.ReservationService.DeleteReservation:output_type -> google.protobuf.Empty
+	21, // 64: hotel.v1.ReservationService.WatchReservations:output_type -> hotel.v1.ReservationEvent
+	23, // 65: hotel.v1.ConsultationService.ListConsultations:output_type -> hotel.v1.ListConsultationsResponse
+	3,  // 66: hotel.v1.ConsultationService.GetConsultation:output_type -> hotel.v1.Consultation
+	3,  // 67: hotel.v1.ConsultationService.CreateConsultation:output_type -> hotel.v1.Consultation
+	3,  // 68: hotel.v1.ConsultationService.UpdateConsultation:output_type -> hotel.v1.Consultation
+	35, // 69: hotel.v1.ConsultationService.DeleteConsultation:output_type -> google.protobuf.Empty
+	29, // 70: hotel.v1.EmployeeService.ListEmployees:output_type -> hotel.v1.ListEmployeesResponse
+	4,  // 71: hotel.v1.EmployeeService.GetEmployee:output_type -> hotel.v1.Employee
+	4,  // 72: hotel.v1.EmployeeService.CreateEmployee:output_type -> hotel.v1.Employee
+	4,  // 73: hotel.v1.EmployeeService.UpdateEmployee:output_type -> hotel.v1.Employee
+	35, // 74: hotel.v1.EmployeeService.DeleteEmployee:output_type -> google.protobuf.Empty
+	52, // [52:75] is the sub-list for method output_type
+	29, // [29:52] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
+}
+
+func init() { file_hotelpb_hotel_proto_init() }
+func file_hotelpb_hotel_proto_init() {
+	if File_hotelpb_hotel_proto != nil {
+		return
+	}
+	file_hotelpb_hotel_proto_msgTypes[1].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[2].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[3].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[4].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[11].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[14].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[15].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[20].OneofWrappers = []any{}
+	file_hotelpb_hotel_proto_msgTypes[22].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hotelpb_hotel_proto_rawDesc), len(file_hotelpb_hotel_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   34,
+			NumExtensions: 0,
+			NumServices:   4,
+		},
+		GoTypes:           file_hotelpb_hotel_proto_goTypes,
+		DependencyIndexes: file_hotelpb_hotel_proto_depIdxs,
+		MessageInfos:      file_hotelpb_hotel_proto_msgTypes,
+	}.Build()
+	File_hotelpb_hotel_proto = out.File
+	file_hotelpb_hotel_proto_goTypes = nil
+	file_hotelpb_hotel_proto_depIdxs = nil
+}
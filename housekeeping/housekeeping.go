@@ -0,0 +1,114 @@
+package housekeeping
+
+import (
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Department es el departamento de los empleados que reciben las tareas de limpieza
+const Department = "housekeeping"
+
+// Distribute reparte las tareas entre los empleados por turnos, en el orden recibido. Las tareas ya
+// asignadas se respetan y cuentan para la carga de su empleado
+func Distribute(tasks []models.HousekeepingTask, employeeIDs []uint) {
+	if len(employeeIDs) == 0 {
+		return
+	}
+	load := make(map[uint]int, len(employeeIDs))
+	for _, task := range tasks {
+		if task.EmployeeID != nil {
+			load[*task.EmployeeID]++
+		}
+	}
+	for i := range tasks {
+		if tasks[i].EmployeeID != nil {
+			continue
+		}
+		// Elegir el empleado con menos tareas; ante un empate, el primero de la lista
+		chosen := employeeIDs[0]
+		for _, id := range employeeIDs[1:] {
+			if load[id] < load[chosen] {
+				chosen = id
+			}
+		}
+		load[chosen]++
+		id := chosen
+		tasks[i].EmployeeID = &id
+	}
+}
+
+// housekeepers devuelve los IDs de los empleados del departamento de limpieza
+func housekeepers(tx *gorm.DB) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&models.Employee{}).Where("LOWER(department) = ?", strings.ToLower(Department)).Order("id asc").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Generate crea las tareas de limpieza del día: una limpieza de salida por cada habitación cuyo tramo
+// termina ese día y un repaso por cada habitación ocupada que sigue ocupada. Es idempotente: las tareas
+// que ya existen no se duplican ni se reasignan
+func Generate(tx *gorm.DB, date time.Time) ([]models.HousekeepingTask, error) {
+	date = inventory.Day(date)
+	var assignments []models.RoomAssignment
+	if err := tx.Joins("JOIN reservations ON reservations.id = room_assignments.reservation_id").
+		Where("reservations.deleted_at IS NULL AND reservations.status <> ?", models.ReservationCancelled).
+		Where("room_assignments.start_date <= ? AND room_assignments.end_date >= ?", date, date).
+		Order("room_assignments.room_id asc").Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+
+	var tasks []models.HousekeepingTask
+	seen := make(map[uint]bool)
+	for _, assignment := range assignments {
+		taskType := models.TaskStayover
+		switch {
+		case assignment.EndDate.Equal(date):
+			taskType = models.TaskDeparture
+		case assignment.StartDate.Equal(date):
+			continue // El huésped llega hoy: la habitación ya se preparó
+		}
+		if seen[assignment.RoomID] {
+			continue
+		}
+		seen[assignment.RoomID] = true
+		tasks = append(tasks, models.HousekeepingTask{RoomID: assignment.RoomID, Date: date, Type: taskType, Status: models.TaskPending})
+	}
+
+	// Descartar las tareas que ya se generaron en una ejecución anterior
+	var existing []models.HousekeepingTask
+	if err := tx.Where("date = ?", date).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	generated := make(map[uint]string, len(existing))
+	for _, task := range existing {
+		generated[task.RoomID] = task.Type
+	}
+	pending := tasks[:0]
+	for _, task := range tasks {
+		if generated[task.RoomID] != task.Type {
+			pending = append(pending, task)
+		}
+	}
+	tasks = pending
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	employees, err := housekeepers(tx)
+	if err != nil {
+		return nil, err
+	}
+	// Las tareas existentes cuentan para la carga de cada empleado
+	all := append(existing, tasks...)
+	Distribute(all, employees)
+	copy(tasks, all[len(existing):])
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
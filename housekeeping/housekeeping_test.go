@@ -0,0 +1,37 @@
+package housekeeping
+
+import (
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistribute(t *testing.T) {
+	assigned := uint(1)
+	tasks := []models.HousekeepingTask{
+		{EmployeeID: &assigned},
+		{EmployeeID: &assigned},
+		{},
+		{},
+		{},
+	}
+	Distribute(tasks, []uint{1, 2})
+
+	load := map[uint]int{}
+	for _, task := range tasks {
+		if assert.NotNil(t, task.EmployeeID) {
+			load[*task.EmployeeID]++
+		}
+	}
+	// El empleado 1 ya tenía dos tareas: las nuevas van primero al empleado 2
+	assert.Equal(t, uint(2), *tasks[2].EmployeeID)
+	assert.Equal(t, uint(2), *tasks[3].EmployeeID)
+	assert.Equal(t, map[uint]int{1: 3, 2: 2}, load)
+}
+
+func TestDistributeWithoutEmployees(t *testing.T) {
+	tasks := []models.HousekeepingTask{{}}
+	Distribute(tasks, nil)
+	assert.Nil(t, tasks[0].EmployeeID)
+}
@@ -0,0 +1,269 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNotCalendar se devuelve al leer un archivo que no contiene un VCALENDAR
+var ErrNotCalendar = errors.New("not an iCalendar file")
+
+// StatusCancelled es el estado de un evento cancelado en el calendario de origen
+const StatusCancelled = "CANCELLED"
+
+// Event es un VEVENT. Los eventos de día completo tienen Start y End en medianoche UTC y End es exclusivo
+type Event struct {
+	UID      string
+	Summary  string
+	Status   string
+	Start    time.Time
+	End      time.Time
+	Sequence int
+	Stamp    time.Time
+}
+
+// Calendar es un VCALENDAR con eventos de día completo
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// property es una línea de contenido ya desplegada: NOMBRE;PARAM=valor:VALOR
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfold lee las líneas de contenido uniendo las que continúan en la línea siguiente
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty separa el nombre, los parámetros y el valor de una línea
+func parseProperty(line string) (property, bool) {
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// unescape decodifica un valor de texto
+func unescape(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// escape codifica un valor de texto
+func escape(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// parseTime interpreta un valor DATE o DATE-TIME. Las horas con TZID se interpretan en esa zona si
+// está disponible, y las horas flotantes en UTC
+func parseTime(prop property) (time.Time, bool, error) {
+	value := prop.value
+	if prop.params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	location := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	return t, false, err
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration interpreta un valor DURATION positivo como P1D, P2W o PT12H
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimPrefix(value, "+"))
+	if match == nil || value == "P" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if match[i+1] != "" {
+			n, _ := strconv.Atoi(match[i+1])
+			total += time.Duration(n) * unit
+		}
+	}
+	return total, nil
+}
+
+// Parse lee los eventos de un archivo iCalendar. Los componentes anidados dentro de un evento, como
+// las alarmas, se ignoran. Un evento sin DTEND dura un día si es de día completo, o DURATION si se indica
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrNotCalendar
+	}
+
+	var events []Event
+	var current *Event
+	var duration string
+	allDay := false
+	hasEnd := false
+	depth := 0 // Componentes anidados dentro del evento actual
+	for n, line := range lines {
+		prop, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && current == nil:
+			current = &Event{}
+			duration, allDay, hasEnd, depth = "", false, false, 0
+		case current == nil:
+			continue
+		case prop.name == "BEGIN":
+			depth++
+		case prop.name == "END" && depth > 0:
+			depth--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %q has no DTSTART", n+1, current.UID)
+			}
+			if !hasEnd {
+				current.End = current.Start.AddDate(0, 0, 1)
+				if duration != "" {
+					d, err := parseDuration(duration)
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %w", n+1, err)
+					}
+					current.End = current.Start.Add(d)
+				} else if !allDay {
+					current.End = current.Start
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case depth > 0:
+			continue
+		case prop.name == "UID":
+			current.UID = prop.value
+		case prop.name == "SUMMARY":
+			current.Summary = unescape(prop.value)
+		case prop.name == "STATUS":
+			current.Status = strings.ToUpper(prop.value)
+		case prop.name == "SEQUENCE":
+			current.Sequence, _ = strconv.Atoi(prop.value)
+		case prop.name == "DURATION":
+			duration = prop.value
+		case prop.name == "DTSTAMP":
+			current.Stamp, _, _ = parseTime(prop)
+		case prop.name == "DTSTART", prop.name == "DTEND":
+			t, date, err := parseTime(prop)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", n+1, prop.name, prop.value)
+			}
+			if prop.name == "DTSTART" {
+				current.Start, allDay = t, date
+			} else {
+				current.End, hasEnd = t, true
+			}
+		}
+	}
+	return events, nil
+}
+
+// fold escribe una línea de contenido partiéndola cada 75 octetos sin cortar caracteres UTF-8
+func fold(w *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // La línea siguiente empieza con un espacio
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// Write escribe el calendario con eventos de día completo
+func Write(w io.Writer, calendar Calendar) error {
+	var out strings.Builder
+	fold(&out, "BEGIN:VCALENDAR")
+	fold(&out, "VERSION:2.0")
+	fold(&out, "PRODID:"+calendar.ProdID)
+	fold(&out, "CALSCALE:GREGORIAN")
+	fold(&out, "METHOD:PUBLISH")
+	if calendar.Name != "" {
+		fold(&out, "X-WR-CALNAME:"+escape(calendar.Name))
+	}
+	for _, event := range calendar.Events {
+		fold(&out, "BEGIN:VEVENT")
+		fold(&out, "UID:"+event.UID)
+		fold(&out, "DTSTAMP:"+event.Stamp.UTC().Format("20060102T150405Z"))
+		fold(&out, "DTSTART;VALUE=DATE:"+event.Start.Format("20060102"))
+		fold(&out, "DTEND;VALUE=DATE:"+event.End.Format("20060102"))
+		if event.Summary != "" {
+			fold(&out, "SUMMARY:"+escape(event.Summary))
+		}
+		if event.Sequence > 0 {
+			fold(&out, "SEQUENCE:"+strconv.Itoa(event.Sequence))
+		}
+		if event.Status != "" {
+			fold(&out, "STATUS:"+event.Status)
+		}
+		fold(&out, "TRANSP:OPAQUE")
+		fold(&out, "END:VEVENT")
+	}
+	fold(&out, "END:VCALENDAR")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
@@ -0,0 +1,110 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const airbnbFeed = "BEGIN:VCALENDAR\r\n" +
+	"PRODID;X-RICAL-TZSOURCE=TZINFO:-//Airbnb Inc//Hosting Calendar 0.8.8//EN\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTEND;VALUE=DATE:20250312\r\n" +
+	"DTSTART;VALUE=DATE:20250310\r\n" +
+	"UID:1418fb94e984-abc@airbnb.com\r\n" +
+	"DESCRIPTION:Reservation URL: https://www.airbnb.com/hosting/reservations/details/HMXYZ\\nPhone\r\n" +
+	"  Number (Last 4 Digits): 1234\r\n" +
+	"SUMMARY:Reserved\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"SUMMARY:Alarm\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250320T150000Z\r\n" +
+	"DTEND:20250322T110000Z\r\n" +
+	"UID:second@example.com\r\n" +
+	"SUMMARY:Airbnb (Not available)\\, owner\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SEQUENCE:2\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250401\r\n" +
+	"DURATION:P1W\r\n" +
+	"UID:third@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250501\r\n" +
+	"UID:fourth@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(airbnbFeed))
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+
+	// Evento de día completo: la alarma anidada no pisa el resumen
+	assert.Equal(t, "1418fb94e984-abc@airbnb.com", events[0].UID)
+	assert.Equal(t, "Reserved", events[0].Summary)
+	assert.Equal(t, date(2025, 3, 10), events[0].Start)
+	assert.Equal(t, date(2025, 3, 12), events[0].End)
+
+	// Evento con hora, texto escapado y cancelado
+	assert.Equal(t, time.Date(2025, 3, 20, 15, 0, 0, 0, time.UTC), events[1].Start)
+	assert.Equal(t, "Airbnb (Not available), owner", events[1].Summary)
+	assert.Equal(t, StatusCancelled, events[1].Status)
+	assert.Equal(t, 2, events[1].Sequence)
+
+	// Sin DTEND: DURATION, o un día si es de día completo
+	assert.Equal(t, date(2025, 4, 8), events[2].End)
+	assert.Equal(t, date(2025, 5, 2), events[3].End)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader("<html></html>"))
+	assert.ErrorIs(t, err, ErrNotCalendar)
+
+	_, err = Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nDTSTART:tomorrow\nEND:VEVENT\nEND:VCALENDAR\n"))
+	assert.Error(t, err)
+
+	_, err = Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nEND:VEVENT\nEND:VCALENDAR\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	stamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	calendar := Calendar{
+		ProdID: "-//Hotel//Calendar//ES",
+		Name:   "Hotel; Doble",
+		Events: []Event{{
+			UID:     "reservation-1@hotel",
+			Summary: "No disponible, " + strings.Repeat("ñ", 60),
+			Start:   date(2025, 3, 10),
+			End:     date(2025, 3, 12),
+			Stamp:   stamp,
+		}},
+	}
+
+	var out strings.Builder
+	assert.NoError(t, Write(&out, calendar))
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Contains(t, out.String(), "X-WR-CALNAME:Hotel\\; Doble\r\n")
+
+	events, err := Parse(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, calendar.Events[0].Summary, events[0].Summary)
+	assert.Equal(t, calendar.Events[0].Start, events[0].Start)
+	assert.Equal(t, calendar.Events[0].End, events[0].End)
+	assert.Equal(t, stamp, events[0].Stamp)
+}
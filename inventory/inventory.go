@@ -0,0 +1,236 @@
+package inventory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoAvailability se devuelve cuando no quedan habitaciones del tipo pedido en alguna noche del período
+var ErrNoAvailability = errors.New("no rooms available for the requested dates")
+
+// Hold son habitaciones ocupadas o retenidas de un tipo durante un período [From, To)
+type Hold struct {
+	From  time.Time
+	To    time.Time
+	Rooms int
+}
+
+// Day normaliza una fecha a la medianoche UTC de su día
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MaxOccupied devuelve la mayor cantidad de habitaciones ocupadas en una misma noche entre from y to
+func MaxOccupied(holds []Hold, from, to time.Time) int {
+	max := 0
+	for night := Day(from); night.Before(Day(to)); night = night.AddDate(0, 0, 1) {
+		occupied := 0
+		for _, hold := range holds {
+			if !night.Before(Day(hold.From)) && night.Before(Day(hold.To)) {
+				occupied += hold.Rooms
+			}
+		}
+		if occupied > max {
+			max = occupied
+		}
+	}
+	return max
+}
+
+// Availability devuelve cuántas habitaciones del tipo indicado quedan libres en todas las noches del
+// período. El resultado es -1 si el tipo de habitación no tiene inventario configurado
+func Availability(tx *gorm.DB, roomType string, from, to time.Time) (int, error) {
+	return availability(tx, roomType, from, to, false)
+}
+
+// Reserve comprueba que haya al menos la cantidad de habitaciones pedida en el período. Bloquea el
+// tipo de habitación hasta el final de la transacción para que dos altas simultáneas no sobrevendan
+func Reserve(tx *gorm.DB, roomType string, from, to time.Time, rooms int) error {
+	available, err := availability(tx, roomType, from, to, true)
+	if err != nil {
+		return err
+	}
+	if available >= 0 && available < rooms {
+		return ErrNoAvailability
+	}
+	return nil
+}
+
+// Daily devuelve las habitaciones libres de cada noche entre from y to a partir del total y las ocupaciones
+func Daily(total int, holds []Hold, from, to time.Time) []int {
+	var days []int
+	for day := Day(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		available := total - MaxOccupied(holds, day, day.AddDate(0, 0, 1))
+		if available < 0 {
+			available = 0
+		}
+		days = append(days, available)
+	}
+	return days
+}
+
+// DailyAvailability devuelve las habitaciones libres del tipo indicado en cada noche del período.
+// El resultado es nil si el tipo de habitación no tiene inventario configurado
+func DailyAvailability(tx *gorm.DB, roomType string, from, to time.Time) ([]int, error) {
+	var rt models.RoomType
+	if err := tx.Where("code = ?", roomType).First(&rt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	holds, err := loadHolds(tx, roomType, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Daily(rt.TotalRooms, holds, from, to), nil
+}
+
+func availability(tx *gorm.DB, roomType string, from, to time.Time, lock bool) (int, error) {
+	query := tx.Where("code = ?", roomType)
+	if lock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var rt models.RoomType
+	if err := query.First(&rt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return -1, nil
+		}
+		return 0, err
+	}
+
+	holds, err := loadHolds(tx, roomType, from, to)
+	if err != nil {
+		return 0, err
+	}
+	available := rt.TotalRooms - MaxOccupied(holds, from, to)
+	if available < 0 {
+		available = 0
+	}
+	return available, nil
+}
+
+// loadHolds reúne las reservas confirmadas y el cupo no tomado de los bloqueos de grupo abiertos
+func loadHolds(tx *gorm.DB, roomType string, from, to time.Time) ([]Hold, error) {
+	var reservations []models.Reservation
+	if err := tx.Where("room_type = ? AND status <> ? AND checkin < ? AND checkout > ?",
+		roomType, models.ReservationCancelled, to, from).Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	holds := make([]Hold, 0, len(reservations))
+	for _, reservation := range reservations {
+		rooms := reservation.NumberOfRooms
+		if rooms < 1 {
+			rooms = 1
+		}
+		holds = append(holds, Hold{From: reservation.Checkin, To: reservation.Checkout, Rooms: rooms})
+	}
+
+	var blocks []models.GroupBlock
+	if err := tx.Preload("Allotments", "room_type = ?", roomType).
+		Where("status = ? AND checkin < ? AND checkout > ?", models.GroupBlockOpen, to, from).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		if err := LoadPickups(tx, &block); err != nil {
+			return nil, err
+		}
+		for _, allotment := range block.Allotments {
+			holds = append(holds, Hold{From: block.Checkin, To: block.Checkout, Rooms: allotment.Held()})
+		}
+	}
+
+	// Las habitaciones fuera de orden se descuentan del inventario mientras dure el período
+	var outages []models.RoomOutage
+	if err := overlappingOutages(tx, from, to).
+		Joins("JOIN rooms ON rooms.id = room_outages.room_id AND rooms.deleted_at IS NULL").
+		Where("rooms.room_type = ? AND room_outages.type = ?", roomType, models.OutageOutOfOrder).
+		Find(&outages).Error; err != nil {
+		return nil, err
+	}
+	return append(holds, OutageHolds(outages, to)...), nil
+}
+
+// OutageHolds convierte los períodos fuera de servicio en ocupaciones de una habitación. Los períodos
+// superpuestos de una misma habitación se unen, para que la habitación se descuente una sola vez por noche
+func OutageHolds(outages []models.RoomOutage, fallback time.Time) []Hold {
+	byRoom := make(map[uint][]Hold)
+	var rooms []uint
+	for _, outage := range outages {
+		if _, ok := byRoom[outage.RoomID]; !ok {
+			rooms = append(rooms, outage.RoomID)
+		}
+		byRoom[outage.RoomID] = append(byRoom[outage.RoomID], Hold{From: Day(outage.StartDate), To: Day(OutageEnd(outage, fallback)), Rooms: 1})
+	}
+
+	var holds []Hold
+	for _, room := range rooms {
+		periods := byRoom[room]
+		sort.Slice(periods, func(i, j int) bool { return periods[i].From.Before(periods[j].From) })
+		merged := periods[:1]
+		for _, period := range periods[1:] {
+			last := &merged[len(merged)-1]
+			if period.From.After(last.To) {
+				merged = append(merged, period)
+				continue
+			}
+			if period.To.After(last.To) {
+				last.To = period.To
+			}
+		}
+		holds = append(holds, merged...)
+	}
+	return holds
+}
+
+// LoadPickups completa la cantidad de habitaciones tomadas de cada cupo del bloqueo
+func LoadPickups(tx *gorm.DB, block *models.GroupBlock) error {
+	var counts []struct {
+		RoomType string
+		Rooms    int
+	}
+	if err := tx.Model(&models.Reservation{}).
+		Select("room_type, COUNT(*) AS rooms").
+		Where("group_block_id = ? AND status <> ?", block.ID, models.ReservationCancelled).
+		Group("room_type").Scan(&counts).Error; err != nil {
+		return err
+	}
+	for i := range block.Allotments {
+		block.Allotments[i].PickedUp = 0
+		for _, count := range counts {
+			if count.RoomType == block.Allotments[i].RoomType {
+				block.Allotments[i].PickedUp = count.Rooms
+			}
+		}
+	}
+	return nil
+}
+
+// Release libera el cupo que ningún huésped tomó y cierra el bloqueo a nuevas tomas
+func Release(tx *gorm.DB, block *models.GroupBlock, now time.Time) error {
+	// Bloquear el bloqueo de grupo para no liberar mientras un huésped toma una habitación
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Allotments").First(block, block.ID).Error; err != nil {
+		return err
+	}
+	if block.Status == models.GroupBlockReleased {
+		return nil
+	}
+	if err := LoadPickups(tx, block); err != nil {
+		return err
+	}
+	for i := range block.Allotments {
+		block.Allotments[i].Released += block.Allotments[i].Held()
+		if err := tx.Model(&block.Allotments[i]).Update("released", block.Allotments[i].Released).Error; err != nil {
+			return err
+		}
+	}
+	block.Status = models.GroupBlockReleased
+	block.ReleasedAt = &now
+	return tx.Model(block).Select("Status", "ReleasedAt").Updates(block).Error
+}
@@ -0,0 +1,83 @@
+package inventory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(d int) time.Time {
+	return time.Date(2024, time.March, d, 14, 0, 0, 0, time.UTC)
+}
+
+func TestMaxOccupied(t *testing.T) {
+	holds := []Hold{
+		{From: date(1), To: date(4), Rooms: 2},
+		{From: date(3), To: date(5), Rooms: 3},
+		{From: date(5), To: date(6), Rooms: 10}, // Empieza el día de salida: no se superpone
+	}
+	assert.Equal(t, 5, MaxOccupied(holds, date(1), date(5)))
+	assert.Equal(t, 2, MaxOccupied(holds, date(1), date(3)))
+	assert.Equal(t, 3, MaxOccupied(holds, date(4), date(5)))
+	assert.Equal(t, 0, MaxOccupied(holds, date(6), date(8)))
+}
+
+func TestDaily(t *testing.T) {
+	holds := []Hold{
+		{From: Day(date(1)), To: Day(date(3)), Rooms: 2},
+		{From: Day(date(2)), To: Day(date(4)), Rooms: 3},
+	}
+	// Las noches sobrevendidas informan cero habitaciones libres
+	assert.Equal(t, []int{2, 0, 1, 4}, Daily(4, holds, date(1), Day(date(5))))
+	assert.Empty(t, Daily(4, holds, Day(date(5)), Day(date(5))))
+}
+
+func TestAllotmentHeld(t *testing.T) {
+	assert.Equal(t, 7, models.GroupAllotment{Rooms: 10, PickedUp: 3}.Held())
+	assert.Equal(t, 0, models.GroupAllotment{Rooms: 10, PickedUp: 4, Released: 6}.Held())
+	assert.Equal(t, 0, models.GroupAllotment{Rooms: 2, PickedUp: 3}.Held())
+}
+
+func TestSuggest(t *testing.T) {
+	room := func(id uint) models.Room {
+		r := models.Room{Number: string(rune('A' + id))}
+		r.ID = id
+		return r
+	}
+	first := models.Reservation{}
+	first.ID = 1
+	second := models.Reservation{}
+	second.ID = 2
+
+	suggestions := Suggest(
+		[]models.Reservation{first, second},
+		map[uint][]models.Room{1: {room(10), room(11)}, 2: {room(10), room(11), room(12)}},
+		map[uint]int{1: 1, 2: 2},
+	)
+	assert.Equal(t, []models.Room{room(10)}, suggestions[1])
+	assert.Equal(t, []models.Room{room(11), room(12)}, suggestions[2]) // La habitación 10 ya fue propuesta
+}
+
+func TestOutageEnd(t *testing.T) {
+	end := date(10)
+	assert.Equal(t, end, OutageEnd(models.RoomOutage{EndDate: &end}, date(20)))
+	// Un período abierto se extiende hasta el final del rango consultado
+	assert.Equal(t, date(20), OutageEnd(models.RoomOutage{}, date(20)))
+}
+
+func TestOutageHolds(t *testing.T) {
+	end := date(5)
+	later := date(8)
+	outages := []models.RoomOutage{
+		{RoomID: 1, StartDate: date(1), EndDate: &end},
+		{RoomID: 1, StartDate: date(3), EndDate: &later},
+		{RoomID: 2, StartDate: date(4)},
+	}
+	holds := OutageHolds(outages, date(10))
+	// Los dos períodos de la habitación 1 se superponen: descuentan una sola habitación por noche
+	assert.Equal(t, 1, MaxOccupied(holds, date(1), date(4)))
+	assert.Equal(t, 2, MaxOccupied(holds, date(4), date(8)))
+	assert.Equal(t, 1, MaxOccupied(holds, date(8), date(10)))
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// busyRooms devuelve la subconsulta de habitaciones asignadas a reservas vigentes que se superponen con [start, end)
+func busyRooms(tx *gorm.DB, start, end time.Time) *gorm.DB {
+	return tx.Model(&models.RoomAssignment{}).
+		Select("room_assignments.room_id").
+		Joins("JOIN reservations ON reservations.id = room_assignments.reservation_id").
+		Where("room_assignments.start_date < ? AND room_assignments.end_date > ?", Day(end), Day(start)).
+		Where("reservations.deleted_at IS NULL AND reservations.status <> ?", models.ReservationCancelled)
+}
+
+// overlappingOutages devuelve la consulta de períodos fuera de servicio que se superponen con [start, end)
+func overlappingOutages(tx *gorm.DB, start, end time.Time) *gorm.DB {
+	return tx.Model(&models.RoomOutage{}).
+		Where("room_outages.start_date < ? AND (room_outages.end_date IS NULL OR room_outages.end_date > ?)", Day(end), Day(start))
+}
+
+// OutageEnd devuelve el fin del período fuera de servicio; los períodos abiertos se extienden hasta fallback
+func OutageEnd(outage models.RoomOutage, fallback time.Time) time.Time {
+	if outage.EndDate != nil {
+		return *outage.EndDate
+	}
+	return fallback
+}
+
+// RoomFree indica si la habitación no está asignada a otra reserva ni fuera de servicio en ninguna noche de [start, end)
+func RoomFree(tx *gorm.DB, roomID uint, start, end time.Time) (bool, error) {
+	var count int64
+	if err := busyRooms(tx, start, end).Where("room_assignments.room_id = ?", roomID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	err := overlappingOutages(tx, start, end).Where("room_outages.room_id = ?", roomID).Count(&count).Error
+	return count == 0, err
+}
+
+// FreeRooms devuelve las habitaciones en servicio del tipo indicado que están libres en todo [start, end)
+func FreeRooms(tx *gorm.DB, roomType string, start, end time.Time) ([]models.Room, error) {
+	var rooms []models.Room
+	err := tx.Where("room_type = ? AND status = ?", roomType, models.RoomInService).
+		Where("id NOT IN (?)", busyRooms(tx, start, end)).
+		Where("id NOT IN (?)", overlappingOutages(tx, start, end).Select("room_outages.room_id")).
+		Order("number asc").Find(&rooms).Error
+	return rooms, err
+}
+
+// Suggest propone habitaciones para las llegadas, en orden, sin repetir una habitación entre reservas.
+// candidates son las habitaciones libres de cada reserva y missing cuántas le faltan asignar
+func Suggest(arrivals []models.Reservation, candidates map[uint][]models.Room, missing map[uint]int) map[uint][]models.Room {
+	taken := make(map[uint]bool)
+	suggestions := make(map[uint][]models.Room)
+	for _, reservation := range arrivals {
+		for _, room := range candidates[reservation.ID] {
+			if len(suggestions[reservation.ID]) >= missing[reservation.ID] {
+				break
+			}
+			if taken[room.ID] {
+				continue
+			}
+			taken[room.ID] = true
+			suggestions[reservation.ID] = append(suggestions[reservation.ID], room)
+		}
+	}
+	return suggestions
+}
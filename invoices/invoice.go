@@ -0,0 +1,149 @@
+package invoices
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Hotel contiene los datos del establecimiento que se imprimen en los comprobantes
+type Hotel struct {
+	Name     string
+	Address  string
+	TaxID    string
+	Email    string
+	Phone    string
+	Currency string
+	TaxRate  float64 // Alícuota por defecto aplicada a los cargos
+}
+
+// LoadHotel lee los datos del hotel desde las variables de entorno
+func LoadHotel() Hotel {
+	hotel := Hotel{
+		Name:     os.Getenv("HOTEL_NAME"),
+		Address:  os.Getenv("HOTEL_ADDRESS"),
+		TaxID:    os.Getenv("HOTEL_TAX_ID"),
+		Email:    os.Getenv("HOTEL_EMAIL"),
+		Phone:    os.Getenv("HOTEL_PHONE"),
+		Currency: os.Getenv("HOTEL_CURRENCY"),
+		TaxRate:  0.21,
+	}
+	if hotel.Name == "" {
+		hotel.Name = "Hotel"
+	}
+	if hotel.Currency == "" {
+		hotel.Currency = "ARS"
+	}
+	if rate, err := strconv.ParseFloat(os.Getenv("HOTEL_TAX_RATE"), 64); err == nil {
+		hotel.TaxRate = rate
+	}
+	return hotel
+}
+
+// TaxLine es el total de base imponible e impuesto para una alícuota
+type TaxLine struct {
+	Rate float64
+	Net  float64
+	Tax  float64
+}
+
+// series devuelve el prefijo de numeración de cada tipo de comprobante
+func series(kind string) string {
+	if kind == models.InvoiceKindCreditNote {
+		return "NC"
+	}
+	return "F"
+}
+
+// NextNumber reserva el siguiente número de la serie. Debe llamarse dentro de la misma
+// transacción que crea el comprobante: la fila de la secuencia queda bloqueada hasta el commit
+// y, si la transacción falla, el número vuelve a estar disponible, por lo que no quedan huecos
+// ni se reutilizan números
+func NextNumber(tx *gorm.DB, kind string) (string, error) {
+	prefix := series(kind)
+	// Crear la serie si no existe, sin pisar el contador de otra transacción
+	seq := models.InvoiceSequence{Series: prefix}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seq).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&seq, "series = ?", prefix).Error; err != nil {
+		return "", err
+	}
+	seq.LastNumber++
+	if err := tx.Save(&seq).Error; err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%08d", prefix, seq.LastNumber), nil
+}
+
+// round redondea un importe a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// NewLine calcula el desglose de una línea cuyo importe incluye impuestos
+func NewLine(description string, total, taxRate float64) models.InvoiceLine {
+	net := round(total / (1 + taxRate))
+	return models.InvoiceLine{
+		Description: description,
+		TaxRate:     taxRate,
+		Net:         net,
+		Tax:         round(total - net),
+		Total:       round(total),
+	}
+}
+
+// Totals completa la base imponible, el impuesto y el total del comprobante a partir de sus líneas
+func Totals(invoice *models.Invoice) {
+	invoice.Net, invoice.Tax, invoice.Total = 0, 0, 0
+	for _, line := range invoice.Lines {
+		invoice.Net += line.Net
+		invoice.Tax += line.Tax
+		invoice.Total += line.Total
+	}
+	invoice.Net = round(invoice.Net)
+	invoice.Tax = round(invoice.Tax)
+	invoice.Total = round(invoice.Total)
+}
+
+// TaxBreakdown agrupa las líneas del comprobante por alícuota
+func TaxBreakdown(invoice models.Invoice) []TaxLine {
+	byRate := make(map[float64]*TaxLine)
+	for _, line := range invoice.Lines {
+		tl, ok := byRate[line.TaxRate]
+		if !ok {
+			tl = &TaxLine{Rate: line.TaxRate}
+			byRate[line.TaxRate] = tl
+		}
+		tl.Net += line.Net
+		tl.Tax += line.Tax
+	}
+
+	breakdown := make([]TaxLine, 0, len(byRate))
+	for _, tl := range byRate {
+		tl.Net = round(tl.Net)
+		tl.Tax = round(tl.Tax)
+		breakdown = append(breakdown, *tl)
+	}
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Rate < breakdown[j].Rate })
+	return breakdown
+}
+
+// Issue numera y guarda un comprobante junto con sus líneas dentro de la transacción indicada
+func Issue(tx *gorm.DB, invoice *models.Invoice) error {
+	number, err := NextNumber(tx, invoice.Kind)
+	if err != nil {
+		return err
+	}
+	invoice.Number = number
+	invoice.IssuedAt = time.Now()
+	Totals(invoice)
+	return tx.Create(invoice).Error
+}
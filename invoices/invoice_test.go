@@ -0,0 +1,68 @@
+package invoices
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleInvoice() models.Invoice {
+	invoice := models.Invoice{
+		Number:        "F-00000001",
+		Kind:          models.InvoiceKindInvoice,
+		ReservationID: 7,
+		IssuedAt:      time.Date(2024, 11, 15, 11, 0, 0, 0, time.UTC),
+		GuestName:     "Gabriela Gómez",
+		GuestEmail:    "ggomez@gmail.com",
+		Currency:      "ARS",
+		Lines: []models.InvoiceLine{
+			NewLine("Alojamiento Suite (5 noches)", 1210, 0.21),
+			NewLine("Desayuno", 121, 0.21),
+			NewLine("Traslado", 105, 0.05),
+		},
+	}
+	Totals(&invoice)
+	return invoice
+}
+
+func TestNewLineSplitsTaxIncludedAmount(t *testing.T) {
+	line := NewLine("Alojamiento", 1210, 0.21)
+	assert.Equal(t, 1000.0, line.Net)
+	assert.Equal(t, 210.0, line.Tax)
+	assert.Equal(t, 1210.0, line.Total)
+}
+
+func TestTotalsAndTaxBreakdown(t *testing.T) {
+	invoice := sampleInvoice()
+	assert.Equal(t, 1436.0, invoice.Total)
+	assert.Equal(t, 1200.0, invoice.Net)
+	assert.Equal(t, 236.0, invoice.Tax)
+
+	breakdown := TaxBreakdown(invoice)
+	assert.Len(t, breakdown, 2)
+	assert.Equal(t, TaxLine{Rate: 0.05, Net: 100, Tax: 5}, breakdown[0])
+	assert.Equal(t, TaxLine{Rate: 0.21, Net: 1100, Tax: 231}, breakdown[1])
+}
+
+func TestRenderInvoice(t *testing.T) {
+	invoice := sampleInvoice()
+	hotel := Hotel{Name: "Hotel Central", Address: "Av. Siempre Viva 742", Currency: "ARS", TaxRate: 0.21}
+
+	var pdf bytes.Buffer
+	assert.NoError(t, RenderPDF(&pdf, hotel, invoice))
+	assert.True(t, bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")))
+
+	var html bytes.Buffer
+	assert.NoError(t, RenderHTML(&html, hotel, invoice))
+	assert.Contains(t, html.String(), "Factura F-00000001")
+	assert.Contains(t, html.String(), "Gabriela Gómez")
+	assert.Contains(t, html.String(), "1436.00")
+
+	invoice.Kind = models.InvoiceKindCreditNote
+	html.Reset()
+	assert.NoError(t, RenderHTML(&html, hotel, invoice))
+	assert.Contains(t, html.String(), "Nota de crédito F-00000001")
+}
@@ -0,0 +1,146 @@
+package invoices
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/go-pdf/fpdf"
+)
+
+// title devuelve el encabezado que corresponde al tipo de comprobante
+func title(invoice models.Invoice) string {
+	if invoice.Kind == models.InvoiceKindCreditNote {
+		return "Nota de crédito"
+	}
+	return "Factura"
+}
+
+// RenderPDF escribe el comprobante en formato PDF
+func RenderPDF(w io.Writer, hotel Hotel, invoice models.Invoice) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // Las fuentes estándar usan cp1252
+	pdf.SetTitle(fmt.Sprintf("%s %s", title(invoice), invoice.Number), true)
+	pdf.AddPage()
+
+	// Encabezado con los datos del hotel
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, tr(hotel.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{hotel.Address, hotel.TaxID, hotel.Email, hotel.Phone} {
+		if line != "" {
+			pdf.CellFormat(0, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+	}
+	pdf.Ln(4)
+
+	// Datos del comprobante y del huésped
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 7, tr(fmt.Sprintf("%s %s", title(invoice), invoice.Number)), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr("Fecha: "+invoice.IssuedAt.Format("02/01/2006")), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr(fmt.Sprintf("Reserva: %d", invoice.ReservationID)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Huésped: "+invoice.GuestName), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Email: "+invoice.GuestEmail), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	// Líneas del comprobante
+	widths := []float64{90, 25, 25, 25, 25}
+	pdf.SetFont("Helvetica", "B", 10)
+	for i, header := range []string{"Concepto", "Neto", "IVA %", "IVA", "Total"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, tr(header), "B", 0, align, false, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range invoice.Lines {
+		pdf.CellFormat(widths[0], 6, tr(line.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, fmt.Sprintf("%.2f", line.Net), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, fmt.Sprintf("%.2f", line.TaxRate*100), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, fmt.Sprintf("%.2f", line.Tax), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, fmt.Sprintf("%.2f", line.Total), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	// Desglose de impuestos y totales
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, tr("Desglose de impuestos"), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, tl := range TaxBreakdown(invoice) {
+		pdf.CellFormat(0, 5, tr(fmt.Sprintf("IVA %.2f%%: base %.2f, impuesto %.2f", tl.Rate*100, tl.Net, tl.Tax)), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("Neto: %.2f %s", invoice.Net, invoice.Currency)), "", 1, "R", false, 0, "")
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("IVA: %.2f %s", invoice.Tax, invoice.Currency)), "", 1, "R", false, 0, "")
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("Total: %.2f %s", invoice.Total, invoice.Currency)), "", 1, "R", false, 0, "")
+
+	return pdf.Output(w)
+}
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money":   func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"percent": func(v float64) string { return fmt.Sprintf("%.2f", v*100) },
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+thead th { border-bottom: 1px solid #000; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Hotel.Name}}</h1>
+{{with .Hotel.Address}}<div>{{.}}</div>{{end}}
+{{with .Hotel.TaxID}}<div>{{.}}</div>{{end}}
+{{with .Hotel.Email}}<div>{{.}}</div>{{end}}
+{{with .Hotel.Phone}}<div>{{.}}</div>{{end}}
+</header>
+<h2>{{.Title}} {{.Invoice.Number}}</h2>
+<p>
+Fecha: {{.Invoice.IssuedAt.Format "02/01/2006"}}<br>
+Reserva: {{.Invoice.ReservationID}}<br>
+Huésped: {{.Invoice.GuestName}}<br>
+Email: {{.Invoice.GuestEmail}}
+</p>
+<table>
+<thead><tr><th>Concepto</th><th>Neto</th><th>IVA %</th><th>IVA</th><th>Total</th></tr></thead>
+<tbody>
+{{range .Invoice.Lines}}<tr><td>{{.Description}}</td><td>{{money .Net}}</td><td>{{percent .TaxRate}}</td><td>{{money .Tax}}</td><td>{{money .Total}}</td></tr>
+{{end}}</tbody>
+</table>
+<h3>Desglose de impuestos</h3>
+<table>
+<thead><tr><th>Alícuota</th><th>Base</th><th>Impuesto</th></tr></thead>
+<tbody>
+{{range .Breakdown}}<tr><td>{{percent .Rate}} %</td><td>{{money .Net}}</td><td>{{money .Tax}}</td></tr>
+{{end}}</tbody>
+</table>
+<p>
+Neto: {{money .Invoice.Net}} {{.Invoice.Currency}}<br>
+IVA: {{money .Invoice.Tax}} {{.Invoice.Currency}}<br>
+<strong>Total: {{money .Invoice.Total}} {{.Invoice.Currency}}</strong>
+</p>
+</body>
+</html>
+`))
+
+// RenderHTML escribe el comprobante como página HTML
+func RenderHTML(w io.Writer, hotel Hotel, invoice models.Invoice) error {
+	return htmlTemplate.Execute(w, struct {
+		Title     string
+		Hotel     Hotel
+		Invoice   models.Invoice
+		Breakdown []TaxLine
+	}{title(invoice), hotel, invoice, TaxBreakdown(invoice)})
+}
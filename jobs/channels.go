@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/channels"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// activeChannels devuelve los canales activos con sus mapeos
+func activeChannels() ([]models.Channel, error) {
+	var list []models.Channel
+	err := db.DB.Preload("RoomMappings").Preload("RateMappings").Where("active = ?", true).Find(&list).Error
+	return list, err
+}
+
+// PushChannelARI envía a cada canal activo la disponibilidad, las tarifas y las restricciones de los
+// próximos CHANNEL_ARI_DAYS días. El fallo de un canal no impide sincronizar los demás
+func PushChannelARI() error {
+	list, err := activeChannels()
+	if err != nil {
+		return err
+	}
+	from := inventory.Day(time.Now())
+	to := from.AddDate(0, 0, channels.ARIDays())
+	for _, channel := range list {
+		if _, err := channels.Push(db.DB, channels.NewClient(channel), channel, from, to); err != nil {
+			log.Printf("ARI push to channel %s failed: %v", channel.Code, err)
+		}
+	}
+	return nil
+}
+
+// PullChannelReservations importa las reservas de cada canal activo
+func PullChannelReservations() error {
+	list, err := activeChannels()
+	if err != nil {
+		return err
+	}
+	for _, channel := range list {
+		result, err := channels.Pull(db.DB, channels.NewClient(channel), channel, nil)
+		if err != nil {
+			log.Printf("reservation pull from channel %s failed: %v", channel.Code, err)
+			continue
+		}
+		if result.Created+result.Modified+result.Cancelled+result.Conflicts > 0 {
+			log.Printf("channel %s: %d created, %d modified, %d cancelled, %d conflicts",
+				channel.Code, result.Created, result.Modified, result.Cancelled, result.Conflicts)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+)
+
+// DispatchEvents entrega a los suscriptores los eventos de dominio pendientes
+func DispatchEvents() error {
+	dispatched, err := events.Dispatch(db.DB, time.Now())
+	if dispatched > 0 {
+		log.Printf("dispatched %d domain events", dispatched)
+	}
+	return err
+}
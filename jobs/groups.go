@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// ReleaseGroupBlocks libera el cupo no tomado de los bloqueos de grupo cuya fecha de liberación ya pasó
+func ReleaseGroupBlocks() error {
+	now := time.Now()
+	var blocks []models.GroupBlock
+	if err := db.DB.Preload("Allotments").Where("status = ? AND release_date < ?", models.GroupBlockOpen, now).Find(&blocks).Error; err != nil {
+		return err
+	}
+	for i := range blocks {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			return inventory.Release(tx, &blocks[i], now)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("released group block %s", blocks[i].Code)
+	}
+	return nil
+}
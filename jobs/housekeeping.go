@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/housekeeping"
+	"gorm.io/gorm"
+)
+
+// GenerateHousekeepingTasks genera las tareas de limpieza de hoy. Como la generación es idempotente
+// puede ejecutarse varias veces al día para incluir las asignaciones nuevas
+func GenerateHousekeepingTasks() error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		tasks, err := housekeeping.Generate(tx, time.Now())
+		if err == nil && len(tasks) > 0 {
+			log.Printf("generated %d housekeeping tasks", len(tasks))
+		}
+		return err
+	})
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/notifications"
+)
+
+// DeliverNotifications entrega las notificaciones pendientes de la bandeja de salida
+func DeliverNotifications() error {
+	if notifications.DefaultSender == nil {
+		return nil
+	}
+	sent, err := notifications.Deliver(db.DB, notifications.DefaultSender, time.Now())
+	if sent > 0 {
+		log.Printf("sent %d notifications", sent)
+	}
+	return err
+}
+
+// SendPreArrivalReminders encola el recordatorio para las reservas confirmadas que llegan mañana.
+// Cada recordatorio lleva una referencia única, así que la tarea puede repetirse sin duplicarlos
+func SendPreArrivalReminders() error {
+	tomorrow := inventory.Day(time.Now()).AddDate(0, 0, 1)
+	var reservations []models.Reservation
+	if err := db.DB.Where("status = ? AND checkin >= ? AND checkin < ?", models.ReservationConfirmed, tomorrow, tomorrow.AddDate(0, 0, 1)).
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+	for _, reservation := range reservations {
+		reference := fmt.Sprintf("%s:%d:%s", notifications.PreArrivalReminder, reservation.ID, tomorrow.Format("2006-01-02"))
+		if err := notifications.NotifyReservation(db.DB, notifications.PreArrivalReminder, reservation, reference); err != nil {
+			return err
+		}
+	}
+	return nil
+}
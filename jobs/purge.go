@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// PurgeRetention devuelve cuánto tiempo se conservan los registros eliminados antes de
+// borrarlos definitivamente. Se configura en días con PURGE_RETENTION_DAYS (30 por defecto)
+func PurgeRetention() time.Duration {
+	days := 30
+	if value, err := strconv.Atoi(os.Getenv("PURGE_RETENTION_DAYS")); err == nil && value > 0 {
+		days = value
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// purgeStep es un tipo de registro que se purga: las subconsultas de referencedBy (NOT EXISTS) impiden
+// borrarlo, y los registros dependientes se borran junto con él
+type purgeStep struct {
+	model        interface{}
+	table        string
+	referencedBy []string
+	dependents   []purgeDependent
+}
+
+// purgeDependent son los registros de otra tabla que se borran con el registro purgado
+type purgeDependent struct {
+	model  interface{}
+	column string
+}
+
+// purgeSteps están en orden de dependencia: cada paso se ejecuta después de purgar los registros que
+// podían referenciarlo. Las reservas con folio, pagos o facturas se conservan porque son registros
+// contables; sus habitaciones asignadas son el historial de la estancia y se borran con ellas. Los
+// usuarios con reservas o consultas, aunque estén eliminadas, se conservan por la clave externa
+var purgeSteps = []purgeStep{
+	{
+		model:      &models.Consultation{},
+		table:      "consultations",
+		dependents: []purgeDependent{{model: &models.ConsultationMessage{}, column: "consultation_id"}},
+	},
+	{
+		model: &models.Reservation{},
+		table: "reservations",
+		referencedBy: []string{
+			"SELECT 1 FROM folios WHERE folios.reservation_id = reservations.id",
+			"SELECT 1 FROM payments WHERE payments.reservation_id = reservations.id",
+			"SELECT 1 FROM invoices WHERE invoices.reservation_id = reservations.id",
+			"SELECT 1 FROM consultations WHERE consultations.reservation_id = reservations.id",
+			"SELECT 1 FROM channel_conflicts WHERE channel_conflicts.reservation_id = reservations.id",
+		},
+		dependents: []purgeDependent{{model: &models.RoomAssignment{}, column: "reservation_id"}},
+	},
+	{
+		model: &models.Employee{},
+		table: "employees",
+		referencedBy: []string{
+			"SELECT 1 FROM consultations WHERE consultations.assigned_employee_id = employees.id OR consultations.converted_by_id = employees.id",
+			"SELECT 1 FROM consultation_messages WHERE consultation_messages.employee_id = employees.id",
+			"SELECT 1 FROM housekeeping_tasks WHERE housekeeping_tasks.employee_id = employees.id",
+			"SELECT 1 FROM work_orders WHERE work_orders.employee_id = employees.id",
+		},
+	},
+	{
+		model: &models.User{},
+		table: "users",
+		referencedBy: []string{
+			"SELECT 1 FROM reservations WHERE reservations.user_id = users.id",
+			"SELECT 1 FROM consultations WHERE consultations.user_id = users.id",
+		},
+	},
+}
+
+// PurgeDeleted borra definitivamente los usuarios, reservas, consultas y empleados que fueron
+// eliminados lógicamente hace más tiempo que la retención indicada. Los registros que todavía están
+// referenciados se conservan hasta que dejen de estarlo, así la purga nunca deja registros huérfanos
+// ni falla por una clave externa
+func PurgeDeleted(retention time.Duration) error {
+	cutoff := time.Now().Add(-retention)
+	for _, step := range purgeSteps {
+		var ids []uint
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			query := tx.Unscoped().Model(step.model).Where(step.table+".deleted_at IS NOT NULL AND "+step.table+".deleted_at < ?", cutoff)
+			for _, reference := range step.referencedBy {
+				query = query.Where("NOT EXISTS (" + reference + ")")
+			}
+			if err := query.Pluck(step.table+".id", &ids).Error; err != nil || len(ids) == 0 {
+				return err
+			}
+			for _, dependent := range step.dependents {
+				if err := tx.Unscoped().Where(dependent.column+" IN ?", ids).Delete(dependent.model).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Where("id IN ?", ids).Delete(step.model).Error
+		})
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			log.Printf("purged %d %T records", len(ids), step.model)
+		}
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys borra las claves de idempotencia vencidas
+func PurgeIdempotencyKeys() error {
+	result := db.DB.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyKey{})
+	if result.Error == nil && result.RowsAffected > 0 {
+		log.Printf("purged %d expired idempotency keys", result.RowsAffected)
+	}
+	return result.Error
+}
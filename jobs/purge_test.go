@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurgeDeletedKeepsReferencedRecords(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.User{}, &models.Reservation{}, &models.Room{}, &models.RoomAssignment{})
+	db.DB.AutoMigrate(&models.Consultation{}, &models.ConsultationMessage{}, &models.Employee{})
+	db.DB.AutoMigrate(&models.HousekeepingTask{}, &models.WorkOrder{})
+	db.DB.AutoMigrate(&models.Folio{}, &models.FolioEntry{}, &models.Payment{}, &models.Invoice{}, &models.ChannelConflict{})
+	defer func() {
+		for _, table := range []string{"room_assignments", "rooms", "folios", "reservations", "consultation_messages", "consultations", "users"} {
+			db.DB.Exec("DELETE FROM " + table)
+		}
+	}()
+
+	// Registros eliminados hace más tiempo que la retención
+	expired := func(model interface{}) {
+		assert.NoError(t, db.DB.Delete(model).Error)
+		assert.NoError(t, db.DB.Unscoped().Model(model).Update("deleted_at", time.Now().AddDate(0, 0, -60)).Error)
+	}
+
+	// Un usuario eliminado con una reserva activa se conserva por la clave externa
+	guest := models.User{FirstName: "Ana", LastName: "García", Email: "ana.garcia@example.com"}
+	assert.NoError(t, db.DB.Create(&guest).Error)
+	active := models.Reservation{UserID: guest.ID, Email: guest.Email, Checkin: time.Now(), Checkout: time.Now().AddDate(0, 0, 2), NumberOfRooms: 1}
+	assert.NoError(t, db.DB.Create(&active).Error)
+	expired(&guest)
+
+	// Una reserva eliminada con folio se conserva; otra sin registros contables se borra con sus habitaciones
+	billed := models.Reservation{UserID: guest.ID, Email: guest.Email, Checkin: time.Now(), Checkout: time.Now().AddDate(0, 0, 1), NumberOfRooms: 1}
+	assert.NoError(t, db.DB.Create(&billed).Error)
+	assert.NoError(t, db.DB.Create(&models.Folio{ReservationID: &billed.ID}).Error)
+	expired(&billed)
+
+	room := models.Room{Number: "101", RoomType: "double"}
+	assert.NoError(t, db.DB.Create(&room).Error)
+	unbilled := models.Reservation{UserID: guest.ID, Email: guest.Email, Checkin: time.Now(), Checkout: time.Now().AddDate(0, 0, 1), NumberOfRooms: 1}
+	assert.NoError(t, db.DB.Create(&unbilled).Error)
+	assert.NoError(t, db.DB.Create(&models.RoomAssignment{ReservationID: unbilled.ID, RoomID: room.ID, StartDate: unbilled.Checkin, EndDate: unbilled.Checkout}).Error)
+	expired(&unbilled)
+
+	// Un usuario eliminado cuyas consultas también se purgan se borra en la misma pasada
+	former := models.User{FirstName: "Luis", LastName: "Pérez", Email: "luis.perez@example.com"}
+	assert.NoError(t, db.DB.Create(&former).Error)
+	consultation := models.Consultation{UserID: former.ID, Consultation: "¿Tienen estacionamiento?"}
+	assert.NoError(t, db.DB.Create(&consultation).Error)
+	assert.NoError(t, db.DB.Create(&models.ConsultationMessage{ConsultationID: consultation.ID, Author: models.MessageFromStaff, Body: "Sí"}).Error)
+	expired(&consultation)
+	expired(&former)
+
+	assert.NoError(t, PurgeDeleted(30*24*time.Hour))
+
+	count := func(model interface{}, id uint) int64 {
+		var n int64
+		db.DB.Unscoped().Model(model).Where("id = ?", id).Count(&n)
+		return n
+	}
+	assert.Equal(t, int64(1), count(&models.User{}, guest.ID))
+	assert.Equal(t, int64(1), count(&models.Reservation{}, billed.ID))
+	assert.Equal(t, int64(0), count(&models.Reservation{}, unbilled.ID))
+	assert.Equal(t, int64(0), count(&models.Consultation{}, consultation.ID))
+	assert.Equal(t, int64(0), count(&models.User{}, former.ID))
+
+	var assignments int64
+	db.DB.Model(&models.RoomAssignment{}).Where("reservation_id = ?", unbilled.ID).Count(&assignments)
+	assert.Equal(t, int64(0), assignments)
+	var messages int64
+	db.DB.Unscoped().Model(&models.ConsultationMessage{}).Where("consultation_id = ?", consultation.ID).Count(&messages)
+	assert.Equal(t, int64(0), messages)
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Every ejecuta la tarea indicada en segundo plano cada intervalo, empezando inmediatamente.
+// Los errores se registran en el log y no detienen las ejecuciones siguientes
+func Every(interval time.Duration, name string, task func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := task(); err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/webhooks"
+)
+
+// DeliverWebhooks envía las entregas de webhooks pendientes
+func DeliverWebhooks() error {
+	delivered, err := webhooks.Deliver(db.DB, webhooks.Client, time.Now())
+	if delivered > 0 {
+		log.Printf("delivered %d webhooks", delivered)
+	}
+	return err
+}
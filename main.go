@@ -1,55 +1,92 @@
 package main
 
 import (
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/jobs"
 	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/notifications"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/payments"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/routes"
-	"github.com/gorilla/mux"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/webhooks"
 )
 
 func main() {
 	db.DBConnection()
 
 	// Migración de las tablas necesarias en la base de datos
+	// El email solo es único entre los registros no eliminados: se reemplaza el índice que también incluía a los eliminados
+	var emailIndexes []string
+	db.DB.Raw("SELECT indexname FROM pg_indexes WHERE indexname IN ('idx_users_email', 'idx_employees_email') AND indexdef NOT LIKE '%WHERE%'").Scan(&emailIndexes)
+	for _, index := range emailIndexes {
+		db.DB.Exec("DROP INDEX " + index)
+	}
 	db.DB.AutoMigrate(&models.User{})
+	db.DB.AutoMigrate(&models.CancellationPolicy{}, &models.RatePlan{})
+	db.DB.AutoMigrate(&models.RoomType{}, &models.GroupBlock{}, &models.GroupAllotment{})
+	// Las reservas de un grupo pueden compartir el email del contacto: el índice único ya no aplica
+	if db.DB.Migrator().HasIndex(&models.Reservation{}, "idx_reservations_email") {
+		db.DB.Migrator().DropIndex(&models.Reservation{}, "idx_reservations_email")
+	}
 	db.DB.AutoMigrate(&models.Reservation{})
-	db.DB.AutoMigrate(&models.Consultation{})
+	db.DB.AutoMigrate(&models.Room{}, &models.RoomAssignment{})
+	db.DB.AutoMigrate(&models.HousekeepingTask{})
+	db.DB.AutoMigrate(&models.WorkOrder{}, &models.RoomOutage{})
+	db.DB.AutoMigrate(&models.Consultation{}, &models.ConsultationMessage{})
 	db.DB.AutoMigrate(&models.Employee{}) 
+	db.DB.AutoMigrate(&models.Folio{}, &models.FolioEntry{})
+	db.DB.AutoMigrate(&models.Payment{})
+	db.DB.AutoMigrate(&models.Invoice{}, &models.InvoiceLine{}, &models.InvoiceSequence{})
+	db.DB.AutoMigrate(&models.AuditLog{})
+	db.DB.AutoMigrate(&models.IdempotencyKey{})
+	db.DB.AutoMigrate(&models.Notification{})
+	db.DB.AutoMigrate(&models.DomainEvent{})
+	db.DB.AutoMigrate(&models.WebhookSubscription{}, &models.WebhookDelivery{})
+	db.DB.AutoMigrate(&models.Restriction{})
+	db.DB.AutoMigrate(&models.Channel{}, &models.ChannelRoomMapping{}, &models.ChannelRateMapping{}, &models.ChannelConflict{})
 
-	// Creación del enrutador
-	r := mux.NewRouter()
-
-	// Rutas para User
-	r.HandleFunc("/users", routes.GetUsersHandler).Methods("GET")
-	r.HandleFunc("/users/{id}", routes.GetUserHandler).Methods("GET")
-	r.HandleFunc("/users", routes.PostUserHandler).Methods("POST")
-	r.HandleFunc("/users/{id}", routes.UpdateUserHandler).Methods("PUT")
-	r.HandleFunc("/users/{id}", routes.DeleteUserHandler).Methods("DELETE")
-
-	// Rutas para Reservation
-	r.HandleFunc("/reservations", routes.GetReservationsHandler).Methods("GET")
-	r.HandleFunc("/reservations/{id}", routes.GetReservationHandler).Methods("GET")
-	r.HandleFunc("/reservations", routes.CreateReservationHandler).Methods("POST")
-	r.HandleFunc("/reservations/{id}", routes.UpdateReservationHandler).Methods("PUT")
-	r.HandleFunc("/reservations/{id}", routes.DeleteReservationHandler).Methods("DELETE")
-
-	// Rutas para Consultation
-	r.HandleFunc("/consultations", routes.GetConsultationsHandler).Methods("GET")
-	r.HandleFunc("/consultations/{id}", routes.GetConsultationHandler).Methods("GET")
-	r.HandleFunc("/consultations", routes.CreateConsultationHandler).Methods("POST")
-	r.HandleFunc("/consultations/{id}", routes.UpdateConsultationHandler).Methods("PUT")
-	r.HandleFunc("/consultations/{id}", routes.DeleteConsultationHandler).Methods("DELETE")
-
-	// Rutas para Employee
-	r.HandleFunc("/employees", routes.GetEmployeesHandler).Methods("GET")
-	r.HandleFunc("/employees/{id}", routes.GetEmployeeHandler).Methods("GET")
-	r.HandleFunc("/employees", routes.PostEmployeeHandler).Methods("POST")
-	r.HandleFunc("/employees/{id}", routes.UpdateEmployeeHandler).Methods("PUT")
-	r.HandleFunc("/employees/{id}", routes.DeleteEmployeeHandler).Methods("DELETE")
-
-	// Configuración del servidor HTTP con CORS habilitado
-	http.ListenAndServe(":10000", middleware.CORS(r))
+	// Configuración del proveedor de pagos
+	payments.SetupProvider()
+
+	// Configuración del envío de notificaciones
+	notifications.SetupSender()
+
+	// Suscriptores de los eventos de dominio
+	notifications.Subscribe()
+	webhooks.Subscribe()
+
+	// Tareas en segundo plano
+	jobs.Every(24*time.Hour, "purge", func() error { return jobs.PurgeDeleted(jobs.PurgeRetention()) })
+	jobs.Every(time.Hour, "idempotency-purge", jobs.PurgeIdempotencyKeys)
+	jobs.Every(time.Hour, "group-release", jobs.ReleaseGroupBlocks)
+	jobs.Every(time.Hour, "housekeeping-tasks", jobs.GenerateHousekeepingTasks)
+	jobs.Every(10*time.Second, "events", jobs.DispatchEvents)
+	jobs.Every(time.Minute, "notifications", jobs.DeliverNotifications)
+	jobs.Every(15*time.Second, "webhooks", jobs.DeliverWebhooks)
+	jobs.Every(15*time.Minute, "channel-ari-push", jobs.PushChannelARI)
+	jobs.Every(time.Minute, "channel-reservation-pull", jobs.PullChannelReservations)
+	jobs.Every(6*time.Hour, "pre-arrival-reminders", jobs.SendPreArrivalReminders)
+
+	// Servidor gRPC para los servicios internos, con los mismos tokens que la API REST
+	go func() {
+		listener, err := net.Listen("tcp", ":10001")
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer := routes.NewGRPCServer(middleware.GRPCAuth(middleware.ParseTokens(os.Getenv("API_TOKENS")))...)
+		log.Fatal(grpcServer.Serve(listener))
+	}()
+
+	// Creación del enrutador con todas las rutas de la API
+	r := routes.NewRouter()
+
+	// Configuración del servidor HTTP con CORS, autenticación por token, validación contra la especificación
+	// OpenAPI e idempotencia de los POST
+	http.ListenAndServe(":10000", middleware.CORS(middleware.RequestID(middleware.Auth(middleware.Validation(routes.OpenAPI, middleware.Idempotency(r))))))
 }
@@ -0,0 +1,68 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// ErrInvalidPatch indica que el cuerpo de la solicitud no es un documento JSON válido
+var ErrInvalidPatch = errors.New("invalid merge patch document")
+
+// merge aplica un parche sobre un valor según RFC 7396: los objetos se combinan
+// recursivamente, null elimina la clave y cualquier otro valor reemplaza al original
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// Apply aplica un JSON Merge Patch (RFC 7396) sobre un documento JSON
+func Apply(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, ErrInvalidPatch
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+// ApplyTo aplica un JSON Merge Patch sobre una estructura. Los campos que el parche pone
+// en null vuelven a su valor cero y los campos desconocidos se rechazan
+func ApplyTo(target interface{}, patch []byte) error {
+	document, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	patched, err := Apply(document, patch)
+	if err != nil {
+		return err
+	}
+
+	// Decodificar sobre un valor nuevo para que las claves eliminadas queden en cero
+	value := reflect.ValueOf(target).Elem()
+	fresh := reflect.New(value.Type())
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(fresh.Interface()); err != nil {
+		return err
+	}
+	value.Set(fresh.Elem())
+	return nil
+}
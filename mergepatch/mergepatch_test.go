@@ -0,0 +1,56 @@
+package mergepatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRFC7396Examples(t *testing.T) {
+	cases := []struct {
+		document, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		patched, err := Apply([]byte(c.document), []byte(c.patch))
+		assert.NoError(t, err)
+		assert.JSONEq(t, c.expected, string(patched), c.patch)
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	type person struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Address   struct {
+			City string `json:"city"`
+			Zip  string `json:"zip"`
+		} `json:"address"`
+	}
+	p := person{FirstName: "Gabriela", LastName: "Gomez"}
+	p.Address.City = "Rosario"
+	p.Address.Zip = "2000"
+
+	// Los campos omitidos se conservan y null vuelve el campo a su valor cero
+	assert.NoError(t, ApplyTo(&p, []byte(`{"last_name":"Gómez","address":{"zip":null}}`)))
+	assert.Equal(t, "Gabriela", p.FirstName)
+	assert.Equal(t, "Gómez", p.LastName)
+	assert.Equal(t, "Rosario", p.Address.City)
+	assert.Equal(t, "", p.Address.Zip)
+
+	assert.Error(t, ApplyTo(&p, []byte(`{"salary":10}`)))
+	assert.ErrorIs(t, ApplyTo(&p, []byte(`not json`)), ErrInvalidPatch)
+}
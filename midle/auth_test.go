@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokens(t *testing.T) {
+	tokens := ParseTokens("t1:admin:alice, t2:staff:bob,broken,t3:unknown:carol")
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, Principal{Actor: "alice", Role: RoleAdmin}, tokens["t1"])
+	assert.Equal(t, Principal{Actor: "bob", Role: RoleStaff}, tokens["t2"])
+}
+
+func TestAuthAndRequireRole(t *testing.T) {
+	tokens := ParseTokens("admin-token:admin:alice,staff-token:staff:bob")
+	handler := AuthWithTokens(tokens, RequireRole(RoleManager, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(PrincipalFromRequest(r).Actor))
+	}))
+
+	cases := []struct {
+		header string
+		code   int
+	}{
+		{"", http.StatusForbidden},                   // Anónimo
+		{"Bearer staff-token", http.StatusForbidden}, // Rol insuficiente
+		{"Bearer admin-token", http.StatusOK},        // El administrador incluye los permisos de gerente
+		{"Bearer wrong", http.StatusUnauthorized},    // Token desconocido
+		{"admin-token", http.StatusUnauthorized},     // Falta el esquema Bearer
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, c.code, rr.Code, c.header)
+		if c.code == http.StatusOK {
+			assert.Equal(t, "alice", rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticateGRPC(t *testing.T) {
+	tokens := ParseTokens("admin-token:admin:alice")
+	call := func(pairs ...string) (context.Context, error) {
+		return authenticateGRPC(metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...)), tokens)
+	}
+
+	// Anónimo, con un identificador de solicitud generado
+	ctx, err := call()
+	assert.NoError(t, err)
+	r, _ := http.NewRequestWithContext(ctx, "POST", "/", nil)
+	assert.Equal(t, Principal{}, PrincipalFromRequest(r))
+	assert.Len(t, RequestIDFromRequest(r), 32)
+
+	ctx, err = call("authorization", "Bearer admin-token", "x-request-id", "abc")
+	assert.NoError(t, err)
+	r, _ = http.NewRequestWithContext(ctx, "POST", "/", nil)
+	assert.Equal(t, "alice", PrincipalFromRequest(r).Actor)
+	assert.Equal(t, "abc", RequestIDFromRequest(r))
+
+	for _, header := range []string{"Bearer wrong", "admin-token"} {
+		_, err = call("authorization", header)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+}
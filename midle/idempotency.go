@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyHeader es la cabecera con la que el cliente identifica una solicitud reintentable
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marca las respuestas que se devuelven desde el almacén en lugar de ejecutar la solicitud
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyStore guarda las claves de idempotencia y sus respuestas
+type IdempotencyStore interface {
+	// Reserve registra la clave. Si ya existe una vigente con el mismo ámbito la devuelve sin modificarla
+	Reserve(record *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	// Complete guarda la respuesta de la solicitud original
+	Complete(record *models.IdempotencyKey) error
+	// Release libera la clave para que la solicitud pueda reintentarse
+	Release(record *models.IdempotencyKey) error
+}
+
+// IdempotencyTTL devuelve durante cuánto tiempo se conserva una clave de idempotencia.
+// Se configura en horas con IDEMPOTENCY_TTL_HOURS (24 por defecto)
+func IdempotencyTTL() time.Duration {
+	hours := 24
+	if value, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS")); err == nil && value > 0 {
+		hours = value
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Idempotency hace que las solicitudes POST con la cabecera Idempotency-Key se ejecuten una sola vez:
+// los reintentos reciben la respuesta original, reutilizar la clave con otra solicitud devuelve 422
+// y un reintento mientras la original sigue en curso devuelve 409
+func Idempotency(next http.Handler) http.Handler {
+	return IdempotencyWithStore(gormIdempotencyStore{}, IdempotencyTTL(), next)
+}
+
+// IdempotencyWithStore aplica la idempotencia usando el almacén y la vigencia indicados
+func IdempotencyWithStore(store IdempotencyStore, ttl time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > 255 {
+			http.Error(w, "Idempotency key too long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// La clave pertenece a quien la envía: dos clientes distintos pueden usar el mismo valor
+		record := &models.IdempotencyKey{
+			Scope:       PrincipalFromRequest(r).Actor,
+			Key:         key,
+			RequestHash: requestHash(r, body),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+		existing, err := store.Reserve(record)
+		if err != nil {
+			http.Error(w, "Failed to store idempotency key", http.StatusInternalServerError)
+			return
+		}
+		if existing != nil {
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				http.Error(w, "Idempotency key already used with a different request", http.StatusUnprocessableEntity)
+			case existing.StatusCode == 0:
+				http.Error(w, "A request with this idempotency key is still in progress", http.StatusConflict)
+			default:
+				if existing.ContentType != "" {
+					w.Header().Set("Content-Type", existing.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(existing.StatusCode)
+				w.Write(existing.ResponseBody)
+			}
+			return
+		}
+
+		// Si el handler entra en pánico la clave se libera, como ante un error del servidor, para que
+		// el reintento no reciba 409 hasta que venza
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err := store.Release(record); err != nil {
+					log.Printf("failed to release idempotency key %q: %v", key, err)
+				}
+				panic(recovered)
+			}
+		}()
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		// Los errores del servidor no se guardan: el cliente puede reintentar con la misma clave
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			if err := store.Release(record); err != nil {
+				log.Printf("failed to release idempotency key %q: %v", key, err)
+			}
+			return
+		}
+		record.StatusCode = recorder.status
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+		if err := store.Complete(record); err != nil {
+			log.Printf("failed to store idempotent response for key %q: %v", key, err)
+		}
+	})
+}
+
+// requestHash resume el método, la ruta y el cuerpo para detectar reutilizaciones de la clave
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copia la respuesta mientras se envía al cliente
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+// gormIdempotencyStore guarda las claves en la base de datos
+type gormIdempotencyStore struct{}
+
+func (gormIdempotencyStore) Reserve(record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	var existing *models.IdempotencyKey
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Una clave vencida se puede volver a usar
+		if err := tx.Where("scope = ? AND key = ? AND expires_at < ?", record.Scope, record.Key, time.Now()).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		existing = &models.IdempotencyKey{}
+		return tx.Where("scope = ? AND key = ?", record.Scope, record.Key).First(existing).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("idempotency key vanished while reserving it")
+	}
+	return existing, err
+}
+
+func (gormIdempotencyStore) Complete(record *models.IdempotencyKey) error {
+	return db.DB.Model(record).Select("StatusCode", "ContentType", "ResponseBody").Updates(record).Error
+}
+
+func (gormIdempotencyStore) Release(record *models.IdempotencyKey) error {
+	return db.DB.Delete(record).Error
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore guarda las claves en memoria para los tests
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyKey
+}
+
+func (s *memoryIdempotencyStore) Reserve(record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := record.Scope + "|" + record.Key
+	if existing, ok := s.records[id]; ok && existing.ExpiresAt.After(time.Now()) {
+		copy := *existing
+		return &copy, nil
+	}
+	s.records[id] = record
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(record *models.IdempotencyKey) error {
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(record *models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, record.Scope+"|"+record.Key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyKey{}}
+	calls := 0
+	handler := IdempotencyWithStore(store, time.Hour, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if strings.Contains(r.URL.Path, "fail") {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// La primera solicitud se ejecuta y el reintento devuelve la respuesta original
+	first := send("/reservations", "k1", `{"adults":2}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	retry := send("/reservations", "k1", `{"adults":2}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, `{"id":1}`, retry.Body.String())
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// Reutilizar la clave con otro cuerpo o en otra ruta es un conflicto
+	assert.Equal(t, http.StatusUnprocessableEntity, send("/reservations", "k1", `{"adults":3}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, send("/users", "k1", `{"adults":2}`).Code)
+	assert.Equal(t, 1, calls)
+
+	// Sin clave no hay deduplicación
+	send("/reservations", "", `{"adults":2}`)
+	send("/reservations", "", `{"adults":2}`)
+	assert.Equal(t, 3, calls)
+
+	// Los errores del servidor liberan la clave para poder reintentar
+	assert.Equal(t, http.StatusInternalServerError, send("/fail", "k2", `{}`).Code)
+	assert.Equal(t, http.StatusInternalServerError, send("/fail", "k2", `{}`).Code)
+	assert.Equal(t, 5, calls)
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyKey{}}
+	store.records["|k1"] = &models.IdempotencyKey{Key: "k1", RequestHash: "", ExpiresAt: time.Now().Add(time.Hour)}
+	handler := IdempotencyWithStore(store, time.Hour, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not run")
+	}))
+
+	req := httptest.NewRequest("POST", "/reservations", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	store.records["|k1"].RequestHash = requestHash(req, []byte(`{}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestIdempotencyPanicReleasesKey(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]*models.IdempotencyKey{}}
+	handler := IdempotencyWithStore(store, time.Hour, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest("POST", "/reservations", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "k1")
+	// El pánico llega al servidor, pero la clave queda libre para el reintento
+	assert.PanicsWithValue(t, "boom", func() { handler.ServeHTTP(httptest.NewRecorder(), req) })
+	assert.Empty(t, store.records)
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/openapi"
+)
+
+// maxValidatedBody es el tamaño máximo de un cuerpo JSON que se valida contra la especificación
+const maxValidatedBody = 1 << 20
+
+// Validation rechaza con 400 las solicitudes que no cumplen la especificación OpenAPI: parámetros de
+// la ruta no numéricos, parámetros de la consulta con tipo incorrecto u obligatorios ausentes y cuerpos
+// JSON que no respetan el esquema. Con OPENAPI_VALIDATE_RESPONSES=true también valida las respuestas
+func Validation(doc *openapi.Document, next http.Handler) http.Handler {
+	return ValidationWithResponses(doc, os.Getenv("OPENAPI_VALIDATE_RESPONSES") == "true", next)
+}
+
+// ValidationWithResponses es igual que Validation. Si validateResponses es verdadero, una respuesta
+// exitosa que no cumple la especificación se reemplaza por un 500 con el detalle; está pensado para
+// las pruebas, porque obliga a guardar cada respuesta en memoria
+func ValidationWithResponses(doc *openapi.Document, validateResponses bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation, pathValues, ok := doc.Match(r.Method, r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := doc.ValidateParameters(operation, pathValues, r.URL.Query()); err != nil {
+			http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if schema, mergePatch, ok := jsonRequestSchema(operation); ok && r.Body != nil {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValidatedBody))
+			if err != nil {
+				http.Error(w, "Invalid request payload", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if len(body) > 0 || operation.RequestBody.Required {
+				if err := doc.ValidateRequestBody(schema, body, mergePatch); err != nil {
+					http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		schema, ok := jsonResponseSchema(operation)
+		if !validateResponses || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		buffered := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+		if buffered.status == http.StatusOK {
+			if err := doc.ValidateResponseBody(schema, buffered.body.Bytes()); err != nil {
+				log.Printf("response to %s %s does not match the API contract: %v", r.Method, r.URL.Path, err)
+				http.Error(w, "Response does not match the API contract: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		for name, values := range buffered.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	})
+}
+
+// jsonRequestSchema devuelve el esquema del cuerpo si la operación recibe JSON
+func jsonRequestSchema(operation *openapi.Operation) (*openapi.Schema, bool, bool) {
+	if operation.RequestBody == nil {
+		return nil, false, false
+	}
+	for contentType, media := range operation.RequestBody.Content {
+		if strings.HasSuffix(contentType, "json") {
+			return media.Schema, contentType == "application/merge-patch+json", true
+		}
+	}
+	return nil, false, false
+}
+
+// jsonResponseSchema devuelve el esquema de la respuesta exitosa si la operación devuelve JSON
+func jsonResponseSchema(operation *openapi.Operation) (*openapi.Schema, bool) {
+	media, ok := operation.Responses["200"].Content["application/json"]
+	return media.Schema, ok
+}
+
+// bufferedResponse guarda la respuesta en memoria para validarla antes de enviarla
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
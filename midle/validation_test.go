@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+var itemsSpec = openapi.Build(openapi.Info{Title: "Test", Version: "1"}, []openapi.Route{
+	{Method: "GET", Path: "/items/{id}", Response: item{}},
+	{Method: "POST", Path: "/items", Query: []openapi.Parameter{openapi.Query("dry_run", openapi.Boolean, "")}, Request: item{}, Response: item{}},
+})
+
+func TestValidationRequests(t *testing.T) {
+	handler := ValidationWithResponses(itemsSpec, false, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// El handler recibe el cuerpo completo aunque ya se haya validado
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+
+	cases := []struct {
+		method, target, body string
+		code                 int
+	}{
+		{"GET", "/items/12", "", http.StatusOK},
+		{"GET", "/items/abc", "", http.StatusBadRequest},                        // El id debe ser numérico
+		{"POST", "/items?dry_run=maybe", `{"name":"a"}`, http.StatusBadRequest}, // Parámetro de consulta inválido
+		{"POST", "/items", `{"count":"3"}`, http.StatusBadRequest},              // Tipo incorrecto en el cuerpo
+		{"POST", "/items", ``, http.StatusBadRequest},                           // Cuerpo obligatorio
+		{"POST", "/items?dry_run=true", `{"name":"a"}`, http.StatusOK},
+		{"GET", "/other/abc", "", http.StatusOK}, // Las rutas no documentadas pasan sin validar
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, c.code, rr.Code, c.method+" "+c.target+" "+c.body)
+		if c.code == http.StatusOK {
+			assert.Equal(t, c.body, rr.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"count":"3"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "Invalid request payload: body.count: must be an integer\n", rr.Body.String())
+}
+
+func TestValidationResponses(t *testing.T) {
+	response := `{"id":1,"name":"a","count":2}`
+	handler := ValidationWithResponses(itemsSpec, true, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"1"`)
+		w.Write([]byte(response))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/items/1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+	assert.Equal(t, response, rr.Body.String())
+
+	// Una respuesta a la que le falta un campo no llega al cliente
+	response = `{"id":1,"name":"a"}`
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/items/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "body.count: is required")
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogAppendOnly se devuelve al intentar modificar o borrar una entrada de auditoría
+var ErrAuditLogAppendOnly = errors.New("audit log is append-only")
+
+// AuditLog registra una modificación realizada a través de la API. Las entradas no se modifican ni se borran
+type AuditLog struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+	Actor        string    `gorm:"not null;index" json:"actor"`
+	Action       string    `gorm:"not null" json:"action"`
+	ResourceType string    `gorm:"not null;index:idx_audit_resource" json:"resource_type"`
+	ResourceID   uint      `gorm:"index:idx_audit_resource" json:"resource_id"`
+	Before       string    `gorm:"type:text" json:"before,omitempty"`
+	After        string    `gorm:"type:text" json:"after,omitempty"`
+	Diff         string    `gorm:"type:text" json:"diff,omitempty"`
+	RequestID    string    `gorm:"index" json:"request_id"`
+}
+
+// BeforeUpdate impide modificar entradas de auditoría
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
+
+// BeforeDelete impide borrar entradas de auditoría
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogAppendOnly
+}
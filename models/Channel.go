@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de conflicto al importar reservas de un canal
+const (
+	ConflictUnmappedRoom       = "unmapped_room"       // El código de habitación del canal no está mapeado
+	ConflictUnmappedRate       = "unmapped_rate"       // El código de tarifa del canal no está mapeado
+	ConflictOverbooking        = "overbooking"         // La reserva se importó pero no había disponibilidad
+	ConflictUnknownReservation = "unknown_reservation" // Cancelación de una reserva que no se importó
+	ConflictInvalid            = "invalid"             // Datos de la reserva inválidos
+	ConflictNotModifiable      = "not_modifiable"      // La reserva ya está cancelada o eliminada, o el huésped está alojado
+	ConflictDuplicate          = "duplicate"           // Otra reserva ya usa la referencia del canal
+)
+
+// Estados de un conflicto
+const (
+	ConflictOpen     = "open"
+	ConflictResolved = "resolved"
+)
+
+// Channel es un canal de venta conectado a través de un channel manager
+type Channel struct {
+	gorm.Model
+	Code         string               `gorm:"uniqueIndex;not null" json:"code"`
+	Name         string               `json:"name"`
+	Endpoint     string               `gorm:"not null" json:"endpoint"` // URL base de la API del channel manager
+	APIKey       string               `json:"api_key,omitempty"`        // Solo se devuelve al crear el canal
+	HotelCode    string               `json:"hotel_code"`               // Identificador del hotel en el channel manager
+	Active       bool                 `gorm:"not null;default:true" json:"active"`
+	PullCursor   string               `json:"pull_cursor,omitempty"` // Posición de la última importación de reservas
+	LastPushAt   *time.Time           `json:"last_push_at,omitempty"`
+	LastPullAt   *time.Time           `json:"last_pull_at,omitempty"`
+	LastError    string               `gorm:"type:text" json:"last_error,omitempty"`
+	RoomMappings []ChannelRoomMapping `json:"room_mappings,omitempty"`
+	RateMappings []ChannelRateMapping `json:"rate_mappings,omitempty"`
+}
+
+// ChannelRoomMapping relaciona un código de habitación del canal con un tipo de habitación
+type ChannelRoomMapping struct {
+	ID              uint   `gorm:"primarykey" json:"id"`
+	ChannelID       uint   `gorm:"not null;uniqueIndex:idx_channel_room_code" json:"channel_id"`
+	ChannelRoomCode string `gorm:"not null;uniqueIndex:idx_channel_room_code" json:"channel_room_code"`
+	RoomType        string `gorm:"not null" json:"room_type"`
+}
+
+// ChannelRateMapping relaciona un código de tarifa del canal con un plan de tarifas
+type ChannelRateMapping struct {
+	ID              uint   `gorm:"primarykey" json:"id"`
+	ChannelID       uint   `gorm:"not null;uniqueIndex:idx_channel_rate_code" json:"channel_id"`
+	ChannelRateCode string `gorm:"not null;uniqueIndex:idx_channel_rate_code" json:"channel_rate_code"`
+	RatePlanID      uint   `gorm:"not null" json:"rate_plan_id"`
+}
+
+// ChannelConflict es una reserva del canal que no se pudo importar tal cual y requiere revisión
+type ChannelConflict struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+	ChannelID     uint       `gorm:"not null;index" json:"channel_id"`
+	Reference     string     `gorm:"index" json:"reference"` // Número de reserva en el canal
+	Kind          string     `gorm:"not null" json:"kind"`
+	Message       string     `json:"message"`
+	Payload       string     `gorm:"type:text" json:"payload"` // Reserva recibida del canal
+	ReservationID *uint      `json:"reservation_id,omitempty"`
+	Status        string     `gorm:"not null;default:open;index" json:"status"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
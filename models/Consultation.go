@@ -1,13 +1,68 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// Estados de una consulta
+const (
+	ConsultationOpen     = "open"     // Espera respuesta del hotel
+	ConsultationPending  = "pending"  // En espera de una gestión interna: no corre el plazo de respuesta
+	ConsultationAnswered = "answered" // El hotel respondió y se espera al huésped
+	ConsultationClosed   = "closed"
+)
+
+// Autores de los mensajes de una consulta
+const (
+	MessageFromGuest = "guest"
+	MessageFromStaff = "staff"
+)
+
 type Consultation struct {
 	gorm.Model
-	Phone         string `json:"phone"`                       
-	Consultation  string `gorm:"type:text;size:3000" json:"consultation"` 
-	MoreInfo      bool   `json:"more_info"`                   
-	UserID        uint   `json:"user_id"`
+	Phone              string                `json:"phone"`
+	Consultation       string                `gorm:"type:text;size:3000" json:"consultation"`
+	MoreInfo           bool                  `json:"more_info"`
+	UserID             uint                  `json:"user_id"`
+	Status             string                `gorm:"not null;default:open;index" json:"status"`
+	AssignedEmployeeID *uint                 `gorm:"index" json:"assigned_employee_id"`
+	DueAt              *time.Time            `gorm:"index" json:"due_at,omitempty"` // Plazo para responder al huésped
+	AnsweredAt         *time.Time            `json:"answered_at,omitempty"`
+	ClosedAt           *time.Time            `json:"closed_at,omitempty"`
+	ReservationID      *uint                 `gorm:"index" json:"reservation_id,omitempty"` // Reserva creada a partir de la consulta
+	ConvertedAt        *time.Time            `json:"converted_at,omitempty"`
+	ConvertedByID      *uint                 `gorm:"index" json:"converted_by_id,omitempty"` // Empleado que convirtió la consulta
+	Messages           []ConsultationMessage `json:"messages,omitempty"`
+}
+
+// ConsultationMessage es un mensaje del hilo de una consulta: una respuesta del personal o un seguimiento del huésped
+type ConsultationMessage struct {
+	gorm.Model
+	ConsultationID uint   `gorm:"not null;index" json:"consultation_id"`
+	Author         string `gorm:"not null" json:"author"`
+	EmployeeID     *uint  `json:"employee_id,omitempty"`
+	Body           string `gorm:"type:text;not null" json:"body"`
+}
+
+// ApplyMessage actualiza el estado de la consulta al recibir un mensaje: la respuesta del personal la
+// deja respondida y un mensaje del huésped la reabre con un nuevo plazo de respuesta
+func (c *Consultation) ApplyMessage(author string, now time.Time, sla time.Duration) {
+	switch author {
+	case MessageFromStaff:
+		c.Status = ConsultationAnswered
+		c.AnsweredAt = &now
+		c.DueAt = nil
+	case MessageFromGuest:
+		due := now.Add(sla)
+		c.Status = ConsultationOpen
+		c.DueAt = &due
+		c.ClosedAt = nil
+	}
+}
+
+// Overdue indica si la consulta espera respuesta del hotel y venció su plazo
+func (c Consultation) Overdue(now time.Time) bool {
+	return c.Status == ConsultationOpen && c.DueAt != nil && c.DueAt.Before(now)
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Estados de despacho de un evento de dominio
+const (
+	EventPending    = "pending"
+	EventDispatched = "dispatched"
+	EventFailed     = "failed"
+)
+
+// DomainEvent es un evento de dominio en la bandeja de salida transaccional. Se guarda en la misma
+// transacción que el cambio de estado y un proceso en segundo plano lo entrega a los suscriptores
+// al menos una vez
+type DomainEvent struct {
+	ID            uint       `gorm:"primarykey;index:idx_domain_events_position,priority:2" json:"id"`
+	TxID          uint64     `gorm:"not null;default:txid_current();index:idx_domain_events_position,priority:1" json:"-"` // Transacción que guardó el evento; ordena la lectura de la bandeja de salida
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+	Type          string     `gorm:"not null;index" json:"type"`
+	AggregateType string     `gorm:"not null;index:idx_domain_events_aggregate" json:"aggregate_type"`
+	AggregateID   uint       `gorm:"index:idx_domain_events_aggregate" json:"aggregate_id"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"` // JSON con el estado del agregado
+	Status        string     `gorm:"not null;default:pending;index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	Handled       string     `gorm:"type:text" json:"handled,omitempty"` // Suscriptores que ya procesaron el evento, separados por comas
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	DispatchedAt  *time.Time `json:"dispatched_at,omitempty"`
+}
+
+// HandledBy indica si el suscriptor ya procesó el evento
+func (e *DomainEvent) HandledBy(subscriber string) bool {
+	for _, name := range strings.Split(e.Handled, ",") {
+		if name == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkHandled registra que el suscriptor procesó el evento, para no repetirlo en los reintentos
+func (e *DomainEvent) MarkHandled(subscriber string) {
+	if e.HandledBy(subscriber) {
+		return
+	}
+	if e.Handled != "" {
+		e.Handled += ","
+	}
+	e.Handled += subscriber
+}
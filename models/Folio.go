@@ -0,0 +1,48 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Tipos de movimientos que se registran en el folio
+const (
+	FolioEntryCharge  = "charge"
+	FolioEntryPayment = "payment"
+	FolioEntryRefund  = "refund"
+)
+
+// Folio agrupa los cargos y pagos asociados a la estancia de un huésped, o los de un grupo
+// cuando es el folio maestro de un bloqueo
+type Folio struct {
+	gorm.Model
+	ReservationID *uint        `gorm:"uniqueIndex" json:"reservation_id"`
+	GroupBlockID  *uint        `gorm:"uniqueIndex" json:"group_block_id,omitempty"`
+	Entries       []FolioEntry `json:"entries"`
+}
+
+// FolioEntry es un movimiento (cargo, pago o reembolso) dentro de un folio
+type FolioEntry struct {
+	gorm.Model
+	FolioID     uint    `gorm:"not null;index" json:"folio_id"`
+	Type        string  `gorm:"not null" json:"type"`
+	Description string  `json:"description"`
+	Amount      float64 `gorm:"not null" json:"amount"`
+	TaxRate     float64 `json:"tax_rate"` // Alícuota incluida en el importe de los cargos
+	InvoiceID   *uint   `json:"invoice_id,omitempty"`
+	PaymentID   *uint   `json:"payment_id,omitempty"`
+	Reference   string  `gorm:"index" json:"reference,omitempty"` // Operación del proveedor que originó el movimiento
+}
+
+// Balance devuelve el saldo pendiente del folio: cargos menos pagos más reembolsos
+func (f Folio) Balance() float64 {
+	var balance float64
+	for _, entry := range f.Entries {
+		switch entry.Type {
+		case FolioEntryCharge, FolioEntryRefund:
+			balance += entry.Amount
+		case FolioEntryPayment:
+			balance -= entry.Amount
+		}
+	}
+	return balance
+}
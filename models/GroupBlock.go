@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de un bloqueo de grupo
+const (
+	GroupBlockOpen     = "open"
+	GroupBlockReleased = "released"
+)
+
+// GroupBlock reserva un cupo de habitaciones para un grupo (bodas, congresos). Los huéspedes toman
+// habitaciones del cupo hasta la fecha de liberación; después el cupo no tomado vuelve al inventario
+type GroupBlock struct {
+	gorm.Model
+	Code         string           `gorm:"uniqueIndex;not null" json:"code"`
+	Name         string           `gorm:"not null" json:"name"`
+	ContactEmail string           `json:"contact_email"`
+	Checkin      time.Time        `gorm:"not null" json:"check_in"`
+	Checkout     time.Time        `gorm:"not null" json:"check_out"`
+	ReleaseDate  time.Time        `gorm:"not null" json:"release_date"`
+	RatePlanID   *uint            `json:"rate_plan_id"`
+	Status       string           `gorm:"not null;default:open" json:"status"`
+	ReleasedAt   *time.Time       `json:"released_at,omitempty"`
+	Allotments   []GroupAllotment `json:"allotments"`
+}
+
+// GroupAllotment es la cantidad de habitaciones de un tipo reservadas para el grupo
+type GroupAllotment struct {
+	ID           uint   `gorm:"primarykey" json:"id"`
+	GroupBlockID uint   `gorm:"not null;uniqueIndex:idx_allotment_block_type" json:"group_block_id"`
+	RoomType     string `gorm:"not null;uniqueIndex:idx_allotment_block_type" json:"room_type"`
+	Rooms        int    `gorm:"not null" json:"rooms"`
+	Released     int    `json:"released"`           // Habitaciones devueltas al inventario al liberar el bloqueo
+	PickedUp     int    `gorm:"-" json:"picked_up"` // Habitaciones ya tomadas por huéspedes del grupo
+}
+
+// Held devuelve las habitaciones que el bloqueo retiene sin que ningún huésped las haya tomado
+func (a GroupAllotment) Held() int {
+	if held := a.Rooms - a.PickedUp - a.Released; held > 0 {
+		return held
+	}
+	return 0
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de tarea de limpieza
+const (
+	TaskDeparture = "departure" // Limpieza completa tras la salida del huésped
+	TaskStayover  = "stayover"  // Repaso diario de una habitación ocupada
+)
+
+// Estados de una tarea de limpieza
+const (
+	TaskPending    = "pending"
+	TaskInProgress = "in_progress"
+	TaskDone       = "done"
+)
+
+// HousekeepingTask es la limpieza de una habitación en un día, asignada a un empleado de limpieza
+type HousekeepingTask struct {
+	gorm.Model
+	RoomID      uint       `gorm:"not null;uniqueIndex:idx_task_room_date_type" json:"room_id"`
+	Room        *Room      `json:"room,omitempty"`
+	Date        time.Time  `gorm:"not null;uniqueIndex:idx_task_room_date_type" json:"date"`
+	Type        string     `gorm:"not null;uniqueIndex:idx_task_room_date_type" json:"type"`
+	Status      string     `gorm:"not null;default:pending" json:"status"`
+	EmployeeID  *uint      `gorm:"index" json:"employee_id"`
+	Notes       string     `json:"notes"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
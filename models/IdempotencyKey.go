@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyKey guarda la respuesta de una solicitud POST enviada con la cabecera Idempotency-Key,
+// para devolverla sin volver a ejecutarla si el cliente reintenta. Una respuesta con StatusCode 0
+// indica que la solicitud original todavía se está procesando
+type IdempotencyKey struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Scope        string    `gorm:"not null;uniqueIndex:idx_idempotency_scope_key" json:"scope"`
+	Key          string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_scope_key" json:"key"`
+	RequestHash  string    `gorm:"not null" json:"request_hash"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type"`
+	ResponseBody []byte    `json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de comprobante
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+)
+
+// Invoice es una factura o nota de crédito emitida a partir del folio de una reserva.
+// Los comprobantes emitidos no se modifican: los datos del huésped se guardan tal como estaban al emitirlos
+type Invoice struct {
+	gorm.Model
+	Number        string        `gorm:"not null;uniqueIndex" json:"number"`
+	Kind          string        `gorm:"not null" json:"kind"`
+	ReservationID uint          `gorm:"not null;index" json:"reservation_id"`
+	FolioID       uint          `gorm:"not null" json:"folio_id"`
+	PaymentID     *uint         `json:"payment_id,omitempty"`
+	IssuedAt      time.Time     `gorm:"not null" json:"issued_at"`
+	GuestName     string        `json:"guest_name"`
+	GuestEmail    string        `json:"guest_email"`
+	Currency      string        `json:"currency"`
+	Net           float64       `json:"net"`
+	Tax           float64       `json:"tax"`
+	Total         float64       `json:"total"`
+	Lines         []InvoiceLine `json:"lines"`
+}
+
+// InvoiceLine es una línea de un comprobante con su desglose de impuestos
+type InvoiceLine struct {
+	gorm.Model
+	InvoiceID    uint    `gorm:"not null;index" json:"invoice_id"`
+	FolioEntryID *uint   `json:"folio_entry_id,omitempty"`
+	Description  string  `json:"description"`
+	TaxRate      float64 `json:"tax_rate"`
+	Net          float64 `json:"net"`
+	Tax          float64 `json:"tax"`
+	Total        float64 `json:"total"`
+}
+
+// InvoiceSequence guarda el último número emitido de cada serie de comprobantes
+type InvoiceSequence struct {
+	Series     string `gorm:"primaryKey"`
+	LastNumber uint   `gorm:"not null"`
+}
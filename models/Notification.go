@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de entrega de una notificación
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// Notification es un email en la bandeja de salida. Se guarda en la misma transacción que el cambio
+// que la origina y un proceso en segundo plano la entrega con reintentos
+type Notification struct {
+	gorm.Model
+	Kind          string     `gorm:"not null;index" json:"kind"`
+	Locale        string     `gorm:"not null" json:"locale"`
+	Recipient     string     `gorm:"not null" json:"recipient"`
+	Subject       string     `gorm:"not null" json:"subject"`
+	Body          string     `gorm:"type:text;not null" json:"body"`
+	Reference     *string    `gorm:"uniqueIndex" json:"reference,omitempty"` // Evita duplicar avisos programados
+	Status        string     `gorm:"not null;default:pending;index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Estados posibles de un pago
+const (
+	PaymentPending    = "pending"
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentRefunded   = "refunded"
+	PaymentVoided     = "voided"
+	PaymentFailed     = "failed"
+)
+
+// paymentTransitions son los estados a los que puede pasar un pago desde cada estado: un pago solo
+// avanza, y anulado, reembolsado o fallido son finales
+var paymentTransitions = map[string][]string{
+	PaymentPending:    {PaymentAuthorized, PaymentCaptured, PaymentVoided, PaymentFailed},
+	PaymentAuthorized: {PaymentCaptured, PaymentVoided, PaymentFailed},
+	PaymentCaptured:   {PaymentRefunded},
+}
+
+// CanMoveTo indica si el pago puede pasar de su estado actual al indicado
+func (p Payment) CanMoveTo(status string) bool {
+	for _, next := range paymentTransitions[p.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipos de pago: depósito al reservar o liquidación del folio
+const (
+	PaymentDeposit    = "deposit"
+	PaymentSettlement = "settlement"
+)
+
+// Payment representa una transacción con el proveedor de pagos vinculada a una reserva
+type Payment struct {
+	gorm.Model
+	ReservationID  uint    `gorm:"not null;index" json:"reservation_id"`
+	FolioID        uint    `gorm:"not null;index" json:"folio_id"`
+	Kind           string  `gorm:"not null" json:"kind"`
+	Amount         float64 `gorm:"not null" json:"amount"`
+	Currency       string  `gorm:"not null" json:"currency"`
+	Status         string  `gorm:"not null" json:"status"`
+	CapturedAmount float64 `json:"captured_amount"`
+	RefundedAmount float64 `json:"refunded_amount"`
+	Provider       string  `gorm:"not null" json:"provider"`
+	ProviderRef    string  `gorm:"index" json:"provider_ref"`
+	IdempotencyKey string  `gorm:"not null;uniqueIndex" json:"idempotency_key"`
+	FailureReason  string  `json:"failure_reason,omitempty"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de penalización por cancelación tardía
+const (
+	PenaltyPercentage = "percentage"  // Porcentaje del total de la estancia
+	PenaltyFirstNight = "first_night" // Importe de la primera noche
+)
+
+// CancellationPolicy define hasta cuándo se puede cancelar sin cargo y qué se cobra después
+type CancellationPolicy struct {
+	gorm.Model
+	Name           string  `gorm:"not null" json:"name"`
+	FreeUntilDays  int     `json:"free_until_days"` // Días antes del check-in en que se puede cancelar sin cargo
+	PenaltyType    string  `gorm:"not null" json:"penalty_type"`
+	PenaltyPercent float64 `json:"penalty_percent"`
+}
+
+// RatePlan es una tarifa comercializable con su política de cancelación
+type RatePlan struct {
+	gorm.Model
+	Code                 string              `gorm:"not null;uniqueIndex" json:"code"`
+	Name                 string              `gorm:"not null" json:"name"`
+	NightlyRate          float64             `gorm:"not null" json:"nightly_rate"`
+	CancellationPolicyID *uint               `json:"cancellation_policy_id"`
+	CancellationPolicy   *CancellationPolicy `json:"cancellation_policy,omitempty"`
+}
+
+// CancellationTerms son las condiciones de cancelación de una tarifa copiadas en la reserva
+type CancellationTerms struct {
+	RatePlanID     *uint   `json:"rate_plan_id,omitempty"` // Tarifa de la que se copiaron; nula en las reservas anteriores a la copia
+	PolicyName     string  `json:"policy_name,omitempty"`
+	FreeUntilDays  int     `json:"free_until_days"`
+	PenaltyType    string  `json:"penalty_type,omitempty"` // Vacío si la tarifa no tenía política
+	PenaltyPercent float64 `json:"penalty_percent"`
+	NightlyRate    float64 `json:"nightly_rate"`
+}
+
+// CancellationTermsFor devuelve las condiciones de cancelación vigentes de la tarifa. Una tarifa que no
+// existe no tiene condiciones
+func CancellationTermsFor(tx *gorm.DB, ratePlanID uint) (CancellationTerms, error) {
+	var ratePlan RatePlan
+	if err := tx.Preload("CancellationPolicy").First(&ratePlan, ratePlanID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return CancellationTerms{}, nil
+		}
+		return CancellationTerms{}, err
+	}
+	terms := CancellationTerms{RatePlanID: &ratePlan.ID, NightlyRate: ratePlan.NightlyRate}
+	if policy := ratePlan.CancellationPolicy; policy != nil {
+		terms.PolicyName = policy.Name
+		terms.FreeUntilDays = policy.FreeUntilDays
+		terms.PenaltyType = policy.PenaltyType
+		terms.PenaltyPercent = policy.PenaltyPercent
+	}
+	return terms, nil
+}
+
+// Policy devuelve la política pactada, o nil si la tarifa no tenía política
+func (t CancellationTerms) Policy() *CancellationPolicy {
+	if t.PenaltyType == "" {
+		return nil
+	}
+	return &CancellationPolicy{Name: t.PolicyName, FreeUntilDays: t.FreeUntilDays, PenaltyType: t.PenaltyType, PenaltyPercent: t.PenaltyPercent}
+}
+
+// FreeCancellationDeadline devuelve el último momento en que se puede cancelar sin cargo
+func (p CancellationPolicy) FreeCancellationDeadline(checkin time.Time) time.Time {
+	return checkin.AddDate(0, 0, -p.FreeUntilDays)
+}
+
+// Penalty calcula el cargo por cancelar en el momento indicado una estancia de las noches
+// y habitaciones dadas a la tarifa por noche indicada
+func (p CancellationPolicy) Penalty(checkin time.Time, nights, rooms int, nightlyRate float64, now time.Time) float64 {
+	if !now.After(p.FreeCancellationDeadline(checkin)) {
+		return 0
+	}
+	if rooms < 1 {
+		rooms = 1
+	}
+
+	var penalty float64
+	switch p.PenaltyType {
+	case PenaltyFirstNight:
+		if nights > 0 {
+			penalty = nightlyRate * float64(rooms)
+		}
+	case PenaltyPercentage:
+		penalty = nightlyRate * float64(nights*rooms) * p.PenaltyPercent / 100
+	}
+	return math.Round(penalty*100) / 100
+}
@@ -2,17 +2,71 @@ package models
 
 import (
 	"time"
+
 	"gorm.io/gorm"
 )
 
 type Reservation struct {
 	gorm.Model
-	Adults         int       `json:"adults"`             
-	Checkin        time.Time `gorm:"not null" json:"check_in"`
-	Checkout       time.Time `gorm:"not null" json:"check_out"`
-	Children       int       `json:"children"`           
-	Email          string    `gorm:"uniqueIndex;not null" json:"email"`
-	NumberOfRooms  int       `json:"number_of_rooms"`    
-	RoomType       string    `json:"room_type"`          
-	UserID         uint      `json:"user_id"`
+	Adults          int        `json:"adults"`
+	Checkin         time.Time  `gorm:"not null" json:"check_in"`
+	Checkout        time.Time  `gorm:"not null" json:"check_out"`
+	Children        int        `json:"children"`
+	Email           string     `gorm:"index:idx_reservations_guest_email;not null" json:"email"`
+	NumberOfRooms   int        `json:"number_of_rooms"`
+	RoomType        string     `json:"room_type"`
+	UserID          uint       `json:"user_id"`
+	GuestName       string     `json:"guest_name"`
+	GroupBlockID    *uint      `gorm:"index" json:"group_block_id,omitempty"`
+	RatePlanID      *uint      `json:"rate_plan_id"`
+	Status          string     `gorm:"not null;default:confirmed" json:"status"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	CheckedInAt     *time.Time `json:"checked_in_at,omitempty"`
+	CheckedOutAt    *time.Time `json:"checked_out_at,omitempty"`
+	CancellationFee float64    `json:"cancellation_fee"`
+	ExternalSource  string     `json:"external_source,omitempty"`                                               // Calendario externo del que se importó la reserva
+	ExternalUID     *string    `gorm:"uniqueIndex" json:"external_uid,omitempty"`                               // UID del evento importado, para no duplicarlo
+	ChannelID       *uint      `gorm:"uniqueIndex:idx_reservations_channel" json:"channel_id,omitempty"`        // Canal de venta del que llegó la reserva
+	ChannelRef      *string    `gorm:"uniqueIndex:idx_reservations_channel" json:"channel_reference,omitempty"` // Número de reserva en el canal
+	Version         uint       `gorm:"not null;default:1" json:"version"`                                       // Versión para el control de concurrencia optimista
+
+	// Condiciones de cancelación de la tarifa al reservar
+	CancellationTerms CancellationTerms `gorm:"embedded;embeddedPrefix:cancellation_" json:"cancellation_terms"`
+}
+
+// BeforeCreate copia en la reserva las condiciones de cancelación de su tarifa, cualquiera sea el
+// origen de la reserva (API, grupos, canales, calendarios o consultas)
+func (r *Reservation) BeforeCreate(tx *gorm.DB) error {
+	return r.AgreeCancellationTerms(tx)
+}
+
+// AgreeCancellationTerms copia en la reserva las condiciones de cancelación vigentes de su tarifa, para
+// que un cambio posterior de la política o del precio no altere lo pactado con el huésped
+func (r *Reservation) AgreeCancellationTerms(tx *gorm.DB) error {
+	r.CancellationTerms = CancellationTerms{}
+	if r.RatePlanID == nil {
+		return nil
+	}
+	terms, err := CancellationTermsFor(tx.Session(&gorm.Session{NewDB: true}), *r.RatePlanID)
+	if err != nil {
+		return err
+	}
+	r.CancellationTerms = terms
+	return nil
+}
+
+// Estados de una reserva
+const (
+	ReservationTentative  = "tentative" // Creada desde una consulta, pendiente de confirmar
+	ReservationConfirmed  = "confirmed"
+	ReservationCheckedIn  = "checked_in"
+	ReservationCheckedOut = "checked_out"
+	ReservationCancelled  = "cancelled"
+)
+
+// Nights devuelve la cantidad de noches entre el check-in y el check-out
+func (r Reservation) Nights() int {
+	in := time.Date(r.Checkin.Year(), r.Checkin.Month(), r.Checkin.Day(), 0, 0, 0, 0, time.UTC)
+	out := time.Date(r.Checkout.Year(), r.Checkout.Month(), r.Checkout.Day(), 0, 0, 0, 0, time.UTC)
+	return int(out.Sub(in).Hours() / 24)
 }
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Restriction son las restricciones de venta de un tipo de habitación para una fecha de llegada
+type Restriction struct {
+	gorm.Model
+	RoomType          string    `gorm:"not null;uniqueIndex:idx_restrictions_day" json:"room_type"`
+	Date              time.Time `gorm:"not null;uniqueIndex:idx_restrictions_day" json:"date"`
+	MinStay           int       `json:"min_stay"` // Noches mínimas para llegadas en la fecha
+	ClosedToArrival   bool      `json:"closed_to_arrival"`
+	ClosedToDeparture bool      `json:"closed_to_departure"`
+	StopSell          bool      `json:"stop_sell"` // No se vende en la fecha
+}
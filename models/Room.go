@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados operativos de una habitación
+const (
+	RoomInService    = "in_service"
+	RoomOutOfService = "out_of_service"
+)
+
+// Estados de limpieza de una habitación
+const (
+	RoomDirty      = "dirty"
+	RoomClean      = "clean"
+	RoomInspected  = "inspected"
+	RoomOutOfOrder = "out_of_order"
+)
+
+// Room es una habitación física del hotel, de un tipo de habitación determinado
+type Room struct {
+	gorm.Model
+	Number       string `gorm:"uniqueIndex;not null" json:"number"`
+	RoomType     string `gorm:"not null;index" json:"room_type"`
+	Floor        int    `json:"floor"`
+	Status       string `gorm:"not null;default:in_service" json:"status"`
+	Housekeeping string `gorm:"not null;default:clean" json:"housekeeping"`
+}
+
+// RoomAssignment asigna una habitación a una reserva durante un tramo de la estancia [StartDate, EndDate).
+// Un cambio de habitación cierra el tramo actual y abre uno nuevo, conservando el historial
+type RoomAssignment struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	ReservationID uint      `gorm:"not null;index" json:"reservation_id"`
+	RoomID        uint      `gorm:"not null;index" json:"room_id"`
+	Room          *Room     `json:"room,omitempty"`
+	StartDate     time.Time `gorm:"not null" json:"start_date"`
+	EndDate       time.Time `gorm:"not null" json:"end_date"`
+	Reason        string    `json:"reason,omitempty"`
+}
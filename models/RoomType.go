@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// RoomType es una categoría de habitación con la cantidad de habitaciones disponibles para la venta.
+// Las reservas la referencian por su código en el campo room_type
+type RoomType struct {
+	gorm.Model
+	Code       string `gorm:"uniqueIndex;not null" json:"code"`
+	Name       string `json:"name"`
+	TotalRooms int    `gorm:"not null" json:"total_rooms"`
+}
@@ -7,9 +7,11 @@ type User struct {
 	gorm.Model
 	FirstName string `gorm:"not null" json:"first_name"`
 	LastName  string `gorm:"not null" json:"last_name"`
-	Email     string `gorm:"not null;uniqueIndex" json:"email"`
+	Email     string `gorm:"not null;uniqueIndex:,where:deleted_at IS NULL" json:"email"`
 	Reservations     []Reservation `json:"reservations"`
 	Consultations     []Consultation `json:"consultations"`
+	Locale    string `gorm:"not null;default:es" json:"locale"` // Idioma de las notificaciones
+	Version   uint   `gorm:"not null;default:1" json:"version"` // Versión para el control de concurrencia optimista
 
 }
 
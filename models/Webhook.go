@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de entrega de un webhook
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead" // Agotó los reintentos: queda en la lista de mensajes muertos
+)
+
+// WebhookSubscription es un sistema externo que recibe los eventos de dominio indicados por HTTP
+type WebhookSubscription struct {
+	gorm.Model
+	URL         string `gorm:"not null" json:"url"`
+	EventTypes  string `gorm:"not null" json:"event_types"`      // Tipos de evento separados por comas, o "*" para todos
+	Secret      string `gorm:"not null" json:"secret,omitempty"` // Clave de la firma HMAC; solo se devuelve al crear la suscripción
+	Description string `json:"description"`
+	Active      bool   `gorm:"not null;default:true" json:"active"`
+}
+
+// Wants indica si la suscripción recibe el tipo de evento indicado
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	for _, t := range strings.Split(s.EventTypes, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery es el envío de un evento a una suscripción, con sus reintentos
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	SubscriptionID uint       `gorm:"not null;uniqueIndex:idx_webhook_delivery_event" json:"subscription_id"`
+	EventID        uint       `gorm:"not null;uniqueIndex:idx_webhook_delivery_event" json:"event_id"`
+	EventType      string     `gorm:"not null;index" json:"event_type"`
+	Body           string     `gorm:"type:text;not null" json:"body"` // Cuerpo JSON que se envía
+	Status         string     `gorm:"not null;default:pending;index" json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Prioridades de una orden de trabajo
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// Estados de una orden de trabajo
+const (
+	WorkOrderOpen       = "open"
+	WorkOrderInProgress = "in_progress"
+	WorkOrderResolved   = "resolved"
+	WorkOrderCancelled  = "cancelled"
+)
+
+// Tipos de período en que una habitación no puede ocuparse
+const (
+	OutageOutOfOrder   = "out_of_order"   // Se descuenta del inventario disponible para la venta
+	OutageOutOfService = "out_of_service" // Sigue en el inventario pero no se asigna a huéspedes
+)
+
+// WorkOrder es una orden de mantenimiento sobre una habitación
+type WorkOrder struct {
+	gorm.Model
+	RoomID      uint         `gorm:"not null;index" json:"room_id"`
+	Room        *Room        `json:"room,omitempty"`
+	Description string       `gorm:"type:text;not null" json:"description"`
+	Priority    string       `gorm:"not null;default:normal" json:"priority"`
+	EmployeeID  *uint        `gorm:"index" json:"employee_id"`
+	Status      string       `gorm:"not null;default:open;index" json:"status"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`
+	ResolvedAt  *time.Time   `json:"resolved_at,omitempty"`
+	Outages     []RoomOutage `json:"outages,omitempty"`
+}
+
+// RoomOutage es un período [StartDate, EndDate) en que la habitación no puede ocuparse. Sin fecha
+// de fin el período sigue abierto hasta que se cierra, por ejemplo al resolver la orden de trabajo
+type RoomOutage struct {
+	gorm.Model
+	RoomID      uint       `gorm:"not null;index" json:"room_id"`
+	WorkOrderID *uint      `gorm:"index" json:"work_order_id,omitempty"`
+	Type        string     `gorm:"not null" json:"type"`
+	StartDate   time.Time  `gorm:"not null" json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Reason      string     `json:"reason"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancellationPenalty(t *testing.T) {
+	checkin := time.Date(2024, 11, 10, 14, 0, 0, 0, time.UTC)
+	reservation := Reservation{Checkin: checkin, Checkout: time.Date(2024, 11, 15, 11, 0, 0, 0, time.UTC), NumberOfRooms: 2}
+	assert.Equal(t, 5, reservation.Nights())
+
+	percentage := CancellationPolicy{FreeUntilDays: 7, PenaltyType: PenaltyPercentage, PenaltyPercent: 50}
+	firstNight := CancellationPolicy{FreeUntilDays: 2, PenaltyType: PenaltyFirstNight}
+
+	// Dentro del plazo gratuito no hay penalización
+	early := checkin.AddDate(0, 0, -10)
+	assert.Equal(t, 0.0, percentage.Penalty(checkin, reservation.Nights(), reservation.NumberOfRooms, 100, early))
+	assert.Equal(t, 0.0, firstNight.Penalty(checkin, reservation.Nights(), reservation.NumberOfRooms, 100, early))
+
+	// Justo en el límite todavía se puede cancelar sin cargo
+	assert.Equal(t, 0.0, percentage.Penalty(checkin, 5, 2, 100, percentage.FreeCancellationDeadline(checkin)))
+
+	// Pasado el plazo se aplica la penalización correspondiente
+	late := checkin.AddDate(0, 0, -1)
+	assert.Equal(t, 500.0, percentage.Penalty(checkin, 5, 2, 100, late))
+	assert.Equal(t, 200.0, firstNight.Penalty(checkin, 5, 2, 100, late))
+}
+
+func TestCancellationTermsPolicy(t *testing.T) {
+	// Una tarifa sin política no tiene penalización
+	assert.Nil(t, CancellationTerms{NightlyRate: 100}.Policy())
+
+	terms := CancellationTerms{PolicyName: "Flexible", FreeUntilDays: 2, PenaltyType: PenaltyFirstNight, NightlyRate: 80}
+	policy := terms.Policy()
+	if assert.NotNil(t, policy) {
+		assert.Equal(t, "Flexible", policy.Name)
+		checkin := time.Date(2024, 11, 10, 14, 0, 0, 0, time.UTC)
+		assert.Equal(t, 80.0, policy.Penalty(checkin, 3, 1, terms.NightlyRate, checkin.AddDate(0, 0, -1)))
+	}
+}
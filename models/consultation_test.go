@@ -0,0 +1,33 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsultationThread(t *testing.T) {
+	now := time.Date(2024, 11, 10, 9, 0, 0, 0, time.UTC)
+	sla := 4 * time.Hour
+	consultation := Consultation{}
+
+	// El mensaje del huésped abre la consulta con su plazo de respuesta
+	consultation.ApplyMessage(MessageFromGuest, now, sla)
+	assert.Equal(t, ConsultationOpen, consultation.Status)
+	assert.Equal(t, now.Add(sla), *consultation.DueAt)
+	assert.False(t, consultation.Overdue(now.Add(time.Hour)))
+	assert.True(t, consultation.Overdue(now.Add(5*time.Hour)))
+
+	// La respuesta del personal detiene el plazo
+	consultation.ApplyMessage(MessageFromStaff, now.Add(time.Hour), sla)
+	assert.Equal(t, ConsultationAnswered, consultation.Status)
+	assert.Nil(t, consultation.DueAt)
+	assert.False(t, consultation.Overdue(now.Add(5*time.Hour)))
+
+	// Un seguimiento del huésped la reabre, aunque estuviera cerrada
+	consultation.Status = ConsultationClosed
+	consultation.ApplyMessage(MessageFromGuest, now.Add(2*time.Hour), sla)
+	assert.Equal(t, ConsultationOpen, consultation.Status)
+	assert.Equal(t, now.Add(2*time.Hour+sla), *consultation.DueAt)
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentCanMoveTo(t *testing.T) {
+	assert.True(t, Payment{Status: PaymentPending}.CanMoveTo(PaymentAuthorized))
+	assert.True(t, Payment{Status: PaymentAuthorized}.CanMoveTo(PaymentCaptured))
+	assert.True(t, Payment{Status: PaymentCaptured}.CanMoveTo(PaymentRefunded))
+
+	// Un webhook repetido o que llega tarde no hace retroceder el pago
+	assert.False(t, Payment{Status: PaymentAuthorized}.CanMoveTo(PaymentAuthorized))
+	assert.False(t, Payment{Status: PaymentVoided}.CanMoveTo(PaymentAuthorized))
+	assert.False(t, Payment{Status: PaymentRefunded}.CanMoveTo(PaymentCaptured))
+	assert.False(t, Payment{Status: PaymentCaptured}.CanMoveTo(PaymentAuthorized))
+	assert.False(t, Payment{Status: PaymentAuthorized}.CanMoveTo("unknown"))
+}
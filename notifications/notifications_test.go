@@ -0,0 +1,173 @@
+package notifications
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/clause"
+)
+
+func testData() Data {
+	reservation := models.Reservation{
+		Checkin:       time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		Checkout:      time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC),
+		NumberOfRooms: 1,
+		Adults:        2,
+	}
+	reservation.ID = 7
+	consultation := models.Consultation{Consultation: "¿Tienen estacionamiento?", Phone: "555-0101"}
+	consultation.ID = 3
+	return Data{
+		Hotel:        invoices.Hotel{Name: "Hotel Test"},
+		GuestName:    "Ana",
+		Reservation:  &reservation,
+		Consultation: &consultation,
+		Message:      "Sí, sin cargo.",
+	}
+}
+
+func TestRenderAllKindsAndLocales(t *testing.T) {
+	kinds := []string{BookingConfirmation, BookingModification, BookingCancellation, PreArrivalReminder,
+		ConsultationReceived, ConsultationAnswered, ConsultationAlert}
+	for _, locale := range Locales {
+		for _, kind := range kinds {
+			subject, body, err := Render(kind, locale, testData())
+			assert.NoError(t, err, locale+"/"+kind)
+			assert.NotEmpty(t, subject, locale+"/"+kind)
+			assert.NotEmpty(t, strings.TrimSpace(body), locale+"/"+kind)
+		}
+	}
+}
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	expectedSubject, expectedBody, err := Render(BookingConfirmation, DefaultLocale(), testData())
+	assert.NoError(t, err)
+
+	subject, body, err := Render(BookingConfirmation, "fr", testData())
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSubject, subject)
+	assert.Equal(t, expectedBody, body)
+	assert.Contains(t, subject, "#7")
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, Backoff(0))
+	assert.Equal(t, time.Minute, Backoff(1))
+	assert.Equal(t, 2*time.Minute, Backoff(2))
+	assert.Equal(t, 16*time.Minute, Backoff(5))
+}
+
+// smtpSink es un servidor SMTP mínimo que acepta un único mensaje y lo devuelve por el canal
+func smtpSink(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	addr, messages := smtpSink(t)
+	sender := &SMTPSender{Addr: addr, From: "hotel@example.com"}
+
+	err := sender.Send("ana@example.com", "Confirmación", "Hola\nAna")
+	assert.NoError(t, err)
+
+	select {
+	case msg := <-messages:
+		assert.Contains(t, msg, "To: ana@example.com\r\n")
+		assert.Contains(t, msg, "From: hotel@example.com\r\n")
+		assert.Contains(t, msg, "Subject: =?utf-8?q?Confirmaci=C3=B3n?=\r\n")
+		assert.Contains(t, msg, "Hola\r\nAna")
+	case <-time.After(2 * time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+// senderFunc adapta una función al Sender
+type senderFunc func(to, subject, body string) error
+
+func (f senderFunc) Send(to, subject, body string) error { return f(to, subject, body) }
+
+func TestDeliverSendsOutsideTransaction(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.Notification{})
+	defer db.DB.Unscoped().Exec("DELETE FROM notifications")
+
+	now := time.Now()
+	assert.NoError(t, Enqueue(db.DB, BookingConfirmation, "es", "ana@example.com", testData(), ""))
+	var notification models.Notification
+	assert.NoError(t, db.DB.Last(&notification).Error)
+
+	sent, err := Deliver(db.DB, senderFunc(func(to, subject, body string) error {
+		// Mientras se envía la fila no está bloqueada y otro proceso no vuelve a tomarla
+		var locked models.Notification
+		assert.NoError(t, db.DB.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).First(&locked, notification.ID).Error)
+		again, err := Deliver(db.DB, senderFunc(func(string, string, string) error {
+			t.Error("notification delivered twice")
+			return nil
+		}), now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, again)
+		return nil
+	}), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	assert.NoError(t, db.DB.First(&notification, notification.ID).Error)
+	assert.Equal(t, models.NotificationSent, notification.Status)
+	assert.Equal(t, 1, notification.Attempts)
+}
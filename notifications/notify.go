@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// guestOf devuelve el usuario indicado, o un usuario vacío si no existe
+func guestOf(tx *gorm.DB, userID uint) (models.User, error) {
+	var user models.User
+	if userID == 0 {
+		return user, nil
+	}
+	err := tx.First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, nil
+	}
+	return user, err
+}
+
+// NotifyReservation encola un email para el huésped de la reserva en su idioma
+func NotifyReservation(tx *gorm.DB, kind string, reservation models.Reservation, reference string) error {
+	if reservation.Email == "" {
+		return nil
+	}
+	user, err := guestOf(tx, reservation.UserID)
+	if err != nil {
+		return err
+	}
+	guestName := reservation.GuestName
+	if guestName == "" {
+		guestName = user.FirstName
+	}
+	data := Data{Hotel: invoices.LoadHotel(), GuestName: guestName, Reservation: &reservation}
+	return Enqueue(tx, kind, user.Locale, reservation.Email, data, reference)
+}
+
+// NotifyConsultation encola un email para el usuario que hizo la consulta en su idioma
+func NotifyConsultation(tx *gorm.DB, kind string, consultation models.Consultation, message, reference string) error {
+	user, err := guestOf(tx, consultation.UserID)
+	if err != nil || user.Email == "" {
+		return err
+	}
+	data := Data{Hotel: invoices.LoadHotel(), GuestName: user.FirstName, Consultation: &consultation, Message: message}
+	return Enqueue(tx, kind, user.Locale, user.Email, data, reference)
+}
+
+// AlertStaff avisa al personal de una consulta nueva. El destinatario se configura con
+// NOTIFY_STAFF_EMAIL; si no está definido se usa el email del hotel
+func AlertStaff(tx *gorm.DB, consultation models.Consultation, reference string) error {
+	hotel := invoices.LoadHotel()
+	recipient := os.Getenv("NOTIFY_STAFF_EMAIL")
+	if recipient == "" {
+		recipient = hotel.Email
+	}
+	if recipient == "" {
+		return nil
+	}
+	user, err := guestOf(tx, consultation.UserID)
+	if err != nil {
+		return err
+	}
+	data := Data{Hotel: hotel, GuestName: strings.TrimSpace(user.FirstName + " " + user.LastName), Consultation: &consultation}
+	return Enqueue(tx, ConsultationAlert, DefaultLocale(), recipient, data, reference)
+}
@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxAttempts es la cantidad de intentos de entrega antes de dar la notificación por fallida
+const MaxAttempts = 6
+
+// ErrNoRecipient se devuelve al encolar una notificación sin destinatario
+var ErrNoRecipient = errors.New("notification has no recipient")
+
+// Backoff devuelve la espera antes del siguiente intento: 1, 2, 4, 8... minutos
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return time.Duration(1<<uint(attempts-1)) * time.Minute
+}
+
+// Enqueue genera la notificación y la guarda en la bandeja de salida dentro de la transacción indicada,
+// para que solo se envíe si el cambio que la origina se confirma. Con reference, una notificación
+// ya encolada con la misma referencia no se duplica
+func Enqueue(tx *gorm.DB, kind, locale, recipient string, data Data, reference string) error {
+	if recipient == "" {
+		return ErrNoRecipient
+	}
+	if !supported(locale) {
+		locale = DefaultLocale()
+	}
+	subject, body, err := Render(kind, locale, data)
+	if err != nil {
+		return err
+	}
+
+	notification := models.Notification{
+		Kind:          kind,
+		Locale:        locale,
+		Recipient:     recipient,
+		Subject:       subject,
+		Body:          body,
+		Status:        models.NotificationPending,
+		NextAttemptAt: time.Now(),
+	}
+	if reference != "" {
+		notification.Reference = &reference
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification).Error
+	}
+	return tx.Create(&notification).Error
+}
+
+// SendLease es el tiempo que una notificación queda reservada para el proceso que la envía. Si el
+// proceso termina sin registrar el resultado, otro la vuelve a intentar cuando vence
+const SendLease = 5 * time.Minute
+
+// Deliver intenta entregar las notificaciones pendientes cuyo próximo intento venció antes de now. Cada
+// notificación se reserva por SendLease en una transacción corta y se envía fuera de ella, para no
+// mantener bloqueada la fila ni abierta la transacción mientras responde el servidor de correo. La
+// reserva, la fecha de envío y el próximo intento se calculan con la hora de cada envío: los envíos
+// lentos del lote no dejan vencidas las reservas de las notificaciones siguientes
+func Deliver(db *gorm.DB, sender Sender, now time.Time) (int, error) {
+	var ids []uint
+	if err := db.Model(&models.Notification{}).
+		Where("status = ? AND next_attempt_at <= ?", models.NotificationPending, now).
+		Order("id asc").Limit(100).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, id := range ids {
+		notification, ok, err := claim(db, id)
+		if err != nil {
+			return sent, err
+		}
+		if !ok {
+			continue
+		}
+
+		claimed := notification.Attempts
+		err = sender.Send(notification.Recipient, notification.Subject, notification.Body)
+		done := time.Now()
+		if err != nil {
+			notification.LastError = err.Error()
+			notification.NextAttemptAt = done.Add(Backoff(notification.Attempts))
+			if notification.Attempts >= MaxAttempts {
+				notification.Status = models.NotificationFailed
+			}
+			log.Printf("notification %d to %s failed (attempt %d): %v", notification.ID, notification.Recipient, notification.Attempts, err)
+		} else {
+			notification.Status = models.NotificationSent
+			notification.SentAt = &done
+			notification.LastError = ""
+			sent++
+		}
+		// Si la reserva venció y otro proceso volvió a tomar la notificación, el resultado es suyo
+		if err := db.Model(&notification).Where("attempts = ?", claimed).
+			Select("Status", "NextAttemptAt", "LastError", "SentAt").Updates(&notification).Error; err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// claim reserva la notificación si sigue pendiente y nadie la tomó: cuenta el intento y corre el próximo
+// intento hasta que vence la reserva. Devuelve false si otro proceso se adelantó
+func claim(db *gorm.DB, id uint) (models.Notification, bool, error) {
+	var notification models.Notification
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.NotificationPending, now).Limit(1).Find(&notification, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		notification.Attempts++
+		notification.NextAttemptAt = now.Add(SendLease)
+		return tx.Model(&notification).Select("Attempts", "NextAttemptAt").Updates(&notification).Error
+	})
+	return notification, err == nil && notification.ID != 0, err
+}
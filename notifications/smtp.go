@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sender entrega un email
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// DefaultSender es el canal de entrega configurado. Sin servidor SMTP queda en nil y las
+// notificaciones se conservan pendientes en la bandeja de salida
+var DefaultSender Sender
+
+// SetupSender configura el canal de entrega a partir de las variables de entorno
+func SetupSender() {
+	if sender := NewSMTPSender(); sender != nil {
+		DefaultSender = sender
+		log.Printf("Sending notifications through SMTP server %s", sender.Addr)
+		return
+	}
+	log.Println("SMTP_HOST not set: notifications will stay queued")
+}
+
+// SMTPSender entrega los emails a un servidor SMTP
+type SMTPSender struct {
+	Addr     string // host:puerto
+	From     string
+	Username string
+	Password string
+}
+
+// NewSMTPSender configura el envío con SMTP_HOST, SMTP_PORT (25 por defecto), SMTP_FROM,
+// SMTP_USERNAME y SMTP_PASSWORD. Devuelve nil si no hay servidor configurado
+func NewSMTPSender() *SMTPSender {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "25"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+	return &SMTPSender{
+		Addr:     host + ":" + port,
+		From:     from,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+// Send arma el mensaje en texto plano UTF-8 y lo entrega al servidor
+func (s *SMTPSender) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host := s.Addr[:strings.LastIndex(s.Addr, ":")]
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return smtp.SendMail(s.Addr, auth, s.From, []string{to}, []byte(msg.String()))
+}
@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"fmt"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// reservationEmails indica qué email recibe el huésped ante cada evento de una reserva
+var reservationEmails = map[string]string{
+	events.ReservationCreated:   BookingConfirmation,
+	events.ReservationConfirmed: BookingConfirmation,
+	events.ReservationModified:  BookingModification,
+	events.ReservationCancelled: BookingCancellation,
+}
+
+// eventReference identifica la notificación generada por un evento, para que un evento entregado
+// más de una vez no duplique el email
+func eventReference(event models.DomainEvent, kind string) string {
+	return fmt.Sprintf("event:%d:%s", event.ID, kind)
+}
+
+// Subscribe registra las notificaciones como suscriptoras de los eventos de dominio
+func Subscribe() {
+	for eventType, kind := range reservationEmails {
+		kind := kind
+		events.Subscribe(eventType, "notifications", func(tx *gorm.DB, event models.DomainEvent) error {
+			var reservation models.Reservation
+			if err := events.Decode(event, &reservation); err != nil {
+				return err
+			}
+			return NotifyReservation(tx, kind, reservation, eventReference(event, kind))
+		})
+	}
+
+	// Acusar recibo al huésped y avisar al personal
+	events.Subscribe(events.ConsultationOpened, "notifications", func(tx *gorm.DB, event models.DomainEvent) error {
+		var payload events.ConsultationPayload
+		if err := events.Decode(event, &payload); err != nil {
+			return err
+		}
+		if err := NotifyConsultation(tx, ConsultationReceived, payload.Consultation, "", eventReference(event, ConsultationReceived)); err != nil {
+			return err
+		}
+		return AlertStaff(tx, payload.Consultation, eventReference(event, ConsultationAlert))
+	})
+
+	// Enviar la respuesta del personal al huésped
+	events.Subscribe(events.ConsultationAnswered, "notifications", func(tx *gorm.DB, event models.DomainEvent) error {
+		var payload events.ConsultationPayload
+		if err := events.Decode(event, &payload); err != nil {
+			return err
+		}
+		return NotifyConsultation(tx, ConsultationAnswered, payload.Consultation, payload.Message, eventReference(event, ConsultationAnswered))
+	})
+}
@@ -0,0 +1,106 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// Tipos de notificación, que coinciden con el nombre de su plantilla
+const (
+	BookingConfirmation  = "booking_confirmation"
+	BookingModification  = "booking_modification"
+	BookingCancellation  = "booking_cancellation"
+	PreArrivalReminder   = "pre_arrival_reminder"
+	ConsultationReceived = "consultation_received"
+	ConsultationAnswered = "consultation_answered"
+	ConsultationAlert    = "consultation_alert" // Aviso al personal de una consulta nueva
+)
+
+// Locales con plantillas propias. El primero es el predeterminado
+var Locales = []string{"es", "en"}
+
+//go:embed templates
+var templateFS embed.FS
+
+// Data son los datos disponibles en las plantillas
+type Data struct {
+	Hotel        invoices.Hotel
+	GuestName    string
+	Reservation  *models.Reservation
+	Consultation *models.Consultation
+	Message      string
+}
+
+// DefaultLocale devuelve el idioma de las notificaciones cuando el destinatario no tiene uno,
+// configurable con NOTIFICATIONS_LOCALE
+func DefaultLocale() string {
+	if locale := os.Getenv("NOTIFICATIONS_LOCALE"); supported(locale) {
+		return locale
+	}
+	return Locales[0]
+}
+
+// supported indica si hay plantillas para el idioma
+func supported(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFuncs devuelve las funciones de formato de fechas e importes del idioma
+func formatFuncs(locale string) template.FuncMap {
+	dateLayout, dateTimeLayout := "02/01/2006", "02/01/2006 15:04"
+	if locale == "en" {
+		dateLayout, dateTimeLayout = "Jan 2, 2006", "Jan 2, 2006 15:04"
+	}
+	deref := func(value interface{}) time.Time {
+		if t, ok := value.(*time.Time); ok && t != nil {
+			return *t
+		}
+		t, _ := value.(time.Time)
+		return t
+	}
+	return template.FuncMap{
+		"date":     func(value interface{}) string { return deref(value).Format(dateLayout) },
+		"datetime": func(value interface{}) string { return deref(value).Format(dateTimeLayout) },
+		"money": func(amount float64) string {
+			formatted := fmt.Sprintf("%.2f", amount)
+			if locale == "es" {
+				formatted = strings.Replace(formatted, ".", ",", 1)
+			}
+			return formatted
+		},
+	}
+}
+
+// Render genera el asunto y el cuerpo de la notificación en el idioma indicado, usando el
+// predeterminado si no hay plantillas para ese idioma
+func Render(kind, locale string, data Data) (string, string, error) {
+	if !supported(locale) {
+		locale = DefaultLocale()
+	}
+	tmpl, err := template.New(kind).Funcs(formatFuncs(locale)).ParseFS(templateFS, "templates/"+locale+"/"+kind+".tmpl")
+	if err != nil {
+		return "", "", err
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()) + "\n", nil
+}
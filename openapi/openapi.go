@@ -0,0 +1,249 @@
+// Package openapi genera la especificación OpenAPI 3 de la API a partir de la tabla de rutas y de los
+// structs que se reciben y devuelven, leyendo sus etiquetas JSON
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Version es la versión de OpenAPI que se genera
+const Version = "3.0.3"
+
+// Route describe una ruta de la API. Request y Response son valores de ejemplo del tipo que se recibe y
+// se devuelve (por ejemplo models.User{} o []models.User{}); nil indica que no hay cuerpo
+type Route struct {
+	Method       string
+	Path         string // Ruta tal como se registra en mux, por ejemplo /invoices/{id:[0-9]+}.pdf
+	Summary      string
+	Tag          string
+	Role         string // Rol mínimo exigido; vacío si la ruta es pública
+	Query        []Parameter
+	Request      interface{}
+	RequestType  string // Tipo de contenido del cuerpo; por defecto application/json
+	OptionalBody bool   // El cuerpo puede omitirse
+	Response     interface{}
+	ContentType  string // Tipo de contenido de la respuesta; por defecto application/json
+}
+
+// Document es el documento OpenAPI
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+
+	matchers matchers
+}
+
+// Info es la descripción general de la API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components agrupa los esquemas, respuestas y esquemas de seguridad reutilizables
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]Response       `json:"responses"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// Operation es una operación (método) sobre una ruta
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter es un parámetro de la ruta o de la consulta
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody es el cuerpo de una solicitud
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response es una respuesta, propia o referencia a una de los componentes
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType es el esquema de un tipo de contenido
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// SecurityScheme es un esquema de autenticación
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Query crea un parámetro de consulta opcional
+func Query(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: schema, Description: description}
+}
+
+// RequiredQuery crea un parámetro de consulta obligatorio
+func RequiredQuery(name string, schema *Schema, description string) Parameter {
+	return Parameter{Name: name, In: "query", Schema: schema, Description: description, Required: true}
+}
+
+// pathParam reconoce los parámetros de mux, con o sin expresión regular
+var pathParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// Path convierte una ruta de mux al formato de OpenAPI quitando las expresiones regulares de los parámetros
+func Path(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+// Key identifica una operación por método y ruta, por ejemplo "GET /users/{id}"
+func Key(method, path string) string {
+	return strings.ToUpper(method) + " " + Path(path)
+}
+
+// Build arma el documento OpenAPI con las rutas indicadas
+func Build(info Info, routes []Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]Operation),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			Responses: map[string]Response{
+				"Error": {
+					Description: "Error. El cuerpo es un mensaje de texto en inglés, por ejemplo \"Invalid request payload\"",
+					Content:     map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}},
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "Token configurado en API_TOKENS. Sin cabecera la solicitud es anónima",
+				},
+			},
+		},
+	}
+	schemas := newGenerator(doc.Components.Schemas)
+	errorResponse := Response{Ref: "#/components/responses/Error"}
+
+	for _, route := range routes {
+		path := Path(route.Path)
+		operation := Operation{
+			OperationID: operationID(route.Method, path),
+			Summary:     route.Summary,
+			Responses:   map[string]Response{"default": errorResponse},
+		}
+		if route.Tag != "" {
+			operation.Tags = []string{route.Tag}
+		}
+
+		for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			operation.Parameters = append(operation.Parameters, Parameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "integer", Minimum: floatPtr(1)},
+			})
+			operation.Responses["404"] = errorResponse
+		}
+		operation.Parameters = append(operation.Parameters, route.Query...)
+		if len(route.Query) > 0 {
+			operation.Responses["400"] = errorResponse
+		}
+
+		if route.Request != nil {
+			contentType := route.RequestType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			operation.RequestBody = &RequestBody{
+				Required: !route.OptionalBody,
+				Content:  map[string]MediaType{contentType: {Schema: schemas.schemaOf(route.Request)}},
+			}
+			operation.Responses["400"] = errorResponse
+		}
+
+		success := Response{Description: "OK"}
+		if route.Response != nil {
+			contentType := route.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			success.Content = map[string]MediaType{contentType: {Schema: schemas.schemaOf(route.Response)}}
+		}
+		operation.Responses["200"] = success
+
+		if route.Role != "" {
+			operation.Description = "Requiere el rol " + route.Role + " o superior"
+			operation.Security = []map[string][]string{{"bearerAuth": {}}}
+			operation.Responses["401"] = errorResponse
+			operation.Responses["403"] = errorResponse
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]Operation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	return doc
+}
+
+// Operations devuelve las operaciones del documento como claves "MÉTODO /ruta" ordenadas
+func (d *Document) Operations() []string {
+	var keys []string
+	for path, methods := range d.Paths {
+		for method := range methods {
+			keys = append(keys, Key(method, path))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// operationID arma un identificador legible a partir del método y la ruta, por ejemplo getUsersById
+func operationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	path = pathParam.ReplaceAllString(path, "by-$1")
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '.' || r == '_' }) {
+		id.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return id.String()
+}
+
+// Handler sirve el documento en formato JSON
+func Handler(doc *Document) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(doc); err != nil {
+			http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		}
+	}
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type guest struct {
+	gorm.Model
+	Name      string     `json:"name"`
+	Email     string     `json:"email,omitempty"`
+	Secret    string     `json:"-"`
+	ArrivesAt time.Time  `json:"arrives_at"`
+	LeftAt    *time.Time `json:"left_at"`
+	Parent    *guest     `json:"parent"`
+	Stays     []stay     `json:"stays"`
+	internal  int
+}
+
+type stay struct {
+	Nights int `json:"nights"`
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/invoices/{id}.pdf", Path("/invoices/{id:[0-9]+}.pdf"))
+	assert.Equal(t, "GET /users/{id}", Key("get", "/users/{id}"))
+	assert.Equal(t, "deleteReservationsByIdRoomsByAssignmentId", operationID("DELETE", "/reservations/{id}/rooms/{assignment_id}"))
+}
+
+func TestSchemas(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, []Route{
+		{Method: "GET", Path: "/guests", Response: []guest{}},
+		{Method: "PUT", Path: "/guests/{id:[0-9]+}", Request: guest{}, Response: guest{}, Role: "manager"},
+	})
+
+	schema := doc.Components.Schemas["Guest"]
+	assert.NotNil(t, schema)
+	// gorm.Model se incorpora con los nombres de campo de Go, como lo codifica encoding/json
+	for _, name := range []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt", "name", "email", "arrives_at", "left_at", "parent", "stays"} {
+		assert.Contains(t, schema.Properties, name)
+	}
+	assert.NotContains(t, schema.Properties, "Secret")
+	assert.NotContains(t, schema.Properties, "internal")
+	assert.Equal(t, "date-time", schema.Properties["arrives_at"].Format)
+	assert.True(t, schema.Properties["left_at"].Nullable)
+	assert.True(t, schema.Properties["DeletedAt"].Nullable)
+	assert.Equal(t, "#/components/schemas/Guest", schema.Properties["parent"].AllOf[0].Ref)
+	assert.Equal(t, "#/components/schemas/Stay", schema.Properties["stays"].Items.Ref)
+
+	list := doc.Paths["/guests"]["get"].Responses["200"].Content["application/json"].Schema
+	assert.Equal(t, "array", list.Type)
+
+	put := doc.Paths["/guests/{id}"]["put"]
+	assert.Equal(t, "id", put.Parameters[0].Name)
+	assert.Equal(t, "path", put.Parameters[0].In)
+	assert.Contains(t, put.Responses, "403")
+	assert.Contains(t, put.Responses, "404")
+	assert.Equal(t, []string{"GET /guests", "PUT /guests/{id}"}, doc.Operations())
+
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, []Route{
+		{Method: "GET", Path: "/guests/dead", Response: []guest{}},
+		{Method: "GET", Path: "/guests/{id}", Query: []Parameter{Query("active", Boolean, ""), RequiredQuery("from", DateTime, "")}, Response: guest{}},
+	})
+
+	// Las rutas fijas tienen prioridad sobre las que tienen parámetros
+	operation, params, ok := doc.Match("GET", "/guests/dead")
+	assert.True(t, ok)
+	assert.Empty(t, params)
+	assert.Equal(t, "getGuestsDead", operation.OperationID)
+	_, _, ok = doc.Match("DELETE", "/guests/1")
+	assert.False(t, ok)
+
+	operation, params, ok = doc.Match("GET", "/guests/abc")
+	assert.True(t, ok)
+	assert.EqualError(t, doc.ValidateParameters(operation, params, nil), "path parameter id: must be an integer")
+	assert.EqualError(t, doc.ValidateParameters(operation, map[string]string{"id": "0"}, nil), "path parameter id: must be at least 1")
+	assert.EqualError(t, doc.ValidateParameters(operation, map[string]string{"id": "7"}, nil), "query parameter from: is required")
+	assert.EqualError(t, doc.ValidateParameters(operation, map[string]string{"id": "7"}, map[string][]string{"from": {"2025-01-01T00:00:00Z"}, "active": {"yes"}}), "query parameter active: must be a boolean")
+	assert.NoError(t, doc.ValidateParameters(operation, map[string]string{"id": "7"}, map[string][]string{"from": {"2025-01-01T00:00:00Z"}}))
+
+	schema := doc.Components.Schemas["Guest"]
+	// En las solicitudes los campos son opcionales; el tipo sí se valida
+	assert.NoError(t, doc.ValidateRequestBody(schema, []byte(`{"name":"Ana","stays":[{"nights":2}]}`), false))
+	assert.EqualError(t, doc.ValidateRequestBody(schema, []byte(`{"stays":[{"nights":1.5}]}`), false), "body.stays[0].nights: must be an integer")
+	assert.EqualError(t, doc.ValidateRequestBody(schema, []byte(`{"arrives_at":"mañana"}`), false), "body.arrives_at: must be an RFC 3339 date-time")
+	assert.EqualError(t, doc.ValidateRequestBody(schema, []byte(`{"name":null}`), false), "body.name: must not be null")
+	assert.NoError(t, doc.ValidateRequestBody(schema, []byte(`{"name":null}`), true))
+	assert.EqualError(t, doc.ValidateRequestBody(schema, []byte(`{`), false), "body: must be valid JSON")
+
+	// En las respuestas se exigen los campos sin omitempty
+	body, _ := json.Marshal(guest{Name: "Ana", Parent: &guest{}})
+	assert.NoError(t, doc.ValidateResponseBody(schema, body))
+	assert.EqualError(t, doc.ValidateResponseBody(schema, []byte(`{"name":"Ana"}`)), "body.ID: is required")
+}
@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Schema es un esquema JSON en el subconjunto que usa OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// Esquemas de uso frecuente en los parámetros de consulta
+var (
+	String   = &Schema{Type: "string"}
+	Integer  = &Schema{Type: "integer"}
+	Boolean  = &Schema{Type: "boolean"}
+	Date     = &Schema{Type: "string", Description: "AAAA-MM-DD o RFC 3339"}
+	DateTime = &Schema{Type: "string", Format: "date-time"}
+)
+
+// Enum crea un esquema de texto con los valores permitidos
+func Enum(values ...string) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+)
+
+// generator convierte tipos de Go en esquemas siguiendo las reglas de encoding/json. Los structs con
+// nombre se registran en los componentes y se referencian con $ref
+type generator struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+}
+
+func newGenerator(schemas map[string]*Schema) *generator {
+	return &generator{schemas: schemas, types: make(map[string]reflect.Type)}
+}
+
+// schemaOf devuelve el esquema del tipo del valor indicado
+func (g *generator) schemaOf(value interface{}) *Schema {
+	return g.schema(reflect.TypeOf(value))
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: floatPtr(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "binary"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := g.componentName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// Se registra antes de recorrer los campos para cortar los tipos recursivos
+			schema := &Schema{}
+			g.schemas[name] = schema
+			*schema = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// object arma el esquema de un struct con sus campos exportados, incorporando los embebidos sin nombre
+// JSON (como gorm.Model) tal como hace encoding/json. Los campos sin omitempty siempre se codifican, por
+// eso se marcan como requeridos
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.fields(t, schema)
+	return schema
+}
+
+func (g *generator) fields(t reflect.Type, object *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				g.fields(fieldType, object)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schema(fieldType)
+		// Los slices y mapas sin valor se codifican como null
+		if kind := fieldType.Kind(); (kind == reflect.Slice && fieldType != rawType && fieldType.Elem().Kind() != reflect.Uint8) || kind == reflect.Map {
+			schema = nullable(schema)
+		}
+		object.Properties[name] = schema
+		if !strings.Contains(options, "omitempty") {
+			object.Required = append(object.Required, name)
+		}
+	}
+}
+
+// componentName es el nombre del tipo con mayúscula inicial; si otro paquete ya usó el mismo nombre se
+// antepone el del paquete
+func (g *generator) componentName(t reflect.Type) string {
+	name := capitalize(t.Name())
+	if existing, ok := g.types[name]; ok && existing != t {
+		parts := strings.Split(t.PkgPath(), "/")
+		name = capitalize(parts[len(parts)-1]) + name
+	}
+	g.types[name] = t
+	return name
+}
+
+// nullable permite null en el esquema. Con $ref no se pueden agregar propiedades, por eso se envuelve en allOf
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	copied := *schema
+	copied.Nullable = true
+	return &copied
+}
+
+func capitalize(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,23 @@
+package openapi
+
+import (
+	_ "embed"
+	"html/template"
+	"net/http"
+)
+
+//go:embed ui.html
+var uiPage string
+
+var uiTemplate = template.Must(template.New("ui").Parse(uiPage))
+
+// UIHandler sirve Swagger UI apuntando al documento publicado en specURL. La página va embebida en el
+// binario; los recursos de Swagger UI se cargan desde el CDN con la versión fijada
+func UIHandler(specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := uiTemplate.Execute(w, specURL); err != nil {
+			http.Error(w, "Failed to render documentation", http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidationError indica qué parte de la solicitud o de la respuesta no cumple la especificación
+type ValidationError struct {
+	Field   string // Ubicación del valor, por ejemplo "path id", "query limit" o "body.adults"
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// matcher reconoce las rutas concretas de una ruta de la especificación
+type matcher struct {
+	path    string
+	pattern *regexp.Regexp
+	params  []string
+}
+
+// matchers compila las rutas una sola vez. Las rutas con más texto fijo van primero para que
+// /webhooks/dead-letters no se confunda con /webhooks/{id}
+type matchers struct {
+	once sync.Once
+	list []matcher
+}
+
+func (d *Document) compile() []matcher {
+	d.matchers.once.Do(func() {
+		for path := range d.Paths {
+			var params []string
+			var pattern strings.Builder
+			pattern.WriteString("^")
+			last := 0
+			for _, loc := range pathParam.FindAllStringSubmatchIndex(path, -1) {
+				pattern.WriteString(regexp.QuoteMeta(path[last:loc[0]]) + "([^/]+)")
+				params = append(params, path[loc[2]:loc[3]])
+				last = loc[1]
+			}
+			pattern.WriteString(regexp.QuoteMeta(path[last:]) + "$")
+			d.matchers.list = append(d.matchers.list, matcher{path: path, pattern: regexp.MustCompile(pattern.String()), params: params})
+		}
+		sort.Slice(d.matchers.list, func(i, j int) bool {
+			a, b := d.matchers.list[i], d.matchers.list[j]
+			fixedA, fixedB := len(pathParam.ReplaceAllString(a.path, "")), len(pathParam.ReplaceAllString(b.path, ""))
+			if fixedA != fixedB {
+				return fixedA > fixedB
+			}
+			return a.path < b.path
+		})
+	})
+	return d.matchers.list
+}
+
+// Match busca la operación que corresponde al método y la ruta, y devuelve los valores de los parámetros
+// de la ruta. Si la ruta no está documentada para ese método no hay operación
+func (d *Document) Match(method, path string) (*Operation, map[string]string, bool) {
+	for _, m := range d.compile() {
+		values := m.pattern.FindStringSubmatch(path)
+		if values == nil {
+			continue
+		}
+		operation, ok := d.Paths[m.path][strings.ToLower(method)]
+		if !ok {
+			return nil, nil, false
+		}
+		params := make(map[string]string, len(m.params))
+		for i, name := range m.params {
+			params[name] = values[i+1]
+		}
+		return &operation, params, true
+	}
+	return nil, nil, false
+}
+
+// ValidateParameters valida los parámetros de la ruta y de la consulta de una operación
+func (d *Document) ValidateParameters(operation *Operation, pathValues map[string]string, query map[string][]string) error {
+	for _, param := range operation.Parameters {
+		var value string
+		var present bool
+		switch param.In {
+		case "path":
+			value, present = pathValues[param.Name]
+		case "query":
+			values := query[param.Name]
+			if len(values) > 0 {
+				value, present = values[0], true
+			}
+		default:
+			continue
+		}
+		field := param.In + " parameter " + param.Name
+		if !present {
+			if param.Required {
+				return &ValidationError{Field: field, Message: "is required"}
+			}
+			continue
+		}
+		if err := d.validateParameter(param.Schema, value); err != nil {
+			return &ValidationError{Field: field, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// validateParameter convierte el texto del parámetro al tipo del esquema y lo valida
+func (d *Document) validateParameter(schema *Schema, value string) error {
+	var parsed interface{} = value
+	switch schema.Type {
+	case "integer", "number":
+		parsed = json.Number(value)
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		parsed = b
+	}
+	if err := (validation{doc: d}).check(schema, parsed, ""); err != nil {
+		return errors.New(err.(*ValidationError).Message)
+	}
+	return nil
+}
+
+// ValidateRequestBody valida un cuerpo JSON contra el esquema de la solicitud. Los campos requeridos
+// no se exigen: cada handler decide qué campos necesita. En un JSON Merge Patch null borra el campo,
+// así que se admite en cualquier propiedad
+func (d *Document) ValidateRequestBody(schema *Schema, body []byte, mergePatch bool) error {
+	value, err := decode(body)
+	if err != nil {
+		return &ValidationError{Field: "body", Message: "must be valid JSON"}
+	}
+	return validation{doc: d, allowNull: mergePatch}.check(schema, value, "body")
+}
+
+// ValidateResponseBody valida un cuerpo JSON contra el esquema de la respuesta, exigiendo los campos
+// que siempre se devuelven
+func (d *Document) ValidateResponseBody(schema *Schema, body []byte) error {
+	value, err := decode(body)
+	if err != nil {
+		return &ValidationError{Field: "body", Message: "must be valid JSON"}
+	}
+	return validation{doc: d, required: true}.check(schema, value, "body")
+}
+
+// decode lee un documento JSON conservando los números tal como vienen
+func decode(body []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// validation recorre un valor JSON decodificado junto con su esquema
+type validation struct {
+	doc       *Document
+	required  bool // Exigir las propiedades requeridas
+	allowNull bool // Admitir null en cualquier propiedad
+}
+
+func (v validation) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		schema = v.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func (v validation) check(schema *Schema, value interface{}, field string) error {
+	schema = v.resolve(schema)
+	if value == nil {
+		if schema.Nullable || v.allowNull || (schema.Type == "" && len(schema.AllOf) == 0) {
+			return nil
+		}
+		return &ValidationError{Field: field, Message: "must not be null"}
+	}
+	for _, part := range schema.AllOf {
+		if err := v.check(part, value, field); err != nil {
+			return err
+		}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be an object"}
+		}
+		if v.required {
+			for _, name := range schema.Required {
+				if _, ok := object[name]; !ok {
+					return &ValidationError{Field: join(field, name), Message: "is required"}
+				}
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := schema.Properties[name]
+			if !ok {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				continue
+			}
+			if err := v.check(property, object[name], join(field, name)); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be an array"}
+		}
+		// Dentro de un arreglo null solo vale si el esquema lo admite
+		itemValidation := v
+		itemValidation.allowNull = false
+		for i, item := range items {
+			if err := itemValidation.check(schema.Items, item, field+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return &ValidationError{Field: field, Message: "must be a string"}
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				return &ValidationError{Field: field, Message: "must be an RFC 3339 date-time"}
+			}
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			return &ValidationError{Field: field, Message: "must be one of " + strings.Join(schema.Enum, ", ")}
+		}
+	case "integer", "number":
+		expected := "must be a number"
+		if schema.Type == "integer" {
+			expected = "must be an integer"
+		}
+		number, ok := value.(json.Number)
+		if !ok {
+			return &ValidationError{Field: field, Message: expected}
+		}
+		parsed, err := number.Float64()
+		if err != nil || (schema.Type == "integer" && parsed != math.Trunc(parsed)) {
+			return &ValidationError{Field: field, Message: expected}
+		}
+		if schema.Minimum != nil && parsed < *schema.Minimum {
+			return &ValidationError{Field: field, Message: fmt.Sprintf("must be at least %v", *schema.Minimum)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{Field: field, Message: "must be a boolean"}
+		}
+	}
+	return nil
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,269 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// Tokens especiales que entiende el proveedor falso para simular distintos escenarios
+const (
+	FakeTokenDecline = "tok_decline" // La autorización es rechazada
+	FakeTokenAsync   = "tok_async"   // La autorización queda pendiente y se confirma por webhook
+)
+
+// FakeSignatureHeader es la cabecera con la firma HMAC de los webhooks del proveedor falso
+const FakeSignatureHeader = "X-Fake-Signature"
+
+type fakeTransaction struct {
+	amount   float64
+	captured float64
+	refunded float64
+	status   string
+}
+
+// FakeProvider es un proveedor de pagos en memoria para desarrollo local y pruebas
+type FakeProvider struct {
+	Secret     string        // Secreto para firmar los webhooks
+	WebhookURL string        // URL a la que se envían los resultados asíncronos
+	Delay      time.Duration // Demora antes de enviar un webhook asíncrono
+
+	mu           sync.Mutex
+	sequence     int
+	transactions map[string]*fakeTransaction
+	results      map[string]Result
+}
+
+// NewFakeProvider crea un proveedor falso que firma sus webhooks con el secreto indicado
+func NewFakeProvider(secret, webhookURL string) *FakeProvider {
+	return &FakeProvider{
+		Secret:       secret,
+		WebhookURL:   webhookURL,
+		Delay:        time.Second,
+		transactions: make(map[string]*fakeTransaction),
+		results:      make(map[string]Result),
+	}
+}
+
+// Name devuelve el identificador del proveedor
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// Authorize reserva el importe indicado en el medio de pago
+func (p *FakeProvider) Authorize(ctx context.Context, req AuthorizeRequest) (Result, error) {
+	if req.Amount <= 0 {
+		return Result{}, ErrInvalidAmount
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.results[req.IdempotencyKey]; ok {
+		return result, nil
+	}
+
+	p.sequence++
+	reference := fmt.Sprintf("fake_%06d", p.sequence)
+	tx := &fakeTransaction{amount: req.Amount, status: models.PaymentAuthorized}
+	result := Result{Reference: reference, Amount: req.Amount}
+
+	switch req.Token {
+	case FakeTokenDecline:
+		tx.status = models.PaymentFailed
+		result.FailureReason = "card_declined"
+	case FakeTokenAsync:
+		tx.status = models.PaymentPending
+		if p.WebhookURL != "" {
+			go p.completeAsync(reference)
+		}
+	}
+
+	result.Status = tx.status
+	p.transactions[reference] = tx
+	p.remember(req.IdempotencyKey, result)
+	return result, nil
+}
+
+// Capture cobra total o parcialmente un importe previamente autorizado.
+// Un importe igual a cero captura todo lo autorizado
+func (p *FakeProvider) Capture(ctx context.Context, reference string, amount float64, idempotencyKey string) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.results[idempotencyKey]; ok {
+		return result, nil
+	}
+
+	tx, ok := p.transactions[reference]
+	if !ok {
+		return Result{}, ErrUnknownReference
+	}
+	if tx.status != models.PaymentAuthorized {
+		return Result{}, ErrInvalidState
+	}
+	if amount == 0 {
+		amount = tx.amount
+	}
+	if amount < 0 || amount > tx.amount {
+		return Result{}, ErrInvalidAmount
+	}
+
+	tx.captured = amount
+	tx.status = models.PaymentCaptured
+	result := Result{Reference: reference, Status: tx.status, Amount: amount}
+	p.remember(idempotencyKey, result)
+	return result, nil
+}
+
+// Refund devuelve total o parcialmente un importe capturado.
+// Un importe igual a cero reembolsa todo lo pendiente de devolver
+func (p *FakeProvider) Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.results[idempotencyKey]; ok {
+		return result, nil
+	}
+
+	tx, ok := p.transactions[reference]
+	if !ok {
+		return Result{}, ErrUnknownReference
+	}
+	if tx.status != models.PaymentCaptured && tx.status != models.PaymentRefunded {
+		return Result{}, ErrInvalidState
+	}
+	remaining := tx.captured - tx.refunded
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount <= 0 || amount > remaining {
+		return Result{}, ErrInvalidAmount
+	}
+
+	tx.refunded += amount
+	if tx.refunded == tx.captured {
+		tx.status = models.PaymentRefunded
+	}
+	result := Result{Reference: reference, Status: tx.status, Amount: amount}
+	p.remember(idempotencyKey, result)
+	return result, nil
+}
+
+// Void anula una autorización que todavía no fue capturada
+func (p *FakeProvider) Void(ctx context.Context, reference string, idempotencyKey string) (Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.results[idempotencyKey]; ok {
+		return result, nil
+	}
+
+	tx, ok := p.transactions[reference]
+	if !ok {
+		return Result{}, ErrUnknownReference
+	}
+	if tx.status != models.PaymentAuthorized && tx.status != models.PaymentPending {
+		return Result{}, ErrInvalidState
+	}
+
+	tx.status = models.PaymentVoided
+	result := Result{Reference: reference, Status: tx.status, Amount: tx.amount}
+	p.remember(idempotencyKey, result)
+	return result, nil
+}
+
+// ParseWebhook valida la firma de un webhook y devuelve el evento que contiene. Sin secreto
+// configurado cualquiera podría firmar, así que se rechazan todos los webhooks
+func (p *FakeProvider) ParseWebhook(r *http.Request) (WebhookEvent, error) {
+	var event WebhookEvent
+	if p.Secret == "" {
+		return event, ErrNoWebhookSecret
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return event, err
+	}
+	if !hmac.Equal([]byte(r.Header.Get(FakeSignatureHeader)), []byte(p.sign(body))) {
+		return event, ErrInvalidSignature
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return event, err
+	}
+	return event, nil
+}
+
+// Complete resuelve una autorización pendiente con el estado indicado y devuelve el
+// webhook que el proveedor enviaría, ya firmado
+func (p *FakeProvider) Complete(reference, status string) (*http.Request, error) {
+	p.mu.Lock()
+	tx, ok := p.transactions[reference]
+	if !ok {
+		p.mu.Unlock()
+		return nil, ErrUnknownReference
+	}
+	if tx.status != models.PaymentPending {
+		p.mu.Unlock()
+		return nil, ErrInvalidState
+	}
+	tx.status = status
+	p.sequence++
+	event := WebhookEvent{
+		ID:        fmt.Sprintf("evt_%06d", p.sequence),
+		Reference: reference,
+		Status:    status,
+		Amount:    tx.amount,
+	}
+	p.mu.Unlock()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FakeSignatureHeader, p.sign(body))
+	return req, nil
+}
+
+// completeAsync simula la confirmación diferida de una autorización pendiente
+func (p *FakeProvider) completeAsync(reference string) {
+	time.Sleep(p.Delay)
+	req, err := p.Complete(reference, models.PaymentAuthorized)
+	if err != nil {
+		log.Printf("fake payment provider: %v", err)
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("fake payment provider: failed to deliver webhook: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func (p *FakeProvider) remember(idempotencyKey string, result Result) {
+	if idempotencyKey != "" {
+		p.results[idempotencyKey] = result
+	}
+}
+
+func (p *FakeProvider) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
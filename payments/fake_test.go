@@ -0,0 +1,107 @@
+package payments
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeProviderAuthorizeCaptureRefund(t *testing.T) {
+	p := NewFakeProvider("secret", "")
+	ctx := context.Background()
+
+	auth, err := p.Authorize(ctx, AuthorizeRequest{Amount: 100, Currency: "ARS", IdempotencyKey: "k1"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentAuthorized, auth.Status)
+
+	captured, err := p.Capture(ctx, auth.Reference, 80, "k1:capture")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentCaptured, captured.Status)
+	assert.Equal(t, 80.0, captured.Amount)
+
+	// Un reembolso superior a lo capturado es rechazado
+	_, err = p.Refund(ctx, auth.Reference, 100, "r1")
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	refund, err := p.Refund(ctx, auth.Reference, 30, "r2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentCaptured, refund.Status)
+
+	refund, err = p.Refund(ctx, auth.Reference, 0, "r3")
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, refund.Amount)
+	assert.Equal(t, models.PaymentRefunded, refund.Status)
+}
+
+func TestFakeProviderIdempotency(t *testing.T) {
+	p := NewFakeProvider("secret", "")
+	ctx := context.Background()
+
+	first, err := p.Authorize(ctx, AuthorizeRequest{Amount: 50, IdempotencyKey: "same"})
+	assert.NoError(t, err)
+	second, err := p.Authorize(ctx, AuthorizeRequest{Amount: 50, IdempotencyKey: "same"})
+	assert.NoError(t, err)
+	assert.Equal(t, first.Reference, second.Reference)
+
+	_, err = p.Capture(ctx, first.Reference, 0, "cap")
+	assert.NoError(t, err)
+	// Repetir la captura con la misma clave devuelve el resultado original en lugar de fallar
+	again, err := p.Capture(ctx, first.Reference, 0, "cap")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentCaptured, again.Status)
+}
+
+func TestFakeProviderDeclineAndVoid(t *testing.T) {
+	p := NewFakeProvider("secret", "")
+	ctx := context.Background()
+
+	declined, err := p.Authorize(ctx, AuthorizeRequest{Amount: 10, Token: FakeTokenDecline, IdempotencyKey: "d"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentFailed, declined.Status)
+	assert.Equal(t, "card_declined", declined.FailureReason)
+
+	auth, _ := p.Authorize(ctx, AuthorizeRequest{Amount: 10, IdempotencyKey: "v"})
+	voided, err := p.Void(ctx, auth.Reference, "v:void")
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentVoided, voided.Status)
+
+	_, err = p.Capture(ctx, auth.Reference, 0, "v:capture")
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestFakeProviderWebhook(t *testing.T) {
+	p := NewFakeProvider("secret", "http://localhost/payments/webhook")
+	ctx := context.Background()
+
+	// Sin URL de webhook configurada en el envío asíncrono, el pago queda pendiente hasta completarlo a mano
+	p.WebhookURL = ""
+	auth, err := p.Authorize(ctx, AuthorizeRequest{Amount: 25, Token: FakeTokenAsync, IdempotencyKey: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentPending, auth.Status)
+
+	p.WebhookURL = "http://localhost/payments/webhook"
+	req, err := p.Complete(auth.Reference, models.PaymentAuthorized)
+	assert.NoError(t, err)
+
+	event, err := p.ParseWebhook(req)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Reference, event.Reference)
+	assert.Equal(t, models.PaymentAuthorized, event.Status)
+
+	// Una firma alterada es rechazada
+	forged := httptest.NewRequest("POST", "/payments/webhook", strings.NewReader(`{"reference":"`+auth.Reference+`","status":"captured"}`))
+	forged.Header.Set(FakeSignatureHeader, "bad")
+	_, err = p.ParseWebhook(forged)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Sin secreto tampoco se acepta un webhook firmado con la clave vacía
+	unsigned := NewFakeProvider("", "")
+	forged = httptest.NewRequest("POST", "/payments/webhook", strings.NewReader(`{"reference":"`+auth.Reference+`","status":"captured"}`))
+	forged.Header.Set(FakeSignatureHeader, unsigned.sign([]byte(`{"reference":"`+auth.Reference+`","status":"captured"}`)))
+	_, err = unsigned.ParseWebhook(forged)
+	assert.ErrorIs(t, err, ErrNoWebhookSecret)
+}
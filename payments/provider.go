@@ -0,0 +1,72 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+)
+
+// Errores comunes que devuelven los proveedores de pago
+var (
+	ErrUnknownReference = errors.New("unknown payment reference")
+	ErrInvalidState     = errors.New("operation not allowed in current payment state")
+	ErrInvalidAmount    = errors.New("invalid payment amount")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrNoWebhookSecret  = errors.New("payment webhook secret not configured")
+)
+
+// AuthorizeRequest contiene los datos necesarios para autorizar un cobro
+type AuthorizeRequest struct {
+	Amount         float64
+	Currency       string
+	Token          string // Token del medio de pago emitido por el proveedor
+	Description    string
+	IdempotencyKey string
+}
+
+// Result es la respuesta del proveedor a cualquier operación
+type Result struct {
+	Reference     string
+	Status        string
+	Amount        float64
+	FailureReason string
+}
+
+// WebhookEvent es una notificación asíncrona del proveedor sobre el estado de un pago
+type WebhookEvent struct {
+	ID        string  `json:"id"`
+	Reference string  `json:"reference"`
+	Status    string  `json:"status"`
+	Amount    float64 `json:"amount"`
+}
+
+// PaymentProvider abstrae la pasarela de pagos. Todas las operaciones reciben una
+// clave de idempotencia: repetir una llamada con la misma clave devuelve el mismo resultado
+type PaymentProvider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (Result, error)
+	Capture(ctx context.Context, reference string, amount float64, idempotencyKey string) (Result, error)
+	Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) (Result, error)
+	Void(ctx context.Context, reference string, idempotencyKey string) (Result, error)
+	ParseWebhook(r *http.Request) (WebhookEvent, error)
+}
+
+// Provider es el proveedor de pagos utilizado por la API
+var Provider PaymentProvider
+
+// SetupProvider configura el proveedor de pagos a partir de las variables de entorno
+func SetupProvider() {
+	switch name := os.Getenv("PAYMENT_PROVIDER"); name {
+	case "", "fake":
+		secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+		Provider = NewFakeProvider(secret, os.Getenv("PAYMENT_WEBHOOK_URL"))
+		log.Println("Using fake payment provider")
+		if secret == "" {
+			log.Println("PAYMENT_WEBHOOK_SECRET is not set: payment webhooks will be rejected")
+		}
+	default:
+		log.Fatalf("unknown payment provider %q", name)
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+)
+
+// GetAuditLogsHandler devuelve las entradas de auditoría, de la más reciente a la más antigua.
+// Admite los filtros resource_type, resource_id, actor, action, request_id, from y to (RFC 3339)
+// y la paginación con limit (100 por defecto, máximo 1000) y offset
+func GetAuditLogsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	conn := db.DB.Model(&models.AuditLog{})
+
+	// Filtros por igualdad
+	for param, column := range map[string]string{
+		"resource_type": "resource_type",
+		"resource_id":   "resource_id",
+		"actor":         "actor",
+		"action":        "action",
+		"request_id":    "request_id",
+	} {
+		if value := query.Get(param); value != "" {
+			conn = conn.Where(column+" = ?", value)
+		}
+	}
+
+	// Filtros por rango de fechas
+	for param, condition := range map[string]string{"from": "created_at >= ?", "to": "created_at <= ?"} {
+		if value := query.Get(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				http.Error(w, "Invalid "+param+" date", http.StatusBadRequest)
+				return
+			}
+			conn = conn.Where(condition, t)
+		}
+	}
+
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 1000 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	var entries []models.AuditLog
+	if err := conn.Order("id desc").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		http.Error(w, "Failed to retrieve audit log", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&entries); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
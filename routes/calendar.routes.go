@@ -0,0 +1,295 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/ical"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// maxCalendarSize es el tamaño máximo aceptado de un archivo .ics importado
+const maxCalendarSize = 5 << 20
+
+// Errores de la importación de un evento, que se informan sin detener el resto de la importación
+var (
+	errCalendarUIDTaken = errors.New("UID already used by another reservation")
+	errCalendarDates    = errors.New("event ends before it starts")
+	errCalendarInStay   = errors.New("reservation already checked in")
+)
+
+// calendarConflict es un evento que no se pudo importar
+type calendarConflict struct {
+	UID   string `json:"uid"`
+	Error string `json:"error"`
+}
+
+// calendarImportResult resume una importación
+type calendarImportResult struct {
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Cancelled int                `json:"cancelled"`
+	Conflicts []calendarConflict `json:"conflicts"`
+}
+
+// findRoomType busca el tipo de habitación indicado en la URL y responde 404 si no existe
+func findRoomType(w http.ResponseWriter, r *http.Request) (models.RoomType, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var roomType models.RoomType
+	if err := db.DB.First(&roomType, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room type not found"))
+			return roomType, false
+		}
+		http.Error(w, "Failed to retrieve room type", http.StatusInternalServerError)
+		return roomType, false
+	}
+	return roomType, true
+}
+
+// GetRoomTypeCalendarHandler exporta en formato iCalendar las fechas ocupadas por las reservas del tipo
+// de habitación desde hoy. Los eventos no incluyen datos del huésped. Con exclude_source se omiten las
+// reservas importadas de ese calendario, para que la plataforma no reciba sus propias reservas
+func GetRoomTypeCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	roomType, ok := findRoomType(w, r)
+	if !ok {
+		return
+	}
+
+	conn := db.DB.Where("room_type = ? AND status <> ? AND checkout > ?", roomType.Code, models.ReservationCancelled, inventory.Day(time.Now())).
+		Order("checkin asc")
+	if source := r.URL.Query().Get("exclude_source"); source != "" {
+		conn = conn.Where("external_source <> ?", source)
+	}
+	var reservations []models.Reservation
+	if err := conn.Find(&reservations).Error; err != nil {
+		http.Error(w, "Failed to retrieve reservations", http.StatusInternalServerError)
+		return
+	}
+
+	hotel := invoices.LoadHotel()
+	calendar := ical.Calendar{
+		ProdID: "-//" + hotel.Name + "//Availability//ES",
+		Name:   strings.TrimSpace(hotel.Name + " " + roomType.Name),
+	}
+	for _, reservation := range reservations {
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:      fmt.Sprintf("reservation-%d-%s@hotel", reservation.ID, roomType.Code),
+			Summary:  "Not available",
+			Start:    inventory.Day(reservation.Checkin),
+			End:      inventory.Day(reservation.Checkout),
+			Sequence: int(reservation.Version),
+			Stamp:    reservation.UpdatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, roomType.Code))
+	if err := ical.Write(w, calendar); err != nil {
+		http.Error(w, "Failed to write calendar", http.StatusInternalServerError)
+	}
+}
+
+// ImportRoomTypeCalendarHandler importa un archivo .ics de un calendario externo (source) y crea una
+// reserva que bloquea una habitación del tipo por cada evento. Los eventos se identifican por su UID:
+// reimportar el mismo archivo no duplica reservas, los eventos con fechas nuevas mueven la reserva y
+// los cancelados o que ya no aparecen en el archivo la cancelan. Los eventos sin disponibilidad se
+// informan como conflictos sin detener la importación
+func ImportRoomTypeCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	roomType, ok := findRoomType(w, r)
+	if !ok {
+		return
+	}
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		source = "ical"
+	}
+
+	calendarEvents, err := ical.Parse(http.MaxBytesReader(w, r.Body, maxCalendarSize))
+	if err != nil {
+		http.Error(w, "Invalid calendar: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := calendarImportResult{Conflicts: []calendarConflict{}}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		seen := make(map[string]bool)
+		for _, event := range calendarEvents {
+			if event.UID == "" {
+				result.Conflicts = append(result.Conflicts, calendarConflict{Error: "event has no UID"})
+				continue
+			}
+			seen[event.UID] = true
+
+			var outcome string
+			err := tx.Transaction(func(sp *gorm.DB) error {
+				var err error
+				outcome, err = importCalendarEvent(sp, r, roomType, source, event)
+				return err
+			})
+			if errors.Is(err, inventory.ErrNoAvailability) || errors.Is(err, errCalendarUIDTaken) ||
+				errors.Is(err, errCalendarDates) || errors.Is(err, errCalendarInStay) {
+				result.Conflicts = append(result.Conflicts, calendarConflict{UID: event.UID, Error: err.Error()})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			countCalendarOutcome(&result, outcome)
+		}
+
+		// Las reservas futuras que ya no aparecen en el calendario de origen se cancelaron allí
+		var imported []models.Reservation
+		if err := tx.Where("room_type = ? AND external_source = ? AND status = ? AND checkout > ?",
+			roomType.Code, source, models.ReservationConfirmed, inventory.Day(time.Now())).Find(&imported).Error; err != nil {
+			return err
+		}
+		for _, reservation := range imported {
+			if reservation.ExternalUID == nil || seen[*reservation.ExternalUID] {
+				continue
+			}
+			if err := cancelImportedReservation(tx, r, reservation); err != nil {
+				return err
+			}
+			result.Cancelled++
+		}
+		return nil
+	})
+	if err != nil {
+		writeSaveError(w, err, "Failed to import calendar")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&result); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// countCalendarOutcome suma el resultado de un evento importado
+func countCalendarOutcome(result *calendarImportResult, outcome string) {
+	switch outcome {
+	case "created":
+		result.Created++
+	case "updated":
+		result.Updated++
+	case "cancelled":
+		result.Cancelled++
+	default:
+		result.Unchanged++
+	}
+}
+
+// importCalendarEvent crea, mueve o cancela la reserva de un evento importado y devuelve qué hizo
+func importCalendarEvent(tx *gorm.DB, r *http.Request, roomType models.RoomType, source string, event ical.Event) (string, error) {
+	start, end := inventory.Day(event.Start), inventory.Day(event.End)
+	if end.Equal(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	if end.Before(start) {
+		return "", errCalendarDates
+	}
+	cancelled := event.Status == ical.StatusCancelled
+
+	var reservation models.Reservation
+	if err := tx.Unscoped().Where("external_uid = ?", event.UID).Limit(1).Find(&reservation).Error; err != nil {
+		return "", err
+	}
+
+	// Evento nuevo
+	if reservation.ID == 0 {
+		if cancelled {
+			return "unchanged", nil
+		}
+		if err := inventory.Reserve(tx, roomType.Code, start, end, 1); err != nil {
+			return "", err
+		}
+		uid := event.UID
+		reservation = models.Reservation{
+			Checkin:        start,
+			Checkout:       end,
+			NumberOfRooms:  1,
+			RoomType:       roomType.Code,
+			GuestName:      event.Summary,
+			Status:         models.ReservationConfirmed,
+			ExternalSource: source,
+			ExternalUID:    &uid,
+		}
+		if err := tx.Create(&reservation).Error; err != nil {
+			return "", err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceReservation, reservation.ID, nil, &reservation); err != nil {
+			return "", err
+		}
+		return "created", events.PublishReservation(tx, events.ReservationCreated, reservation)
+	}
+
+	if reservation.DeletedAt.Valid || reservation.RoomType != roomType.Code || reservation.ExternalSource != source {
+		return "", errCalendarUIDTaken
+	}
+	if reservation.Status == models.ReservationCheckedIn || reservation.Status == models.ReservationCheckedOut {
+		if cancelled || !reservation.Checkin.Equal(start) || !reservation.Checkout.Equal(end) {
+			return "", errCalendarInStay
+		}
+		return "unchanged", nil
+	}
+
+	// Evento cancelado en el origen
+	if cancelled {
+		if reservation.Status == models.ReservationCancelled {
+			return "unchanged", nil
+		}
+		return "cancelled", cancelImportedReservation(tx, r, reservation)
+	}
+
+	// Evento movido, o que vuelve a aparecer después de cancelado
+	if reservation.Status != models.ReservationCancelled && reservation.Checkin.Equal(start) && reservation.Checkout.Equal(end) {
+		return "unchanged", nil
+	}
+	before := reservation
+	// Liberar la habitación que ocupa la reserva antes de comprobar las fechas nuevas
+	if err := tx.Model(&reservation).UpdateColumn("status", models.ReservationCancelled).Error; err != nil {
+		return "", err
+	}
+	if err := inventory.Reserve(tx, roomType.Code, start, end, 1); err != nil {
+		return "", err
+	}
+	reservation.Checkin = start
+	reservation.Checkout = end
+	reservation.Status = models.ReservationConfirmed
+	reservation.CancelledAt = nil
+	if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+		return "", err
+	}
+	if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+		return "", err
+	}
+	return "updated", events.PublishReservation(tx, events.ReservationModified, reservation)
+}
+
+// cancelImportedReservation cancela sin cargo una reserva importada que se canceló en el calendario de origen
+func cancelImportedReservation(tx *gorm.DB, r *http.Request, reservation models.Reservation) error {
+	now := time.Now()
+	before := reservation
+	reservation.Status = models.ReservationCancelled
+	reservation.CancelledAt = &now
+	if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+		return err
+	}
+	if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+		return err
+	}
+	return events.PublishReservation(tx, events.ReservationCancelled, reservation)
+}
@@ -0,0 +1,163 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cancellationQuote es el resultado de calcular la cancelación de una reserva
+type cancellationQuote struct {
+	Reservation models.Reservation         `json:"reservation"`
+	Policy      *models.CancellationPolicy `json:"policy,omitempty"`
+	FreeUntil   *time.Time                 `json:"free_until,omitempty"`
+	Penalty     float64                    `json:"penalty"`
+}
+
+// quoteCancellation calcula la penalización que corresponde si la reserva se cancela en el momento indicado,
+// con las condiciones de cancelación pactadas al reservar. Las reservas anteriores a que se guardaran esas
+// condiciones usan la política vigente de su tarifa. Las reservas sin tarifa o cuya tarifa no tiene
+// política se cancelan sin cargo
+func quoteCancellation(tx *gorm.DB, reservation models.Reservation, now time.Time) (cancellationQuote, error) {
+	quote := cancellationQuote{Reservation: reservation}
+	if reservation.RatePlanID == nil {
+		return quote, nil
+	}
+
+	terms := reservation.CancellationTerms
+	if terms.RatePlanID == nil || *terms.RatePlanID != *reservation.RatePlanID {
+		var err error
+		if terms, err = models.CancellationTermsFor(tx, *reservation.RatePlanID); err != nil {
+			return quote, err
+		}
+	}
+	policy := terms.Policy()
+	if policy == nil {
+		return quote, nil
+	}
+
+	freeUntil := policy.FreeCancellationDeadline(reservation.Checkin)
+	quote.Policy = policy
+	quote.FreeUntil = &freeUntil
+	quote.Penalty = policy.Penalty(reservation.Checkin, reservation.Nights(), reservation.NumberOfRooms, terms.NightlyRate, now)
+	return quote, nil
+}
+
+// GetCancellationQuoteHandler informa la penalización que se cobraría si la reserva se cancelara ahora
+func GetCancellationQuoteHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	quote, err := quoteCancellation(db.DB, reservation, time.Now())
+	if err != nil {
+		http.Error(w, "Failed to compute cancellation penalty", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&quote); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CancelReservationHandler cancela una reserva conservando el registro y carga la penalización en el folio
+func CancelReservationHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	quote, err := cancelReservation(r, reservation)
+	if errors.Is(err, errAlreadyCancelled) {
+		http.Error(w, "Reservation already cancelled", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, errNotCancellable) {
+		http.Error(w, "Only tentative or confirmed reservations can be cancelled", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeSaveError(w, err, "Failed to cancel reservation")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&quote); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// errAlreadyCancelled indica que la reserva ya estaba cancelada
+var errAlreadyCancelled = errors.New("reservation already cancelled")
+
+// errNotCancellable indica que la estancia ya comenzó o terminó, así que la reserva no puede cancelarse
+var errNotCancellable = errors.New("only tentative or confirmed reservations can be cancelled")
+
+// cancelReservation cancela la reserva, cobra la penalización en el folio y publica el evento. La
+// comparten el handler REST, la mutación GraphQL y el servicio gRPC. La reserva se vuelve a leer
+// bloqueada dentro de la transacción, así dos cancelaciones simultáneas no cobran la penalización dos veces
+func cancelReservation(r *http.Request, reservation models.Reservation) (cancellationQuote, error) {
+	now := time.Now()
+	var quote cancellationQuote
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, reservation.ID).Error; err != nil {
+			return err
+		}
+		if reservation.Status == models.ReservationCancelled {
+			return errAlreadyCancelled
+		}
+		if reservation.Status != models.ReservationTentative && reservation.Status != models.ReservationConfirmed {
+			return errNotCancellable
+		}
+
+		var err error
+		if quote, err = quoteCancellation(tx, reservation, now); err != nil {
+			return err
+		}
+
+		before := reservation
+		reservation.Status = models.ReservationCancelled
+		reservation.CancelledAt = &now
+		reservation.CancellationFee = quote.Penalty
+		if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+			return err
+		}
+		quote.Reservation = reservation
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+			return err
+		}
+		if err := events.PublishReservation(tx, events.ReservationCancelled, reservation); err != nil {
+			return err
+		}
+
+		if quote.Penalty == 0 {
+			return nil
+		}
+		folio, err := findOrCreateFolio(tx, reservation.ID)
+		if err != nil {
+			return err
+		}
+		fee := models.FolioEntry{
+			FolioID:     folio.ID,
+			Type:        models.FolioEntryCharge,
+			Description: fmt.Sprintf("Cargo por cancelación (%s)", quote.Policy.Name),
+			Amount:      quote.Penalty,
+			TaxRate:     invoices.LoadHotel().TaxRate,
+		}
+		if err := tx.Create(&fee).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceFolioEntry, fee.ID, nil, &fee)
+	})
+	return quote, err
+}
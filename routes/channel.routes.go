@@ -0,0 +1,334 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/channels"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// validChannel comprueba el código y que el endpoint sea una URL absoluta http(s)
+func validChannel(channel models.Channel) bool {
+	endpoint, err := url.Parse(channel.Endpoint)
+	return err == nil && channel.Code != "" && (endpoint.Scheme == "http" || endpoint.Scheme == "https") && endpoint.Host != ""
+}
+
+// withoutAPIKey devuelve el canal sin su clave, para responder y auditar
+func withoutAPIKey(channel models.Channel) models.Channel {
+	channel.APIKey = ""
+	return channel
+}
+
+// findChannel busca el canal indicado en la URL con sus mapeos y responde 404 si no existe
+func findChannel(w http.ResponseWriter, r *http.Request) (models.Channel, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var channel models.Channel
+	if err := db.DB.Preload("RoomMappings").Preload("RateMappings").First(&channel, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Channel not found"))
+			return channel, false
+		}
+		http.Error(w, "Failed to retrieve channel", http.StatusInternalServerError)
+		return channel, false
+	}
+	return channel, true
+}
+
+// GetChannelsHandler obtiene los canales de venta
+func GetChannelsHandler(w http.ResponseWriter, r *http.Request) {
+	var channelList []models.Channel
+	if err := db.DB.Order("id asc").Find(&channelList).Error; err != nil {
+		http.Error(w, "Failed to retrieve channels", http.StatusInternalServerError)
+		return
+	}
+	for i := range channelList {
+		channelList[i].APIKey = ""
+	}
+
+	if err := json.NewEncoder(w).Encode(&channelList); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetChannelHandler obtiene un canal con sus mapeos
+func GetChannelHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+	channel = withoutAPIKey(channel)
+
+	if err := json.NewEncoder(w).Encode(&channel); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateChannelHandler da de alta un canal. Los mapeos se configuran aparte
+func CreateChannelHandler(w http.ResponseWriter, r *http.Request) {
+	channel := models.Channel{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&channel); err != nil || !validChannel(channel) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	channel.RoomMappings, channel.RateMappings = nil, nil
+	channel.PullCursor = ""
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&channel).Error; err != nil {
+			return err
+		}
+		// Active tiene valor por defecto en la base de datos: guardar explícitamente un canal inactivo
+		if !channel.Active {
+			if err := tx.Model(&channel).Update("active", false).Error; err != nil {
+				return err
+			}
+		}
+		after := withoutAPIKey(channel)
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceChannel, channel.ID, nil, &after)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&channel); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateChannelHandler modifica los datos de conexión de un canal. La clave solo cambia si se envía una nueva
+func UpdateChannelHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+
+	var updated models.Channel
+	if err := json.NewDecoder(r.Body).Decode(&updated); err != nil || !validChannel(updated) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before := withoutAPIKey(channel)
+	channel.Code = updated.Code
+	channel.Name = updated.Name
+	channel.Endpoint = updated.Endpoint
+	channel.HotelCode = updated.HotelCode
+	channel.Active = updated.Active
+	if updated.APIKey != "" {
+		channel.APIKey = updated.APIKey
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("RoomMappings", "RateMappings").Save(&channel).Error; err != nil {
+			return err
+		}
+		after := withoutAPIKey(channel)
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceChannel, channel.ID, &before, &after)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update channel", http.StatusInternalServerError)
+		return
+	}
+
+	channel = withoutAPIKey(channel)
+	if err := json.NewEncoder(w).Encode(&channel); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// channelMappings es el cuerpo con el que se reemplazan las correspondencias de un canal
+type channelMappings struct {
+	RoomMappings []models.ChannelRoomMapping `json:"room_mappings"`
+	RateMappings []models.ChannelRateMapping `json:"rate_mappings"`
+}
+
+// UpdateChannelMappingsHandler reemplaza los mapeos de habitaciones y tarifas del canal
+func UpdateChannelMappingsHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+
+	var body channelMappings
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	for i, mapping := range body.RoomMappings {
+		if mapping.ChannelRoomCode == "" || mapping.RoomType == "" {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		body.RoomMappings[i].ID, body.RoomMappings[i].ChannelID = 0, channel.ID
+	}
+	for i, mapping := range body.RateMappings {
+		if mapping.ChannelRateCode == "" || mapping.RatePlanID == 0 {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		body.RateMappings[i].ID, body.RateMappings[i].ChannelID = 0, channel.ID
+	}
+
+	before := withoutAPIKey(channel)
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("channel_id = ?", channel.ID).Delete(&models.ChannelRoomMapping{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("channel_id = ?", channel.ID).Delete(&models.ChannelRateMapping{}).Error; err != nil {
+			return err
+		}
+		if len(body.RoomMappings) > 0 {
+			if err := tx.Create(&body.RoomMappings).Error; err != nil {
+				return err
+			}
+		}
+		if len(body.RateMappings) > 0 {
+			if err := tx.Create(&body.RateMappings).Error; err != nil {
+				return err
+			}
+		}
+		channel.RoomMappings, channel.RateMappings = body.RoomMappings, body.RateMappings
+		after := withoutAPIKey(channel)
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceChannel, channel.ID, &before, &after)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	channel = withoutAPIKey(channel)
+	if err := json.NewEncoder(w).Encode(&channel); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// channelPushResult resume un envío de disponibilidad y tarifas al canal
+type channelPushResult struct {
+	Updates int `json:"updates"`
+}
+
+// PushChannelHandler envía al canal la disponibilidad, las tarifas y las restricciones entre from y to,
+// por defecto desde hoy y por CHANNEL_ARI_DAYS días
+func PushChannelHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+
+	from := inventory.Day(time.Now())
+	to := from.AddDate(0, 0, channels.ARIDays())
+	query := r.URL.Query()
+	if value := query.Get("from"); value != "" {
+		var err error
+		if from, err = parseDate(value); err != nil {
+			http.Error(w, "Invalid from date", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		var err error
+		if to, err = parseDate(value); err != nil || !to.After(from) {
+			http.Error(w, "Invalid to date", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sent, err := channels.Push(db.DB, channels.NewClient(channel), channel, from, to)
+	if err != nil {
+		http.Error(w, "Failed to push to channel: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	response := channelPushResult{sent}
+	if err := json.NewEncoder(w).Encode(&response); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// PullChannelHandler importa las reservas nuevas, modificadas y canceladas del canal
+func PullChannelHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := channels.Pull(db.DB, channels.NewClient(channel), channel, r)
+	if err != nil {
+		http.Error(w, "Failed to pull from channel: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&result); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetChannelConflictsHandler obtiene los conflictos de importación del canal, con filtro opcional por status
+func GetChannelConflictsHandler(w http.ResponseWriter, r *http.Request) {
+	channel, ok := findChannel(w, r)
+	if !ok {
+		return
+	}
+
+	conn := db.DB.Where("channel_id = ?", channel.ID).Order("id desc").Limit(100)
+	if status := r.URL.Query().Get("status"); status != "" {
+		conn = conn.Where("status = ?", status)
+	}
+	var conflicts []models.ChannelConflict
+	if err := conn.Find(&conflicts).Error; err != nil {
+		http.Error(w, "Failed to retrieve channel conflicts", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&conflicts); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// ResolveChannelConflictHandler marca un conflicto como resuelto después de revisarlo
+func ResolveChannelConflictHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var conflict models.ChannelConflict
+	if err := db.DB.First(&conflict, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Channel conflict not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve channel conflict", http.StatusInternalServerError)
+		return
+	}
+	if conflict.Status == models.ConflictResolved {
+		http.Error(w, "Channel conflict is already resolved", http.StatusConflict)
+		return
+	}
+
+	before := conflict
+	now := time.Now()
+	conflict.Status = models.ConflictResolved
+	conflict.ResolvedAt = &now
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&conflict).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceChannelConflict, conflict.ID, &before, &conflict)
+	})
+	if err != nil {
+		http.Error(w, "Failed to resolve channel conflict", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&conflict); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errStaleWrite indica que el registro fue modificado por otra solicitud desde que se leyó
+var errStaleWrite = errors.New("record was modified by another request")
+
+// etag construye la ETag de un registro a partir de su versión
+func etag(version uint) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// setETag agrega la ETag del registro a la respuesta
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// checkIfMatch verifica la cabecera If-Match contra la versión actual del registro.
+// Sin cabecera la escritura se permite; si no coincide se responde 412
+func checkIfMatch(w http.ResponseWriter, r *http.Request, version uint) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag(version) {
+			return true
+		}
+	}
+	http.Error(w, "Precondition failed: record was modified", http.StatusPreconditionFailed)
+	return false
+}
+
+// saveVersioned guarda el registro solo si su versión no cambió desde que se leyó e incrementa
+// la versión. Si otra solicitud lo modificó antes devuelve errStaleWrite
+func saveVersioned(tx *gorm.DB, record interface{}, version *uint) error {
+	current := *version
+	*version = current + 1
+	result := tx.Model(record).Where("version = ?", current).Select("*").Omit(clause.Associations).Updates(record)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = errStaleWrite
+	}
+	if result.Error != nil {
+		*version = current
+	}
+	return result.Error
+}
+
+// writeSaveError responde 412 cuando la escritura perdió la carrera con otra solicitud y 500 en otro caso
+func writeSaveError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, errStaleWrite) {
+		http.Error(w, "Precondition failed: record was modified", http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, message, http.StatusInternalServerError)
+}
+
+// readMergePatch lee el cuerpo de una solicitud PATCH, que debe ser un JSON Merge Patch
+func readMergePatch(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, "application/json") {
+		http.Error(w, "Unsupported media type, use application/merge-patch+json", http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
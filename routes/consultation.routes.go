@@ -3,17 +3,41 @@ package routes
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
-// GetConsultationsHandler obtiene todas las consultas desde la base de datos en orden ascendente por ID y las devuelve en formato JSON
+// consultationSLA devuelve el plazo para responder una consulta. Se configura en horas con
+// CONSULTATION_SLA_HOURS (24 por defecto)
+func consultationSLA() time.Duration {
+	hours := 24
+	if value, err := strconv.Atoi(os.Getenv("CONSULTATION_SLA_HOURS")); err == nil && value > 0 {
+		hours = value
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// GetConsultationsHandler obtiene todas las consultas desde la base de datos en orden ascendente por ID y las devuelve en formato JSON.
+// Acepta los filtros status, assigned_employee_id, unassigned=true y overdue=true
 func GetConsultationsHandler(w http.ResponseWriter, r *http.Request) {
 	var consultations []models.Consultation
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	// Aplicar los filtros de la bandeja de entrada
+	conn = filterConsultations(conn, r.URL.Query())
 	// Buscar todas las consultas en la base de datos y ordenarlas por ID en orden ascendente
-	if err := db.DB.Order("id asc").Find(&consultations).Error; err != nil {
+	if err := conn.Order("id asc").Find(&consultations).Error; err != nil {
 		// Manejar el error si ocurre al buscar las consultas
 		http.Error(w, "Failed to retrieve consultations", http.StatusInternalServerError)
 		return
@@ -26,12 +50,34 @@ func GetConsultationsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// filterConsultations aplica los filtros de la bandeja de entrada: status, assigned_employee_id,
+// unassigned=true y overdue=true
+func filterConsultations(conn *gorm.DB, query url.Values) *gorm.DB {
+	if status := query.Get("status"); status != "" {
+		conn = conn.Where("status = ?", status)
+	}
+	if employeeID := query.Get("assigned_employee_id"); employeeID != "" {
+		conn = conn.Where("assigned_employee_id = ?", employeeID)
+	}
+	if query.Get("unassigned") == "true" {
+		conn = conn.Where("assigned_employee_id IS NULL AND status <> ?", models.ConsultationClosed)
+	}
+	if query.Get("overdue") == "true" {
+		conn = conn.Where("status = ? AND due_at < ?", models.ConsultationOpen, time.Now())
+	}
+	return conn
+}
+
 // GetConsultationHandler obtiene una consulta específica por ID y la devuelve en formato JSON
 func GetConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
 	var consultation models.Consultation
-	// Buscar una consulta específica por ID
-	if err := db.DB.First(&consultation, params["id"]).Error; err != nil {
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	// Buscar una consulta específica por ID junto con los mensajes del hilo
+	if err := conn.Preload("Messages", func(tx *gorm.DB) *gorm.DB { return tx.Order("id asc") }).First(&consultation, params["id"]).Error; err != nil {
 		// Verificar si la consulta no fue encontrada
 		if err.Error() == "record not found" {
 			// Si la consulta no existe, devolver un error 404
@@ -61,8 +107,8 @@ func CreateConsultationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Crear la nueva consulta en la base de datos
-	if err := db.DB.Create(&consultation).Error; err != nil {
+	// Crear la nueva consulta en la base de datos y registrarla en la auditoría
+	if err := createConsultation(r, &consultation); err != nil {
 		// Manejar el error si ocurre al crear la consulta
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -75,6 +121,39 @@ func CreateConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// createConsultation da de alta la consulta abierta, sin asignar, sin convertir y con el plazo de respuesta
+// corriendo, guarda su texto como primer mensaje del hilo y publica el evento. La comparten el handler
+// REST y el servicio gRPC
+func createConsultation(r *http.Request, consultation *models.Consultation) error {
+	consultation.Messages = nil
+	consultation.AssignedEmployeeID = nil
+	consultation.AnsweredAt = nil
+	consultation.ClosedAt = nil
+	// La conversión en reserva solo la registra POST /consultations/{id}/convert
+	consultation.ReservationID = nil
+	consultation.ConvertedAt = nil
+	consultation.ConvertedByID = nil
+	consultation.ApplyMessage(models.MessageFromGuest, time.Now(), consultationSLA())
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(consultation).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceConsultation, consultation.ID, nil, consultation); err != nil {
+			return err
+		}
+		// El texto de la consulta es el primer mensaje del hilo
+		if consultation.Consultation != "" {
+			message := models.ConsultationMessage{ConsultationID: consultation.ID, Author: models.MessageFromGuest, Body: consultation.Consultation}
+			if err := tx.Create(&message).Error; err != nil {
+				return err
+			}
+			consultation.Messages = append(consultation.Messages, message)
+		}
+		return events.PublishConsultation(tx, events.ConsultationOpened, *consultation, consultation.Consultation)
+	})
+}
+
 // UpdateConsultationHandler actualiza una consulta existente por ID con los datos proporcionados
 func UpdateConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -102,14 +181,8 @@ func UpdateConsultationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Actualizar los campos de la consulta existente con los datos proporcionados
-	consultation.Phone = updatedConsultation.Phone
-	consultation.Consultation = updatedConsultation.Consultation
-	consultation.MoreInfo = updatedConsultation.MoreInfo
-	consultation.UserID = updatedConsultation.UserID
-
-	// Guardar los cambios en la base de datos
-	if err := db.DB.Save(&consultation).Error; err != nil {
+	// Guardar los cambios en la base de datos junto con su registro de auditoría
+	if err := updateConsultation(r, &consultation, updatedConsultation); err != nil {
 		// Manejar el error si ocurre al guardar la consulta actualizada
 		http.Error(w, "Failed to update consultation", http.StatusInternalServerError)
 		return
@@ -122,6 +195,24 @@ func UpdateConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// updateConsultation reemplaza los datos de contacto y el texto de la consulta y registra la
+// modificación en la auditoría. El estado, la asignación y la conversión en reserva se cambian con sus
+// propias rutas, así que se conservan los valores guardados
+func updateConsultation(r *http.Request, consultation *models.Consultation, updated models.Consultation) error {
+	before := *consultation
+	consultation.Phone = updated.Phone
+	consultation.Consultation = updated.Consultation
+	consultation.MoreInfo = updated.MoreInfo
+	consultation.UserID = updated.UserID
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(consultation).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceConsultation, consultation.ID, &before, consultation)
+	})
+}
+
 // DeleteConsultationHandler elimina una consulta específica por ID
 func DeleteConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -140,8 +231,8 @@ func DeleteConsultationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Eliminar la consulta de la base de datos
-	if err := db.DB.Unscoped().Delete(&consultation).Error; err != nil {
+	// Eliminar lógicamente la consulta: puede recuperarse hasta que se purgue
+	if err := deleteConsultation(r, consultation); err != nil {
 		// Manejar el error si ocurre al eliminar la consulta
 		http.Error(w, "Failed to delete consultation", http.StatusInternalServerError)
 		return
@@ -150,3 +241,13 @@ func DeleteConsultationHandler(w http.ResponseWriter, r *http.Request) {
 	// Devolver un estado 200 OK si la eliminación fue exitosa
 	w.WriteHeader(http.StatusOK)
 }
+
+// deleteConsultation elimina lógicamente la consulta y la registra en la auditoría
+func deleteConsultation(r *http.Request, consultation models.Consultation) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&consultation).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceConsultation, consultation.ID, &consultation, nil)
+	})
+}
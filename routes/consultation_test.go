@@ -9,30 +9,32 @@ import (
 	"testing"
 
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
-// Configura el router para las pruebas de Consultations
-func setupConsultationRouter() *mux.Router {
+// Configura el router para las pruebas de Consultations. Las respuestas se validan contra la especificación OpenAPI
+func setupConsultationRouter() http.Handler {
 	r := mux.NewRouter()
 	r.HandleFunc("/consultations", GetConsultationsHandler).Methods("GET")
 	r.HandleFunc("/consultations/{id}", GetConsultationHandler).Methods("GET")
 	r.HandleFunc("/consultations", CreateConsultationHandler).Methods("POST")
 	r.HandleFunc("/consultations/{id}", UpdateConsultationHandler).Methods("PUT")
 	r.HandleFunc("/consultations/{id}", DeleteConsultationHandler).Methods("DELETE")
-	return r
+	return middleware.ValidationWithResponses(OpenAPI, true, r)
 }
 
 // Conecta a la base de datos y realiza las migraciones necesarias para Consultations
 func setupConsultationDB() {
 	db.DBConnection() // Conectar a la base de datos
-	db.DB.AutoMigrate(&models.User{}, &models.Consultation{}) // Migrar los modelos
+	db.DB.AutoMigrate(&models.User{}, &models.Consultation{}, &models.ConsultationMessage{}, &models.AuditLog{}, &models.DomainEvent{}) // Migrar los modelos
 }
 
 // Limpia las tablas de la base de datos para evitar errores de clave duplicada y restricciones de clave externa
 func cleanUpConsultationDB() {
+	db.DB.Unscoped().Exec("DELETE FROM consultation_messages")
 	db.DB.Unscoped().Exec("DELETE FROM consultations")
 	db.DB.Unscoped().Exec("DELETE FROM users")
 }
@@ -258,8 +260,42 @@ func TestDeleteConsultationHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
-	// Verificar que el registro no existe
+	// Verificar que el registro quedó eliminado lógicamente
 	var deletedConsultation models.Consultation
-	err = db.DB.Unscoped().First(&deletedConsultation, consultation.ID).Error
+	err = db.DB.First(&deletedConsultation, consultation.ID).Error
 	assert.Error(t, err)
+	err = db.DB.Unscoped().First(&deletedConsultation, consultation.ID).Error
+	assert.NoError(t, err)
+	assert.True(t, deletedConsultation.DeletedAt.Valid)
+}
+
+func TestCreateConsultationIgnoresConversion(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	setupConsultationDB()
+	defer cleanUpConsultationDB()
+
+	user := models.User{FirstName: "Alice", LastName: "Johnson", Email: "alice.johnson@example.com"}
+	db.DB.Create(&user)
+
+	// Una consulta nueva no puede llegar ya convertida ni atribuida a un empleado
+	payload, _ := json.Marshal(map[string]interface{}{
+		"consultation":    "¿Tienen habitaciones para el fin de semana?",
+		"user_id":         user.ID,
+		"reservation_id":  99,
+		"converted_at":    "2024-01-01T00:00:00Z",
+		"converted_by_id": 7,
+	})
+	req, _ := http.NewRequest("POST", "/consultations", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	setupConsultationRouter().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var created models.Consultation
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Nil(t, created.ReservationID)
+	assert.Nil(t, created.ConvertedAt)
+	assert.Nil(t, created.ConvertedByID)
 }
@@ -0,0 +1,237 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// findConsultation busca la consulta indicada en la URL y responde 404 si no existe
+func findConsultation(w http.ResponseWriter, r *http.Request) (models.Consultation, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var consultation models.Consultation
+	if err := db.DB.First(&consultation, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Consultation not found"))
+			return consultation, false
+		}
+		http.Error(w, "Failed to retrieve consultation", http.StatusInternalServerError)
+		return consultation, false
+	}
+	return consultation, true
+}
+
+// saveConsultation guarda los cambios de estado de la consulta junto con su registro de auditoría
+func saveConsultation(tx *gorm.DB, r *http.Request, before, consultation *models.Consultation) error {
+	if err := tx.Omit("Messages").Save(consultation).Error; err != nil {
+		return err
+	}
+	return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceConsultation, consultation.ID, before, consultation)
+}
+
+// GetConsultationMessagesHandler devuelve los mensajes del hilo de una consulta en orden cronológico
+func GetConsultationMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	consultation, ok := findConsultation(w, r)
+	if !ok {
+		return
+	}
+
+	var messages []models.ConsultationMessage
+	if err := db.DB.Where("consultation_id = ?", consultation.ID).Order("id asc").Find(&messages).Error; err != nil {
+		http.Error(w, "Failed to retrieve consultation messages", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&messages); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// consultationMessageRequest es el cuerpo de un mensaje nuevo en el hilo de una consulta
+type consultationMessageRequest struct {
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	EmployeeID *uint  `json:"employee_id"`
+}
+
+// PostConsultationMessageHandler agrega un mensaje al hilo. El personal autenticado responde como
+// "staff"; el resto de las solicitudes son seguimientos del huésped
+func PostConsultationMessageHandler(w http.ResponseWriter, r *http.Request) {
+	consultation, ok := findConsultation(w, r)
+	if !ok {
+		return
+	}
+
+	var body consultationMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Body == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	isStaff := middleware.HasRole(r, middleware.RoleStaff)
+	if body.Author == "" {
+		body.Author = models.MessageFromGuest
+		if isStaff {
+			body.Author = models.MessageFromStaff
+		}
+	}
+	switch {
+	case body.Author != models.MessageFromGuest && body.Author != models.MessageFromStaff:
+		http.Error(w, "Invalid message author", http.StatusBadRequest)
+		return
+	case body.Author == models.MessageFromStaff && !isStaff:
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	// Solo las respuestas del personal llevan empleado; por defecto el asignado a la consulta
+	if body.Author == models.MessageFromGuest {
+		body.EmployeeID = nil
+	} else if body.EmployeeID == nil {
+		body.EmployeeID = consultation.AssignedEmployeeID
+	}
+
+	message := models.ConsultationMessage{
+		ConsultationID: consultation.ID,
+		Author:         body.Author,
+		EmployeeID:     body.EmployeeID,
+		Body:           body.Body,
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmployee(tx, message.EmployeeID); err != nil {
+			return err
+		}
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceConsultationMessage, message.ID, nil, &message); err != nil {
+			return err
+		}
+		before := consultation
+		consultation.ApplyMessage(message.Author, message.CreatedAt, consultationSLA())
+		if err := saveConsultation(tx, r, &before, &consultation); err != nil {
+			return err
+		}
+		if message.Author == models.MessageFromStaff {
+			return events.PublishConsultation(tx, events.ConsultationAnswered, consultation, message.Body)
+		}
+		return nil
+	})
+	if errors.Is(err, errEmployeeNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to post consultation message", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&message); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// consultationAssignment indica el empleado al que se asigna una consulta
+type consultationAssignment struct {
+	EmployeeID *uint `json:"employee_id"`
+}
+
+// AssignConsultationHandler asigna la consulta a un empleado, o la deja sin asignar con employee_id null
+func AssignConsultationHandler(w http.ResponseWriter, r *http.Request) {
+	consultation, ok := findConsultation(w, r)
+	if !ok {
+		return
+	}
+
+	var body consultationAssignment
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmployee(tx, body.EmployeeID); err != nil {
+			return err
+		}
+		before := consultation
+		consultation.AssignedEmployeeID = body.EmployeeID
+		if err := saveConsultation(tx, r, &before, &consultation); err != nil {
+			return err
+		}
+		return events.PublishConsultation(tx, events.ConsultationUpdated, consultation, "")
+	})
+	if errors.Is(err, errEmployeeNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to assign consultation", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&consultation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// consultationStatusUpdate es el cuerpo del cambio de estado de una consulta
+type consultationStatusUpdate struct {
+	Status string `json:"status"`
+}
+
+// UpdateConsultationStatusHandler cambia el estado de la consulta. Al reabrirla vuelve a correr el plazo de respuesta
+func UpdateConsultationStatusHandler(w http.ResponseWriter, r *http.Request) {
+	consultation, ok := findConsultation(w, r)
+	if !ok {
+		return
+	}
+
+	var body consultationStatusUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	before := consultation
+	switch body.Status {
+	case models.ConsultationOpen:
+		if consultation.DueAt == nil || consultation.Status != models.ConsultationOpen {
+			due := now.Add(consultationSLA())
+			consultation.DueAt = &due
+		}
+		consultation.ClosedAt = nil
+	case models.ConsultationPending, models.ConsultationAnswered:
+		consultation.ClosedAt = nil
+	case models.ConsultationClosed:
+		consultation.ClosedAt = &now
+		consultation.DueAt = nil
+	default:
+		http.Error(w, "Invalid consultation status", http.StatusBadRequest)
+		return
+	}
+	consultation.Status = body.Status
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveConsultation(tx, r, &before, &consultation); err != nil {
+			return err
+		}
+		return events.PublishConsultation(tx, events.ConsultationUpdated, consultation, "")
+	})
+	if err != nil {
+		http.Error(w, "Failed to update consultation", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&consultation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
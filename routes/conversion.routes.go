@@ -0,0 +1,218 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAlreadyConverted se devuelve al convertir una consulta que ya tiene reserva
+var errAlreadyConverted = errors.New("consultation already converted into a reservation")
+
+// conversionRequest son los datos de la reserva que se crea a partir de una consulta
+type conversionRequest struct {
+	Checkin       time.Time `json:"check_in"`
+	Checkout      time.Time `json:"check_out"`
+	Adults        int       `json:"adults"`
+	Children      int       `json:"children"`
+	NumberOfRooms int       `json:"number_of_rooms"`
+	RoomType      string    `json:"room_type"`
+	RatePlanID    *uint     `json:"rate_plan_id"`
+	Email         string    `json:"email"`
+	EmployeeID    *uint     `json:"employee_id"`
+}
+
+// conversionResult es la consulta convertida junto con la reserva creada
+type conversionResult struct {
+	Consultation models.Consultation `json:"consultation"`
+	Reservation  models.Reservation  `json:"reservation"`
+}
+
+// ConvertConsultationHandler crea una reserva provisional para el usuario de la consulta con las fechas
+// y ocupación que indica el personal, y enlaza la consulta con la reserva
+func ConvertConsultationHandler(w http.ResponseWriter, r *http.Request) {
+	consultation, ok := findConsultation(w, r)
+	if !ok {
+		return
+	}
+
+	var body conversionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.Checkout.After(body.Checkin) || body.Adults < 1 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if body.NumberOfRooms < 1 {
+		body.NumberOfRooms = 1
+	}
+	// La conversión se atribuye al empleado asignado si no se indica otro
+	if body.EmployeeID == nil {
+		body.EmployeeID = consultation.AssignedEmployeeID
+	}
+
+	var reservation models.Reservation
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloquear la consulta para que no se convierta dos veces
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&consultation, consultation.ID).Error; err != nil {
+			return err
+		}
+		if consultation.ReservationID != nil {
+			return errAlreadyConverted
+		}
+		if err := checkEmployee(tx, body.EmployeeID); err != nil {
+			return err
+		}
+
+		// Sin email explícito se usa el del usuario que hizo la consulta
+		email := body.Email
+		if email == "" {
+			var user models.User
+			if err := tx.First(&user, consultation.UserID).Error; err != nil {
+				return err
+			}
+			email = user.Email
+		}
+
+		if err := inventory.Reserve(tx, body.RoomType, body.Checkin, body.Checkout, body.NumberOfRooms); err != nil {
+			return err
+		}
+		reservation = models.Reservation{
+			Adults:        body.Adults,
+			Checkin:       body.Checkin,
+			Checkout:      body.Checkout,
+			Children:      body.Children,
+			Email:         email,
+			NumberOfRooms: body.NumberOfRooms,
+			RoomType:      body.RoomType,
+			UserID:        consultation.UserID,
+			RatePlanID:    body.RatePlanID,
+			Status:        models.ReservationTentative,
+		}
+		if err := tx.Create(&reservation).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceReservation, reservation.ID, nil, &reservation); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		before := consultation
+		consultation.ReservationID = &reservation.ID
+		consultation.ConvertedAt = &now
+		consultation.ConvertedByID = body.EmployeeID
+		return saveConsultation(tx, r, &before, &consultation)
+	})
+	switch {
+	case errors.Is(err, errAlreadyConverted), errors.Is(err, inventory.ErrNoAvailability):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, errEmployeeNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Consultation user or employee not found", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, "Failed to convert consultation", http.StatusInternalServerError)
+		return
+	}
+
+	response := conversionResult{consultation, reservation}
+	if err := json.NewEncoder(w).Encode(&response); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// conversionStats es la tasa de conversión de consultas en reservas de un empleado. Las consultas
+// sin empleado asignado se informan con employee_id null
+type conversionStats struct {
+	EmployeeID    *uint   `json:"employee_id"`
+	Consultations int     `json:"consultations"`
+	Converted     int     `json:"converted"`
+	Rate          float64 `json:"rate"`
+}
+
+// GetConversionStatsHandler informa la tasa de conversión por empleado de las consultas recibidas entre
+// from y to (opcionales, AAAA-MM-DD o RFC 3339)
+func GetConversionStatsHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Model(&models.Consultation{}).
+		Select("COALESCE(converted_by_id, assigned_employee_id) AS employee_id, COUNT(*) AS consultations, COUNT(reservation_id) AS converted")
+	query := r.URL.Query()
+	for param, condition := range map[string]string{"from": "created_at >= ?", "to": "created_at < ?"} {
+		if value := query.Get(param); value != "" {
+			t, err := parseDate(value)
+			if err != nil {
+				http.Error(w, "Invalid "+param+" date", http.StatusBadRequest)
+				return
+			}
+			conn = conn.Where(condition, t)
+		}
+	}
+
+	var stats []conversionStats
+	if err := conn.Group("1").Order("1").Scan(&stats).Error; err != nil {
+		http.Error(w, "Failed to compute conversion stats", http.StatusInternalServerError)
+		return
+	}
+	for i := range stats {
+		if stats[i].Consultations > 0 {
+			stats[i].Rate = float64(stats[i].Converted) / float64(stats[i].Consultations)
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(&stats); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// ConfirmReservationHandler confirma una reserva provisional
+func ConfirmReservationHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	reservation, err := confirmReservation(r, reservation)
+	if errors.Is(err, errNotTentative) {
+		http.Error(w, "Only tentative reservations can be confirmed", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeSaveError(w, err, "Failed to confirm reservation")
+		return
+	}
+
+	setETag(w, reservation.Version)
+	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// errNotTentative indica que la reserva no está pendiente de confirmar
+var errNotTentative = errors.New("only tentative reservations can be confirmed")
+
+// confirmReservation confirma una reserva tentativa y publica el evento. La comparten el handler REST
+// y los servicios GraphQL y gRPC
+func confirmReservation(r *http.Request, reservation models.Reservation) (models.Reservation, error) {
+	if reservation.Status != models.ReservationTentative {
+		return reservation, errNotTentative
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		before := reservation
+		reservation.Status = models.ReservationConfirmed
+		if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+			return err
+		}
+		return events.PublishReservation(tx, events.ReservationConfirmed, reservation)
+	})
+	return reservation, err
+}
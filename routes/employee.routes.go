@@ -2,25 +2,97 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/mergepatch"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
+// employeeID devuelve el ID del empleado, que proviene del usuario embebido
+func employeeID(employee models.Employee) uint {
+	if employee.User == nil {
+		return 0
+	}
+	return employee.User.ID
+}
+
+// errSalaryRequiresManager indica que un rol inferior a manager intentó fijar el salario
+var errSalaryRequiresManager = errors.New("only managers can set the salary")
+
+// employeeFields son los campos de un empleado que el cliente puede modificar. Sin salary se conserva
+// el salario actual
+type employeeFields struct {
+	Position    string   `json:"position"`
+	Salary      *float64 `json:"salary"`
+	Department  string   `json:"department"`
+	HireDate    string   `json:"hire_date"`
+	PhoneNumber string   `json:"phone_number"`
+	User        struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+	} `json:"user"`
+}
+
+// employeeWithoutSalary es el empleado tal como lo ven los roles inferiores a manager: el campo salary
+// oculta el del empleado y se omite
+type employeeWithoutSalary struct {
+	*models.Employee
+	Salary *float64 `json:"salary,omitempty"`
+}
+
+// employeeJSON devuelve el empleado a codificar en la respuesta. El salario solo se envía al rol
+// manager, como en GraphQL, gRPC y las exportaciones
+func employeeJSON(r *http.Request, employee *models.Employee) interface{} {
+	if middleware.HasRole(r, middleware.RoleManager) {
+		return employee
+	}
+	return employeeWithoutSalary{Employee: employee}
+}
+
+// writeEmployeeSaveError responde con 403 si el salario requiere el rol manager y, si no, como writeSaveError
+func writeEmployeeSaveError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, errSalaryRequiresManager) {
+		http.Error(w, "Only managers can set the salary", http.StatusForbidden)
+		return
+	}
+	writeSaveError(w, err, message)
+}
+
+// employeeVersion devuelve la versión del empleado, que se guarda en el usuario embebido
+func employeeVersion(employee *models.Employee) *uint {
+	if employee.User == nil {
+		employee.User = &models.User{}
+	}
+	return &employee.User.Version
+}
+
 // GetEmployeesHandler obtiene todos los empleados desde la base de datos en orden ascendente por ID y los devuelve en formato JSON
 func GetEmployeesHandler(w http.ResponseWriter, r *http.Request) {
 	var employees []models.Employee
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
 	// Buscar todos los empleados en la base de datos y ordenarlos por ID en orden ascendente
-	if err := db.DB.Order("id asc").Find(&employees).Error; err != nil {
+	if err := conn.Order("id asc").Find(&employees).Error; err != nil {
 		// Manejar el error si ocurre al buscar los empleados
 		http.Error(w, "Failed to retrieve employees", http.StatusInternalServerError)
 		return
 	}
 
-	// Codificar los empleados en formato JSON y enviarlos como respuesta
-	if err := json.NewEncoder(w).Encode(&employees); err != nil {
+	// Codificar los empleados en formato JSON y enviarlos como respuesta, sin el salario para los roles inferiores a manager
+	response := make([]interface{}, len(employees))
+	for i := range employees {
+		response[i] = employeeJSON(r, &employees[i])
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		// Manejar el error si ocurre al codificar el JSON
 		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
 	}
@@ -29,9 +101,13 @@ func GetEmployeesHandler(w http.ResponseWriter, r *http.Request) {
 func GetEmployeeHandler(w http.ResponseWriter, r *http.Request) {
     params := mux.Vars(r)
     var employee models.Employee
+    conn, ok := readDB(w, r)
+    if !ok {
+        return
+    }
 
     // Buscar un empleado específico por ID e incluir reservas y consultas asociadas
-    if err := db.DB.Preload("User.Reservations").Preload("User.Consultations").Preload("Reservations").Preload("Consultations").First(&employee, params["id"]).Error; err != nil {
+    if err := conn.Preload("User.Reservations").Preload("User.Consultations").Preload("Reservations").Preload("Consultations").First(&employee, params["id"]).Error; err != nil {
         if err.Error() == "record not found" {
             w.WriteHeader(http.StatusNotFound)
             w.Write([]byte("Employee not found"))
@@ -41,7 +117,8 @@ func GetEmployeeHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    if err := json.NewEncoder(w).Encode(&employee); err != nil {
+    setETag(w, *employeeVersion(&employee))
+    if err := json.NewEncoder(w).Encode(employeeJSON(r, &employee)); err != nil {
         http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
     }
 }
@@ -55,13 +132,16 @@ func PostEmployeeHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    createdEmployee := db.DB.Create(&employee)
-    if err := createdEmployee.Error; err != nil {
+    if err := createEmployee(r, &employee); err != nil {
+        if errors.Is(err, errSalaryRequiresManager) {
+            http.Error(w, "Only managers can set the salary", http.StatusForbidden)
+            return
+        }
         http.Error(w, err.Error(), http.StatusBadRequest)
         return
     }
 
-    if err := json.NewEncoder(w).Encode(&employee); err != nil {
+    if err := json.NewEncoder(w).Encode(employeeJSON(r, &employee)); err != nil {
         http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
     }
 }
@@ -81,39 +161,25 @@ func UpdateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    var updatedEmployee struct {
-        Position    string  `json:"position"`
-        Salary      float64 `json:"salary"`
-        Department  string  `json:"department"`
-        HireDate    string  `json:"hire_date"`
-        PhoneNumber string  `json:"phone_number"`
-        User        struct {
-            FirstName string `json:"first_name"`
-            LastName  string `json:"last_name"`
-            Email     string `json:"email"`
-        } `json:"user"`
+    // Rechazar la escritura si el cliente editó una versión anterior
+    if !checkIfMatch(w, r, *employeeVersion(&employee)) {
+        return
     }
 
+    var updatedEmployee employeeFields
+
     if err := json.NewDecoder(r.Body).Decode(&updatedEmployee); err != nil {
         http.Error(w, "Invalid request payload", http.StatusBadRequest)
         return
     }
 
-    employee.Position = updatedEmployee.Position
-    employee.Salary = updatedEmployee.Salary
-    employee.Department = updatedEmployee.Department
-    employee.HireDate = updatedEmployee.HireDate
-    employee.PhoneNumber = updatedEmployee.PhoneNumber
-    employee.User.FirstName = updatedEmployee.User.FirstName
-    employee.User.LastName = updatedEmployee.User.LastName
-    employee.User.Email = updatedEmployee.User.Email
-
-    if err := db.DB.Save(&employee).Error; err != nil {
-        http.Error(w, "Failed to update employee", http.StatusInternalServerError)
+    if err := updateEmployee(r, &employee, updatedEmployee); err != nil {
+        writeEmployeeSaveError(w, err, "Failed to update employee")
         return
     }
 
-    if err := json.NewEncoder(w).Encode(&employee); err != nil {
+    setETag(w, *employeeVersion(&employee))
+    if err := json.NewEncoder(w).Encode(employeeJSON(r, &employee)); err != nil {
         http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
     }
 }
@@ -121,6 +187,103 @@ func UpdateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
 
 
 
+// PatchEmployeeHandler modifica parcialmente un empleado aplicando un JSON Merge Patch:
+// los campos omitidos se conservan
+func PatchEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var employee models.Employee
+	if err := db.DB.First(&employee, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Employee not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve employee", http.StatusInternalServerError)
+		return
+	}
+	if !checkIfMatch(w, r, *employeeVersion(&employee)) {
+		return
+	}
+	patch, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	// Aplicar el parche sobre los campos editables del empleado. El salario solo cambia si el parche lo incluye
+	fields := employeeFields{
+		Position:    employee.Position,
+		Department:  employee.Department,
+		HireDate:    employee.HireDate,
+		PhoneNumber: employee.PhoneNumber,
+	}
+	fields.User.FirstName = employee.User.FirstName
+	fields.User.LastName = employee.User.LastName
+	fields.User.Email = employee.User.Email
+	if err := mergepatch.ApplyTo(&fields, patch); err != nil {
+		http.Error(w, "Invalid merge patch: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := updateEmployee(r, &employee, fields); err != nil {
+		writeEmployeeSaveError(w, err, "Failed to update employee")
+		return
+	}
+
+	setETag(w, *employeeVersion(&employee))
+	if err := json.NewEncoder(w).Encode(employeeJSON(r, &employee)); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// createEmployee da de alta el empleado y lo registra en la auditoría. La comparten el handler REST y
+// el servicio gRPC. Solo el rol manager puede fijar el salario
+func createEmployee(r *http.Request, employee *models.Employee) error {
+	if employee.Salary != 0 && !middleware.HasRole(r, middleware.RoleManager) {
+		return errSalaryRequiresManager
+	}
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(employee).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceEmployee, employeeID(*employee), nil, employee)
+	})
+}
+
+// updateEmployee guarda los campos editables del empleado si su versión no cambió y registra la
+// modificación en la auditoría. La comparten PUT, PATCH y el servicio gRPC. Solo el rol manager puede
+// enviar el salario, aunque sea el mismo, para no revelar el actual a quien no puede verlo
+func updateEmployee(r *http.Request, employee *models.Employee, fields employeeFields) error {
+	if fields.Salary != nil && !middleware.HasRole(r, middleware.RoleManager) {
+		return errSalaryRequiresManager
+	}
+
+	// Copiar el estado anterior, incluido el usuario embebido, para la auditoría
+	before := *employee
+	if employee.User != nil {
+		beforeUser := *employee.User
+		before.User = &beforeUser
+	}
+
+	employee.Position = fields.Position
+	if fields.Salary != nil {
+		employee.Salary = *fields.Salary
+	}
+	employee.Department = fields.Department
+	employee.HireDate = fields.HireDate
+	employee.PhoneNumber = fields.PhoneNumber
+	version := employeeVersion(employee)
+	employee.User.FirstName = fields.User.FirstName
+	employee.User.LastName = fields.User.LastName
+	employee.User.Email = fields.User.Email
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, employee, version); err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceEmployee, employeeID(*employee), &before, employee)
+	})
+}
+
 // DeleteEmployeeHandler elimina un empleado específico por ID y sus reservas y consultas asociadas
 func DeleteEmployeeHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -140,24 +303,10 @@ func DeleteEmployeeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Eliminar las reservas asociadas al empleado
-	if err := db.DB.Where("employee_id = ?", employee.User.ID).Delete(&models.Reservation{}).Error; err != nil {
-		// Manejar el error si ocurre al eliminar las reservas
-		http.Error(w, "Failed to delete reservations", http.StatusInternalServerError)
-		return
-	}
-
-	// Eliminar las consultas asociadas al empleado
-	if err := db.DB.Where("employee_id = ?", employee.User.ID).Delete(&models.Consultation{}).Error; err != nil {
-		// Manejar el error si ocurre al eliminar las consultas
-		http.Error(w, "Failed to delete consultations", http.StatusInternalServerError)
-		return
-	}
-
-	// Eliminar el empleado de la base de datos
-	if err := db.DB.Unscoped().Delete(&employee).Error; err != nil {
+	// Eliminar el empleado junto con sus reservas y consultas asociadas
+	if err := deleteEmployee(r, employee); err != nil {
 		// Manejar el error si ocurre al eliminar el empleado
-		http.Error(w, "Failed to delete employee", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -165,4 +314,29 @@ func DeleteEmployeeHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// deleteEmployee elimina las reservas y consultas asociadas al empleado y luego, lógicamente, el empleado.
+// Los errores llevan el mensaje que se devuelve al cliente
+func deleteEmployee(r *http.Request, employee models.Employee) error {
+	// Eliminar las reservas asociadas al empleado
+	if err := db.DB.Where("employee_id = ?", employeeID(employee)).Delete(&models.Reservation{}).Error; err != nil {
+		return errors.New("Failed to delete reservations")
+	}
+
+	// Eliminar las consultas asociadas al empleado
+	if err := db.DB.Where("employee_id = ?", employeeID(employee)).Delete(&models.Consultation{}).Error; err != nil {
+		return errors.New("Failed to delete consultations")
+	}
+
+	// Eliminar lógicamente el empleado: puede recuperarse hasta que se purgue
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&employee).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceEmployee, employeeID(employee), &employee, nil)
+	})
+	if err != nil {
+		return errors.New("Failed to delete employee")
+	}
+	return nil
+}
 
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// withPrincipal ejecuta fn con una solicitud autenticada con el token indicado
+func withPrincipal(token string, fn func(r *http.Request)) {
+	handler := middleware.AuthWithTokens(middleware.ParseTokens("staff-token:staff:bob,manager-token:manager:alice"),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fn(r) }))
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestEmployeeSalaryRequiresManager(t *testing.T) {
+	employee := models.Employee{User: &models.User{FirstName: "Ana"}, Position: "Recepción", Salary: 1500}
+	encode := func(token string) map[string]interface{} {
+		var body bytes.Buffer
+		withPrincipal(token, func(r *http.Request) {
+			assert.NoError(t, json.NewEncoder(&body).Encode(employeeJSON(r, &employee)))
+		})
+		var result map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body.Bytes(), &result))
+		return result
+	}
+
+	staff := encode("staff-token")
+	assert.NotContains(t, staff, "salary")
+	assert.Equal(t, "Recepción", staff["position"])
+	assert.Equal(t, 1500.0, encode("manager-token")["salary"])
+
+	// Los roles inferiores a manager no pueden fijar el salario, ni siquiera repitiendo el actual
+	salary := 1500.0
+	withPrincipal("staff-token", func(r *http.Request) {
+		assert.ErrorIs(t, createEmployee(r, &models.Employee{User: &models.User{}, Salary: 2000}), errSalaryRequiresManager)
+		assert.ErrorIs(t, updateEmployee(r, &employee, employeeFields{Salary: &salary}), errSalaryRequiresManager)
+	})
+	assert.Equal(t, 1500.0, employee.Salary)
+
+	rr := httptest.NewRecorder()
+	writeEmployeeSaveError(rr, errSalaryRequiresManager, "Failed to update employee")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, "Only managers can set the salary\n", rr.Body.String())
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// GetEventsHandler obtiene los eventos de dominio, los más recientes primero, con filtros opcionales
+// por type, status, aggregate_type y aggregate_id
+func GetEventsHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Order("id desc").Limit(100)
+	query := r.URL.Query()
+	for _, param := range []string{"type", "status", "aggregate_type", "aggregate_id"} {
+		if value := query.Get(param); value != "" {
+			conn = conn.Where(param+" = ?", value)
+		}
+	}
+
+	var domainEvents []models.DomainEvent
+	if err := conn.Find(&domainEvents).Error; err != nil {
+		http.Error(w, "Failed to retrieve events", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&domainEvents); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// RetryEventHandler vuelve a poner en cola un evento fallido. Solo se reintentan los suscriptores
+// que todavía no lo procesaron
+func RetryEventHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var event models.DomainEvent
+	if err := db.DB.First(&event, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Event not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve event", http.StatusInternalServerError)
+		return
+	}
+	if event.Status != models.EventFailed {
+		http.Error(w, "Only failed events can be retried", http.StatusConflict)
+		return
+	}
+
+	before := event
+	event.Status = models.EventPending
+	event.Attempts = 0
+	event.NextAttemptAt = time.Now()
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&event).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceDomainEvent, event.ID, &before, &event)
+	})
+	if err != nil {
+		http.Error(w, "Failed to retry event", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&event); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,201 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/export"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize es la cantidad de registros que se leen por consulta al exportar
+const exportBatchSize = 500
+
+// startExport valida el formato pedido en ?format= (csv por defecto) y comienza la respuesta con el
+// archivo <name>-<fecha>.<formato>. Con include_deleted=true se agrega la columna deleted_at
+func startExport(w http.ResponseWriter, r *http.Request, name string, header []string) (export.Writer, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.CSV
+	}
+	contentType, err := export.ContentType(format)
+	if err != nil {
+		http.Error(w, "Invalid export format", http.StatusBadRequest)
+		return nil, false
+	}
+	if exportDeleted(r) {
+		header = append(header, "deleted_at")
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"-"+time.Now().Format("2006-01-02")+"."+format))
+	writer, err := export.NewWriter(format, w, name, header)
+	if err != nil {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "Failed to export "+name, http.StatusInternalServerError)
+		return nil, false
+	}
+	return writer, true
+}
+
+// exportDeleted indica si la exportación incluye los registros eliminados. readDB ya comprobó el rol
+func exportDeleted(r *http.Request) bool {
+	return r.URL.Query().Get("include_deleted") == "true"
+}
+
+// withDeleted agrega a la fila la fecha de eliminación si la exportación incluye los registros eliminados
+func withDeleted(r *http.Request, row []interface{}, deletedAt gorm.DeletedAt) []interface{} {
+	if !exportDeleted(r) {
+		return row
+	}
+	if !deletedAt.Valid {
+		return append(row, nil)
+	}
+	return append(row, deletedAt.Time)
+}
+
+// finishExport cierra el archivo. Si falló la lectura o la escritura la respuesta ya comenzó, así que
+// se corta la conexión para que el cliente no reciba un archivo incompleto como si fuera válido
+func finishExport(writer export.Writer, name string, err error) {
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		log.Printf("export of %s failed: %v", name, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// ExportReservationsHandler exporta las reservas en CSV o xlsx, leyéndolas por lotes
+func ExportReservationsHandler(w http.ResponseWriter, r *http.Request) {
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	writer, ok := startExport(w, r, "reservations", []string{
+		"id", "created_at", "status", "check_in", "check_out", "adults", "children", "number_of_rooms", "room_type",
+		"guest_name", "email", "user_id", "rate_plan_id", "group_block_id", "cancelled_at", "checked_in_at",
+		"checked_out_at", "cancellation_fee",
+	})
+	if !ok {
+		return
+	}
+
+	var batch []models.Reservation
+	err := conn.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, reservation := range batch {
+			row := []interface{}{
+				reservation.ID, reservation.CreatedAt, reservation.Status, reservation.Checkin, reservation.Checkout,
+				reservation.Adults, reservation.Children, reservation.NumberOfRooms, reservation.RoomType,
+				reservation.GuestName, reservation.Email, reservation.UserID, reservation.RatePlanID,
+				reservation.GroupBlockID, reservation.CancelledAt, reservation.CheckedInAt, reservation.CheckedOutAt,
+				reservation.CancellationFee,
+			}
+			if err := writer.Write(withDeleted(r, row, reservation.DeletedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	finishExport(writer, "reservations", err)
+}
+
+// ExportUsersHandler exporta los usuarios en CSV o xlsx, leyéndolos por lotes
+func ExportUsersHandler(w http.ResponseWriter, r *http.Request) {
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	writer, ok := startExport(w, r, "users", []string{"id", "created_at", "first_name", "last_name", "email", "locale"})
+	if !ok {
+		return
+	}
+
+	var batch []models.User
+	err := conn.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, user := range batch {
+			row := []interface{}{user.ID, user.CreatedAt, user.FirstName, user.LastName, user.Email, user.Locale}
+			if err := writer.Write(withDeleted(r, row, user.DeletedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	finishExport(writer, "users", err)
+}
+
+// ExportConsultationsHandler exporta las consultas en CSV o xlsx con los mismos filtros que GET /consultations
+func ExportConsultationsHandler(w http.ResponseWriter, r *http.Request) {
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	conn = filterConsultations(conn, r.URL.Query())
+	writer, ok := startExport(w, r, "consultations", []string{
+		"id", "created_at", "status", "user_id", "phone", "consultation", "more_info", "assigned_employee_id",
+		"due_at", "answered_at", "closed_at", "reservation_id", "converted_at", "converted_by_id",
+	})
+	if !ok {
+		return
+	}
+
+	var batch []models.Consultation
+	err := conn.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, consultation := range batch {
+			row := []interface{}{
+				consultation.ID, consultation.CreatedAt, consultation.Status, consultation.UserID, consultation.Phone,
+				consultation.Consultation, consultation.MoreInfo, consultation.AssignedEmployeeID, consultation.DueAt,
+				consultation.AnsweredAt, consultation.ClosedAt, consultation.ReservationID, consultation.ConvertedAt,
+				consultation.ConvertedByID,
+			}
+			if err := writer.Write(withDeleted(r, row, consultation.DeletedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	finishExport(writer, "consultations", err)
+}
+
+// ExportEmployeesHandler exporta los empleados en CSV o xlsx. El salario solo se incluye para el rol manager
+func ExportEmployeesHandler(w http.ResponseWriter, r *http.Request) {
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
+	salaries := middleware.HasRole(r, middleware.RoleManager)
+	header := []string{"id", "created_at", "first_name", "last_name", "email", "position", "department", "hire_date", "phone_number"}
+	if salaries {
+		header = append(header, "salary")
+	}
+	writer, ok := startExport(w, r, "employees", header)
+	if !ok {
+		return
+	}
+
+	var batch []models.Employee
+	err := conn.FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, employee := range batch {
+			user := employee.User
+			if user == nil {
+				user = &models.User{}
+			}
+			row := []interface{}{
+				user.ID, user.CreatedAt, user.FirstName, user.LastName, user.Email, employee.Position,
+				employee.Department, employee.HireDate, employee.PhoneNumber,
+			}
+			if salaries {
+				row = append(row, employee.Salary)
+			}
+			if err := writer.Write(withDeleted(r, row, user.DeletedAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	finishExport(writer, "employees", err)
+}
@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupExportRouter() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/exports/users", middleware.RequireRole(middleware.RoleStaff, ExportUsersHandler)).Methods("GET")
+	r.HandleFunc("/exports/employees", middleware.RequireRole(middleware.RoleStaff, ExportEmployeesHandler)).Methods("GET")
+	tokens := middleware.ParseTokens("staff-token:staff:bob,manager-token:manager:alice")
+	return middleware.AuthWithTokens(tokens, r)
+}
+
+func exportRequest(router http.Handler, url, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", url, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestExportInvalidRequests(t *testing.T) {
+	router := setupExportRouter()
+
+	rr := exportRequest(router, "/exports/users", "")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = exportRequest(router, "/exports/users?format=pdf", "staff-token")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid export format\n", rr.Body.String())
+
+	// Los eliminados se exportan solo con rol admin, como en los listados
+	rr = exportRequest(router, "/exports/users?include_deleted=true", "manager-token")
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestExportEmployees(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.Employee{})
+	defer db.DB.Unscoped().Exec("DELETE FROM employees")
+
+	employee := models.Employee{
+		User:       &models.User{FirstName: "Ada", LastName: "Lovelace", Email: "ada.lovelace@example.com"},
+		Position:   "Recepcionista",
+		Salary:     1500,
+		Department: "Recepción",
+		HireDate:   "2024-01-15",
+	}
+	assert.NoError(t, db.DB.Create(&employee).Error)
+	router := setupExportRouter()
+
+	// El personal recibe la planilla sin los salarios
+	rr := exportRequest(router, "/exports/employees", "staff-token")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), `attachment; filename="employees-`)
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.NotContains(t, records[0], "salary")
+		assert.Equal(t, "Ada", records[1][2])
+	}
+
+	// Un manager también recibe el salario
+	rr = exportRequest(router, "/exports/employees", "manager-token")
+	records, err = csv.NewReader(rr.Body).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "salary", records[0][len(records[0])-1])
+		assert.Equal(t, "1500", records[1][len(records[1])-1])
+	}
+}
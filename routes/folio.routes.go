@@ -0,0 +1,120 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// folioResponse es la representación del folio con su saldo calculado
+type folioResponse struct {
+	models.Folio
+	Balance float64 `json:"balance"`
+}
+
+// findOrCreateFolio devuelve el folio de una reserva, creándolo si todavía no existe
+func findOrCreateFolio(tx *gorm.DB, reservationID uint) (models.Folio, error) {
+	folio := models.Folio{ReservationID: &reservationID}
+	err := tx.Where("reservation_id = ?", reservationID).FirstOrCreate(&folio).Error
+	return folio, err
+}
+
+// findOrCreateGroupFolio devuelve el folio maestro de un bloqueo de grupo, creándolo si todavía no existe
+func findOrCreateGroupFolio(tx *gorm.DB, groupBlockID uint) (models.Folio, error) {
+	folio := models.Folio{GroupBlockID: &groupBlockID}
+	err := tx.Where("group_block_id = ?", groupBlockID).FirstOrCreate(&folio).Error
+	return folio, err
+}
+
+// GetReservationFolioHandler devuelve el folio de una reserva con sus movimientos y saldo
+func GetReservationFolioHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	folio, err := findOrCreateFolio(db.DB, reservation.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve folio", http.StatusInternalServerError)
+		return
+	}
+	writeFolio(w, folio)
+}
+
+// PostFolioChargeHandler registra un cargo (alojamiento, consumos, etc.) en el folio de una reserva
+func PostFolioChargeHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+	postFolioCharge(w, r, func(tx *gorm.DB) (models.Folio, error) {
+		return findOrCreateFolio(tx, reservation.ID)
+	})
+}
+
+// writeFolio carga los movimientos del folio y lo devuelve con su saldo
+func writeFolio(w http.ResponseWriter, folio models.Folio) {
+	// Cargar los movimientos del folio en orden cronológico
+	if err := db.DB.Order("id asc").Where("folio_id = ?", folio.ID).Find(&folio.Entries).Error; err != nil {
+		http.Error(w, "Failed to retrieve folio entries", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(folioResponse{Folio: folio, Balance: folio.Balance()}); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// folioCharge es un cargo manual en un folio
+type folioCharge struct {
+	Description string   `json:"description"`
+	Amount      float64  `json:"amount"`
+	TaxRate     *float64 `json:"tax_rate"`
+}
+
+// postFolioCharge registra el cargo del cuerpo de la solicitud en el folio que devuelve findFolio
+func postFolioCharge(w http.ResponseWriter, r *http.Request, findFolio func(tx *gorm.DB) (models.Folio, error)) {
+	var charge folioCharge
+	if err := json.NewDecoder(r.Body).Decode(&charge); err != nil || charge.Amount <= 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Si no se indica la alícuota se aplica la configurada para el hotel
+	taxRate := invoices.LoadHotel().TaxRate
+	if charge.TaxRate != nil {
+		taxRate = *charge.TaxRate
+	}
+
+	var entry models.FolioEntry
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		folio, err := findFolio(tx)
+		if err != nil {
+			return err
+		}
+		entry = models.FolioEntry{
+			FolioID:     folio.ID,
+			Type:        models.FolioEntryCharge,
+			Description: charge.Description,
+			Amount:      charge.Amount,
+			TaxRate:     taxRate,
+		}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceFolioEntry, entry.ID, nil, &entry)
+	})
+	if err != nil {
+		http.Error(w, "Failed to post charge", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&entry); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
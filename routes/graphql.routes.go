@@ -0,0 +1,726 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/graph"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/graphql-go/graphql"
+)
+
+// maxGraphQLPage es el máximo del argumento limit de los listados GraphQL
+const maxGraphQLPage = graph.MaxListSize
+
+// graphQLOptions son los límites de profundidad y complejidad de las consultas GraphQL
+var graphQLOptions = graph.OptionsFromEnv()
+
+// graphQLSchema es el esquema GraphQL de usuarios, reservas, consultas, empleados y disponibilidad
+var graphQLSchema = newGraphQLSchema()
+
+// GraphQLHandler ejecuta una operación GraphQL. Por GET solo se admiten consultas, con los parámetros
+// query, operationName y variables (JSON); por POST también las mutaciones, con el cuerpo en JSON
+func GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var request graph.Request
+	options := graphQLOptions
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				http.Error(w, "Invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+		options.QueryOnly = true
+	} else if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		http.Error(w, "Missing GraphQL query", http.StatusBadRequest)
+		return
+	}
+
+	// Los errores de la operación viajan en el campo errors de la respuesta, con estado 200
+	ctx := context.WithValue(r.Context(), graphContextKey{}, newGraphContext(r))
+	result := graph.Execute(ctx, graphQLSchema, request, options)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+type graphContextKey struct{}
+
+// graphContext guarda la solicitud HTTP, para la auditoría de las mutaciones, y los loaders que
+// agrupan las cargas de las relaciones durante una operación
+type graphContext struct {
+	request                 *http.Request
+	users                   *graph.Loader
+	reservations            *graph.Loader
+	employees               *graph.Loader
+	reservationsByUser      *graph.Loader
+	consultationsByUser     *graph.Loader
+	consultationsByEmployee *graph.Loader
+	messagesByConsultation  *graph.Loader
+}
+
+func newGraphContext(r *http.Request) *graphContext {
+	return &graphContext{
+		request: r,
+		users: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var users []models.User
+			if err := db.DB.Where("id IN ?", ids).Find(&users).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]interface{}, len(users))
+			for _, user := range users {
+				result[user.ID] = user
+			}
+			return result, nil
+		}),
+		reservations: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var reservations []models.Reservation
+			if err := db.DB.Where("id IN ?", ids).Find(&reservations).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]interface{}, len(reservations))
+			for _, reservation := range reservations {
+				result[reservation.ID] = reservation
+			}
+			return result, nil
+		}),
+		employees: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var employees []models.Employee
+			if err := db.DB.Where("id IN ?", ids).Find(&employees).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]interface{}, len(employees))
+			for _, employee := range employees {
+				if employee.User != nil {
+					result[employee.User.ID] = employee
+				}
+			}
+			return result, nil
+		}),
+		reservationsByUser: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var reservations []models.Reservation
+			if err := db.DB.Where("user_id IN ?", ids).Order("id asc").Find(&reservations).Error; err != nil {
+				return nil, err
+			}
+			groups := make(map[uint][]models.Reservation, len(ids))
+			for _, reservation := range reservations {
+				groups[reservation.UserID] = append(groups[reservation.UserID], reservation)
+			}
+			result := make(map[uint]interface{}, len(ids))
+			for _, id := range ids {
+				result[id] = append([]models.Reservation{}, groups[id]...)
+			}
+			return result, nil
+		}),
+		consultationsByUser: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var consultations []models.Consultation
+			if err := db.DB.Where("user_id IN ?", ids).Order("id asc").Find(&consultations).Error; err != nil {
+				return nil, err
+			}
+			return groupConsultations(ids, consultations, func(c models.Consultation) *uint { return &c.UserID }), nil
+		}),
+		consultationsByEmployee: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var consultations []models.Consultation
+			if err := db.DB.Where("assigned_employee_id IN ?", ids).Order("id asc").Find(&consultations).Error; err != nil {
+				return nil, err
+			}
+			return groupConsultations(ids, consultations, func(c models.Consultation) *uint { return c.AssignedEmployeeID }), nil
+		}),
+		messagesByConsultation: graph.NewLoader(func(ids []uint) (map[uint]interface{}, error) {
+			var messages []models.ConsultationMessage
+			if err := db.DB.Where("consultation_id IN ?", ids).Order("id asc").Find(&messages).Error; err != nil {
+				return nil, err
+			}
+			groups := make(map[uint][]models.ConsultationMessage, len(ids))
+			for _, message := range messages {
+				groups[message.ConsultationID] = append(groups[message.ConsultationID], message)
+			}
+			result := make(map[uint]interface{}, len(ids))
+			for _, id := range ids {
+				result[id] = append([]models.ConsultationMessage{}, groups[id]...)
+			}
+			return result, nil
+		}),
+	}
+}
+
+// groupConsultations agrupa las consultas por la clave indicada; las claves sin consultas quedan con una lista vacía
+func groupConsultations(ids []uint, consultations []models.Consultation, key func(models.Consultation) *uint) map[uint]interface{} {
+	groups := make(map[uint][]models.Consultation, len(ids))
+	for _, consultation := range consultations {
+		if id := key(consultation); id != nil {
+			groups[*id] = append(groups[*id], consultation)
+		}
+	}
+	result := make(map[uint]interface{}, len(ids))
+	for _, id := range ids {
+		result[id] = append([]models.Consultation{}, groups[id]...)
+	}
+	return result
+}
+
+func graphContextFrom(p graphql.ResolveParams) *graphContext {
+	return p.Context.Value(graphContextKey{}).(*graphContext)
+}
+
+// graphField expone un campo del struct que se resuelve. path es el nombre del campo en Go, o una ruta
+// como User.FirstName para los structs anidados; un puntero nil en el camino resuelve null
+func graphField(typ graphql.Output, path string, description string) *graphql.Field {
+	names := strings.Split(path, ".")
+	return &graphql.Field{
+		Type:        typ,
+		Description: description,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			value := reflect.ValueOf(p.Source)
+			for _, name := range names {
+				for value.Kind() == reflect.Ptr {
+					if value.IsNil() {
+						return nil, nil
+					}
+					value = value.Elem()
+				}
+				value = value.FieldByName(name)
+			}
+			return value.Interface(), nil
+		},
+	}
+}
+
+// graphManagerField resuelve el campo solo si quien hace la solicitud tiene rol manager, y null si no,
+// como el salario en la exportación de empleados
+func graphManagerField(field *graphql.Field) *graphql.Field {
+	resolve := field.Resolve
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		if !middleware.HasRole(graphContextFrom(p).request, middleware.RoleManager) {
+			return nil, nil
+		}
+		return resolve(p)
+	}
+	return field
+}
+
+// graphUint lee un campo uint o *uint del struct que se resuelve; 0 si es nil
+func graphUint(source interface{}, path string) uint {
+	value, _ := graphField(graphql.Int, path, "").Resolve(graphql.ResolveParams{Source: source})
+	switch value := value.(type) {
+	case uint:
+		return value
+	case *uint:
+		if value != nil {
+			return *value
+		}
+	}
+	return 0
+}
+
+// graphLoad resuelve una relación a partir del ID guardado en el campo indicado
+func graphLoad(loader func(*graphContext) *graph.Loader, path string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		id := graphUint(p.Source, path)
+		if id == 0 {
+			return nil, nil
+		}
+		return loader(graphContextFrom(p)).Load(id), nil
+	}
+}
+
+// graphPage lee los argumentos limit y offset de un listado
+func graphPage(p graphql.ResolveParams) (int, int, error) {
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if limit < 1 || limit > maxGraphQLPage {
+		return 0, 0, errors.New("limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("offset must not be negative")
+	}
+	return limit, offset, nil
+}
+
+// graphListArgs es el argumento limit de las listas de una relación. Por defecto devuelven
+// graph.DefaultListSize elementos, el tamaño con el que se estiman las listas sin limit
+var graphListArgs = graphql.FieldConfigArgument{
+	"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graph.DefaultListSize, Description: "Cantidad de elementos, hasta 100"},
+}
+
+// graphLoadList resuelve una lista relacionada como graphLoad y devuelve solo los primeros limit
+// elementos, para que la consulta no resuelva más campos que los estimados en su complejidad
+func graphLoadList(loader func(*graphContext) *graph.Loader, path string) graphql.FieldResolveFn {
+	load := graphLoad(loader, path)
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		limit, _ := p.Args["limit"].(int)
+		if limit < 1 || limit > maxGraphQLPage {
+			return nil, errors.New("limit must be between 1 and 100")
+		}
+		value, err := load(p)
+		fetch, ok := value.(func() (interface{}, error))
+		if err != nil || !ok {
+			return value, err
+		}
+		return func() (interface{}, error) {
+			list, err := fetch()
+			if err != nil {
+				return nil, err
+			}
+			if items := reflect.ValueOf(list); items.Kind() == reflect.Slice && items.Len() > limit {
+				return items.Slice(0, limit).Interface(), nil
+			}
+			return list, nil
+		}, nil
+	}
+}
+
+// graphPageArgs son los argumentos de paginación, más los indicados
+func graphPageArgs(extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 50, Description: "Cantidad de elementos, hasta 100"},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+	for name, arg := range extra {
+		args[name] = arg
+	}
+	return args
+}
+
+// graphByID busca un registro por el argumento id usando el loader indicado
+func graphByID(loader func(*graphContext) *graph.Loader) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		id, _ := p.Args["id"].(int)
+		if id < 1 {
+			return nil, nil
+		}
+		return loader(graphContextFrom(p)).Load(uint(id)), nil
+	}
+}
+
+// graphReservation busca la reserva del argumento id para una mutación
+func graphReservation(p graphql.ResolveParams) (models.Reservation, error) {
+	var reservation models.Reservation
+	id, _ := p.Args["id"].(int)
+	if err := db.DB.First(&reservation, id).Error; err != nil {
+		if err.Error() == "record not found" {
+			return reservation, errors.New("Reservation not found")
+		}
+		return reservation, errors.New("Failed to retrieve reservation")
+	}
+	return reservation, nil
+}
+
+func newGraphQLSchema() graphql.Schema {
+	idArg := graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}}
+	nonNullInt := graphql.NewNonNull(graphql.Int)
+	nonNullString := graphql.NewNonNull(graphql.String)
+	nonNullDateTime := graphql.NewNonNull(graphql.DateTime)
+
+	var userType, reservationType, consultationType, messageType, employeeType *graphql.Object
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "Huésped",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":        graphField(nonNullInt, "ID", ""),
+				"createdAt": graphField(nonNullDateTime, "CreatedAt", ""),
+				"updatedAt": graphField(nonNullDateTime, "UpdatedAt", ""),
+				"firstName": graphField(nonNullString, "FirstName", ""),
+				"lastName":  graphField(nonNullString, "LastName", ""),
+				"email":     graphField(nonNullString, "Email", ""),
+				"locale":    graphField(nonNullString, "Locale", "Idioma de las notificaciones"),
+				"version":   graphField(nonNullInt, "Version", "Versión para el control de concurrencia optimista"),
+				"reservations": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reservationType))),
+					Args:    graphListArgs,
+					Resolve: graphLoadList(func(c *graphContext) *graph.Loader { return c.reservationsByUser }, "ID"),
+				},
+				"consultations": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(consultationType))),
+					Args:    graphListArgs,
+					Resolve: graphLoadList(func(c *graphContext) *graph.Loader { return c.consultationsByUser }, "ID"),
+				},
+			}
+		}),
+	})
+
+	reservationType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Reservation",
+		Description: "Reserva",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              graphField(nonNullInt, "ID", ""),
+				"createdAt":       graphField(nonNullDateTime, "CreatedAt", ""),
+				"updatedAt":       graphField(nonNullDateTime, "UpdatedAt", ""),
+				"status":          graphField(nonNullString, "Status", "tentative, confirmed, checked_in, checked_out o cancelled"),
+				"checkIn":         graphField(nonNullDateTime, "Checkin", ""),
+				"checkOut":        graphField(nonNullDateTime, "Checkout", ""),
+				"adults":          graphField(nonNullInt, "Adults", ""),
+				"children":        graphField(nonNullInt, "Children", ""),
+				"numberOfRooms":   graphField(nonNullInt, "NumberOfRooms", ""),
+				"roomType":        graphField(nonNullString, "RoomType", ""),
+				"guestName":       graphField(nonNullString, "GuestName", ""),
+				"email":           graphField(nonNullString, "Email", ""),
+				"ratePlanId":      graphField(graphql.Int, "RatePlanID", ""),
+				"groupBlockId":    graphField(graphql.Int, "GroupBlockID", ""),
+				"cancelledAt":     graphField(graphql.DateTime, "CancelledAt", ""),
+				"checkedInAt":     graphField(graphql.DateTime, "CheckedInAt", ""),
+				"checkedOutAt":    graphField(graphql.DateTime, "CheckedOutAt", ""),
+				"cancellationFee": graphField(graphql.NewNonNull(graphql.Float), "CancellationFee", ""),
+				"version":         graphField(nonNullInt, "Version", ""),
+				"userId":          graphField(nonNullInt, "UserID", ""),
+				"nights": &graphql.Field{
+					Type: nonNullInt,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(models.Reservation).Nights(), nil
+					},
+				},
+				"user": &graphql.Field{
+					Type:    userType,
+					Resolve: graphLoad(func(c *graphContext) *graph.Loader { return c.users }, "UserID"),
+				},
+			}
+		}),
+	})
+
+	messageType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ConsultationMessage",
+		Description: "Mensaje del hilo de una consulta",
+		Fields: graphql.Fields{
+			"id":         graphField(nonNullInt, "ID", ""),
+			"createdAt":  graphField(nonNullDateTime, "CreatedAt", ""),
+			"author":     graphField(nonNullString, "Author", "guest o staff"),
+			"body":       graphField(nonNullString, "Body", ""),
+			"employeeId": graphField(graphql.Int, "EmployeeID", ""),
+		},
+	})
+
+	consultationType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Consultation",
+		Description: "Consulta de un huésped",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":                 graphField(nonNullInt, "ID", ""),
+				"createdAt":          graphField(nonNullDateTime, "CreatedAt", ""),
+				"updatedAt":          graphField(nonNullDateTime, "UpdatedAt", ""),
+				"phone":              graphField(nonNullString, "Phone", ""),
+				"consultation":       graphField(nonNullString, "Consultation", ""),
+				"moreInfo":           graphField(graphql.NewNonNull(graphql.Boolean), "MoreInfo", ""),
+				"status":             graphField(nonNullString, "Status", "open, pending, answered o closed"),
+				"dueAt":              graphField(graphql.DateTime, "DueAt", "Plazo para responder al huésped"),
+				"answeredAt":         graphField(graphql.DateTime, "AnsweredAt", ""),
+				"closedAt":           graphField(graphql.DateTime, "ClosedAt", ""),
+				"convertedAt":        graphField(graphql.DateTime, "ConvertedAt", ""),
+				"userId":             graphField(nonNullInt, "UserID", ""),
+				"assignedEmployeeId": graphField(graphql.Int, "AssignedEmployeeID", ""),
+				"reservationId":      graphField(graphql.Int, "ReservationID", ""),
+				"overdue": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "La consulta espera respuesta y venció su plazo",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(models.Consultation).Overdue(time.Now()), nil
+					},
+				},
+				"user": &graphql.Field{
+					Type:    userType,
+					Resolve: graphLoad(func(c *graphContext) *graph.Loader { return c.users }, "UserID"),
+				},
+				"assignedEmployee": &graphql.Field{
+					Type:    employeeType,
+					Resolve: graphLoad(func(c *graphContext) *graph.Loader { return c.employees }, "AssignedEmployeeID"),
+				},
+				"reservation": &graphql.Field{
+					Type:    reservationType,
+					Resolve: graphLoad(func(c *graphContext) *graph.Loader { return c.reservations }, "ReservationID"),
+				},
+				"messages": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(messageType))),
+					Args:    graphListArgs,
+					Resolve: graphLoadList(func(c *graphContext) *graph.Loader { return c.messagesByConsultation }, "ID"),
+				},
+			}
+		}),
+	})
+
+	employeeType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Employee",
+		Description: "Empleado del hotel",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          graphField(nonNullInt, "User.ID", ""),
+				"firstName":   graphField(graphql.String, "User.FirstName", ""),
+				"lastName":    graphField(graphql.String, "User.LastName", ""),
+				"email":       graphField(graphql.String, "User.Email", ""),
+				"position":    graphField(nonNullString, "Position", ""),
+				"department":  graphField(nonNullString, "Department", ""),
+				"salary":      graphManagerField(graphField(graphql.Float, "Salary", "Solo para el rol manager; null para el resto")),
+				"hireDate":    graphField(nonNullString, "HireDate", ""),
+				"phoneNumber": graphField(nonNullString, "PhoneNumber", ""),
+				"assignedConsultations": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(consultationType))),
+					Args:    graphListArgs,
+					Resolve: graphLoadList(func(c *graphContext) *graph.Loader { return c.consultationsByEmployee }, "User.ID"),
+				},
+			}
+		}),
+	})
+
+	availabilityType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Availability",
+		Description: "Habitaciones libres de un tipo en un rango de fechas",
+		Fields: graphql.Fields{
+			"roomType":  graphField(nonNullString, "RoomType", ""),
+			"from":      graphField(nonNullDateTime, "From", ""),
+			"to":        graphField(nonNullDateTime, "To", ""),
+			"available": graphField(nonNullInt, "Available", ""),
+		},
+	})
+
+	cancellationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CancellationQuote",
+		Description: "Resultado de cancelar una reserva",
+		Fields: graphql.Fields{
+			"reservation": graphField(graphql.NewNonNull(reservationType), "Reservation", ""),
+			"penalty":     graphField(graphql.NewNonNull(graphql.Float), "Penalty", "Penalización cargada en el folio"),
+			"freeUntil":   graphField(graphql.DateTime, "FreeUntil", "Hasta cuándo se podía cancelar sin cargo"),
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"user": &graphql.Field{
+				Type:    userType,
+				Args:    idArg,
+				Resolve: graphByID(func(c *graphContext) *graph.Loader { return c.users }),
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Args: graphPageArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset, err := graphPage(p)
+					if err != nil {
+						return nil, err
+					}
+					var users []models.User
+					if err := db.DB.Order("id asc").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+						return nil, errors.New("Failed to retrieve users")
+					}
+					return users, nil
+				},
+			},
+			"reservation": &graphql.Field{
+				Type:    reservationType,
+				Args:    idArg,
+				Resolve: graphByID(func(c *graphContext) *graph.Loader { return c.reservations }),
+			},
+			"reservations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reservationType))),
+				Args: graphPageArgs(graphql.FieldConfigArgument{
+					"status": &graphql.ArgumentConfig{Type: graphql.String, Description: "Filtrar por estado"},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset, err := graphPage(p)
+					if err != nil {
+						return nil, err
+					}
+					conn := db.DB.Order("id asc").Limit(limit).Offset(offset)
+					if status, ok := p.Args["status"].(string); ok {
+						conn = conn.Where("status = ?", status)
+					}
+					var reservations []models.Reservation
+					if err := conn.Find(&reservations).Error; err != nil {
+						return nil, errors.New("Failed to retrieve reservations")
+					}
+					return reservations, nil
+				},
+			},
+			"consultations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(consultationType))),
+				Args: graphPageArgs(graphql.FieldConfigArgument{
+					"status":             &graphql.ArgumentConfig{Type: graphql.String, Description: "Filtrar por estado"},
+					"assignedEmployeeId": &graphql.ArgumentConfig{Type: graphql.Int, Description: "Filtrar por empleado asignado"},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset, err := graphPage(p)
+					if err != nil {
+						return nil, err
+					}
+					conn := db.DB.Order("id asc").Limit(limit).Offset(offset)
+					if status, ok := p.Args["status"].(string); ok {
+						conn = conn.Where("status = ?", status)
+					}
+					if employeeID, ok := p.Args["assignedEmployeeId"].(int); ok {
+						conn = conn.Where("assigned_employee_id = ?", employeeID)
+					}
+					var consultations []models.Consultation
+					if err := conn.Find(&consultations).Error; err != nil {
+						return nil, errors.New("Failed to retrieve consultations")
+					}
+					return consultations, nil
+				},
+			},
+			"consultation": &graphql.Field{
+				Type: consultationType,
+				Args: idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var consultation models.Consultation
+					if err := db.DB.First(&consultation, p.Args["id"]).Error; err != nil {
+						if err.Error() == "record not found" {
+							return nil, nil
+						}
+						return nil, errors.New("Failed to retrieve consultation")
+					}
+					return consultation, nil
+				},
+			},
+			"employee": &graphql.Field{
+				Type:    employeeType,
+				Args:    idArg,
+				Resolve: graphByID(func(c *graphContext) *graph.Loader { return c.employees }),
+			},
+			"employees": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(employeeType))),
+				Args: graphPageArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, offset, err := graphPage(p)
+					if err != nil {
+						return nil, err
+					}
+					var employees []models.Employee
+					if err := db.DB.Order("id asc").Limit(limit).Offset(offset).Find(&employees).Error; err != nil {
+						return nil, errors.New("Failed to retrieve employees")
+					}
+					return employees, nil
+				},
+			},
+			"availability": &graphql.Field{
+				Type: availabilityType,
+				Args: graphql.FieldConfigArgument{
+					"roomType": &graphql.ArgumentConfig{Type: nonNullString},
+					"from":     &graphql.ArgumentConfig{Type: nonNullString, Description: "Fecha de llegada, AAAA-MM-DD o RFC 3339"},
+					"to":       &graphql.ArgumentConfig{Type: nonNullString, Description: "Fecha de salida"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					from, err := parseDate(p.Args["from"].(string))
+					if err != nil {
+						return nil, errors.New("Invalid from date")
+					}
+					to, err := parseDate(p.Args["to"].(string))
+					if err != nil || !to.After(from) {
+						return nil, errors.New("Invalid to date")
+					}
+					roomType := p.Args["roomType"].(string)
+					available, err := inventory.Availability(db.DB, roomType, from, to)
+					if err != nil {
+						return nil, errors.New("Failed to compute availability")
+					}
+					if available < 0 {
+						return nil, errors.New("Room type not found")
+					}
+					return availabilityResult{roomType, from, to, available}, nil
+				},
+			},
+		},
+	})
+
+	reservationInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ReservationInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"userId":        &graphql.InputObjectFieldConfig{Type: nonNullInt},
+			"email":         &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"guestName":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"roomType":      &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"checkIn":       &graphql.InputObjectFieldConfig{Type: nonNullString, Description: "AAAA-MM-DD o RFC 3339"},
+			"checkOut":      &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"adults":        &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 1},
+			"children":      &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 0},
+			"numberOfRooms": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 1},
+			"ratePlanId":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createReservation": &graphql.Field{
+				Type:        graphql.NewNonNull(reservationType),
+				Description: "Crea una reserva si quedan habitaciones del tipo pedido",
+				Args:        graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(reservationInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input := p.Args["input"].(map[string]interface{})
+					checkin, err := parseDate(input["checkIn"].(string))
+					if err != nil {
+						return nil, errors.New("Invalid checkIn date")
+					}
+					checkout, err := parseDate(input["checkOut"].(string))
+					if err != nil || !checkout.After(checkin) {
+						return nil, errors.New("Invalid checkOut date")
+					}
+					reservation := models.Reservation{
+						UserID:   uint(input["userId"].(int)),
+						Email:    input["email"].(string),
+						RoomType: input["roomType"].(string),
+						Checkin:  checkin,
+						Checkout: checkout,
+					}
+					reservation.GuestName, _ = input["guestName"].(string)
+					reservation.Adults, _ = input["adults"].(int)
+					reservation.Children, _ = input["children"].(int)
+					reservation.NumberOfRooms, _ = input["numberOfRooms"].(int)
+					if ratePlanID, ok := input["ratePlanId"].(int); ok {
+						id := uint(ratePlanID)
+						reservation.RatePlanID = &id
+					}
+					if err := createReservation(graphContextFrom(p).request, &reservation); err != nil {
+						return nil, err
+					}
+					return reservation, nil
+				},
+			},
+			"cancelReservation": &graphql.Field{
+				Type:        graphql.NewNonNull(cancellationType),
+				Description: "Cancela una reserva cobrando la penalización que corresponda",
+				Args:        idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					reservation, err := graphReservation(p)
+					if err != nil {
+						return nil, err
+					}
+					return cancelReservation(graphContextFrom(p).request, reservation)
+				},
+			},
+			"confirmReservation": &graphql.Field{
+				Type:        graphql.NewNonNull(reservationType),
+				Description: "Confirma una reserva tentativa",
+				Args:        idArg,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					reservation, err := graphReservation(p)
+					if err != nil {
+						return nil, err
+					}
+					return confirmReservation(graphContextFrom(p).request, reservation)
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
@@ -0,0 +1,177 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// Configura el router para las pruebas de GraphQL. Las respuestas se validan contra la especificación OpenAPI
+func setupGraphQLRouter() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/graphql", GraphQLHandler).Methods("GET", "POST")
+	return middleware.ValidationWithResponses(OpenAPI, true, r)
+}
+
+type graphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// postGraphQL envía la operación por POST y decodifica la respuesta
+func postGraphQL(t *testing.T, router http.Handler, query string, variables map[string]interface{}) (*httptest.ResponseRecorder, graphQLResponse) {
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	var response graphQLResponse
+	if rr.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	}
+	return rr, response
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	router := setupGraphQLRouter()
+
+	// Los límites se comprueban antes de ejecutar, sin tocar la base de datos
+	rr, response := postGraphQL(t, router, "{ users(limit: 100) { reservations { user { consultations { messages { body } } } } } }", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, response.Data)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "query complexity 112101 exceeds the limit of 5000", response.Errors[0].Message)
+	}
+
+	// Las listas de una relación se estiman por su propio limit
+	_, response = postGraphQL(t, router, "{ users(limit: 100) { reservations(limit: 100) { id } } }", nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "query complexity 10101 exceeds the limit of 5000", response.Errors[0].Message)
+	}
+
+	deep := "{ user(id: 1) { reservations { user { reservations { user { reservations { user { reservations { user { reservations { id } } } } } } } } } } }"
+	_, response = postGraphQL(t, router, deep, nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "query depth 11 exceeds the limit of 10", response.Errors[0].Message)
+	}
+
+	// El limit de los listados se valida en el resolver
+	_, response = postGraphQL(t, router, "query($n: Int) { users(limit: $n) { id } }", map[string]interface{}{"n": 0})
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "limit must be between 1 and 100", response.Errors[0].Message)
+	}
+
+	// Por GET no se ejecutan mutaciones
+	req, _ := http.NewRequest("GET", "/graphql?query="+url.QueryEscape("mutation { cancelReservation(id: 1) { penalty } }"), nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "mutation operations are only allowed with POST", response.Errors[0].Message)
+	}
+}
+
+func TestGraphQLInvalidRequests(t *testing.T) {
+	router := setupGraphQLRouter()
+
+	rr, _ := postGraphQL(t, router, "  ", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Missing GraphQL query\n", rr.Body.String())
+
+	req, _ := http.NewRequest("GET", "/graphql?query="+url.QueryEscape("{ users { id } }")+"&variables=nope", nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid variables\n", rr.Body.String())
+
+	// Los errores de validación del documento se devuelven en errors
+	_, response := postGraphQL(t, router, "{ rooms { id } }", nil)
+	if assert.Len(t, response.Errors, 1) {
+		assert.Contains(t, response.Errors[0].Message, `Cannot query field "rooms"`)
+	}
+}
+
+func TestGraphQLNestedQuery(t *testing.T) {
+	setupConsultationDB()
+	db.DB.AutoMigrate(&models.Reservation{})
+	defer func() {
+		db.DB.Unscoped().Exec("DELETE FROM reservations")
+		cleanUpConsultationDB()
+	}()
+
+	// Crear tres usuarios con una reserva y una consulta cada uno
+	checkin := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		user := models.User{FirstName: fmt.Sprintf("Guest%d", i), LastName: "Test", Email: fmt.Sprintf("guest%d@example.com", i)}
+		db.DB.Create(&user)
+		db.DB.Create(&models.Reservation{UserID: user.ID, Email: user.Email, Checkin: checkin, Checkout: checkin.AddDate(0, 0, i), Adults: 2, NumberOfRooms: 1, RoomType: "double"})
+		consultation := models.Consultation{UserID: user.ID, Phone: "+1234567890", Consultation: "¿Hay estacionamiento?"}
+		db.DB.Create(&consultation)
+		db.DB.Create(&models.ConsultationMessage{ConsultationID: consultation.ID, Author: models.MessageFromStaff, Body: "Sí, sin cargo"})
+	}
+
+	// Contar las consultas SQL para comprobar que las relaciones se cargan por lotes
+	queries := 0
+	db.DB.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries++ })
+	defer db.DB.Callback().Query().Remove("test:count_queries")
+
+	router := setupGraphQLRouter()
+	rr, response := postGraphQL(t, router, "{ users { firstName reservations { nights user { email } } consultations { messages { body } } } }", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, response.Errors)
+	// users, reservas por usuario, consultas por usuario, usuarios de las reservas y mensajes por consulta
+	assert.Equal(t, 5, queries)
+
+	users := response.Data["users"].([]interface{})
+	if assert.Len(t, users, 3) {
+		first := users[0].(map[string]interface{})
+		assert.Equal(t, "Guest1", first["firstName"])
+		reservation := first["reservations"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, float64(1), reservation["nights"])
+		assert.Equal(t, "guest1@example.com", reservation["user"].(map[string]interface{})["email"])
+		consultation := first["consultations"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, []interface{}{map[string]interface{}{"body": "Sí, sin cargo"}}, consultation["messages"])
+	}
+}
+
+func TestGraphQLSalaryRequiresManager(t *testing.T) {
+	salary := graphManagerField(graphField(graphql.Float, "Salary", ""))
+	employee := models.Employee{User: &models.User{}, Salary: 1500}
+	resolve := func(token string) interface{} {
+		var value interface{}
+		handler := middleware.AuthWithTokens(middleware.ParseTokens("staff-token:staff:bob,manager-token:manager:alice"),
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), graphContextKey{}, newGraphContext(r))
+				value, _ = salary.Resolve(graphql.ResolveParams{Source: &employee, Context: ctx})
+			}))
+		req, _ := http.NewRequest("POST", "/graphql", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return value
+	}
+
+	// El salario solo se resuelve para el rol manager; el resto recibe null
+	assert.Nil(t, resolve(""))
+	assert.Nil(t, resolve("staff-token"))
+	assert.Equal(t, 1500.0, resolve("manager-token"))
+}
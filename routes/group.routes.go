@@ -0,0 +1,275 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errores de negocio al tomar habitaciones de un bloqueo de grupo
+var (
+	errGroupBlockClosed = errors.New("group block is released")
+	errAllotmentFull    = errors.New("no rooms left in the group allotment")
+)
+
+// groupBlockResponse es el bloqueo con las reservas tomadas por los huéspedes del grupo
+type groupBlockResponse struct {
+	models.GroupBlock
+	Reservations []models.Reservation `json:"reservations"`
+}
+
+// findGroupBlock busca el bloqueo indicado en la URL con sus cupos y responde 404 si no existe
+func findGroupBlock(w http.ResponseWriter, r *http.Request) (models.GroupBlock, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var block models.GroupBlock
+	if err := db.DB.Preload("Allotments").First(&block, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Group block not found"))
+			return block, false
+		}
+		http.Error(w, "Failed to retrieve group block", http.StatusInternalServerError)
+		return block, false
+	}
+	return block, true
+}
+
+// GetGroupBlocksHandler obtiene todos los bloqueos de grupo con sus cupos
+func GetGroupBlocksHandler(w http.ResponseWriter, r *http.Request) {
+	var blocks []models.GroupBlock
+	if err := db.DB.Preload("Allotments").Order("id asc").Find(&blocks).Error; err != nil {
+		http.Error(w, "Failed to retrieve group blocks", http.StatusInternalServerError)
+		return
+	}
+	for i := range blocks {
+		if err := inventory.LoadPickups(db.DB, &blocks[i]); err != nil {
+			http.Error(w, "Failed to retrieve group pickups", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(&blocks); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetGroupBlockHandler obtiene un bloqueo de grupo con sus cupos y las reservas tomadas
+func GetGroupBlockHandler(w http.ResponseWriter, r *http.Request) {
+	block, ok := findGroupBlock(w, r)
+	if !ok {
+		return
+	}
+
+	response := groupBlockResponse{GroupBlock: block}
+	if err := inventory.LoadPickups(db.DB, &response.GroupBlock); err != nil {
+		http.Error(w, "Failed to retrieve group pickups", http.StatusInternalServerError)
+		return
+	}
+	if err := db.DB.Where("group_block_id = ?", block.ID).Order("id asc").Find(&response.Reservations).Error; err != nil {
+		http.Error(w, "Failed to retrieve group reservations", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&response); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateGroupBlockHandler crea un bloqueo de grupo reteniendo el cupo de cada tipo de habitación
+func CreateGroupBlockHandler(w http.ResponseWriter, r *http.Request) {
+	var block models.GroupBlock
+	if err := json.NewDecoder(r.Body).Decode(&block); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if block.Code == "" || block.Name == "" || !block.Checkout.After(block.Checkin) ||
+		block.ReleaseDate.IsZero() || block.ReleaseDate.After(block.Checkin) || len(block.Allotments) == 0 {
+		http.Error(w, "Invalid group block", http.StatusBadRequest)
+		return
+	}
+	roomTypes := make(map[string]bool)
+	for i, allotment := range block.Allotments {
+		if allotment.RoomType == "" || allotment.Rooms <= 0 || roomTypes[allotment.RoomType] {
+			http.Error(w, "Invalid group allotment", http.StatusBadRequest)
+			return
+		}
+		roomTypes[allotment.RoomType] = true
+		block.Allotments[i] = models.GroupAllotment{RoomType: allotment.RoomType, Rooms: allotment.Rooms}
+	}
+	block.Status = models.GroupBlockOpen
+	block.ReleasedAt = nil
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// El cupo solo se puede retener si hay habitaciones libres en todas las noches
+		for _, allotment := range block.Allotments {
+			if err := inventory.Reserve(tx, allotment.RoomType, block.Checkin, block.Checkout, allotment.Rooms); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&block).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceGroupBlock, block.ID, nil, &block)
+	})
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&block); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// groupPickup es una reserva tomada del cupo de un grupo
+type groupPickup struct {
+	GuestName string `json:"guest_name"`
+	Email     string `json:"email"`
+	RoomType  string `json:"room_type"`
+	Adults    int    `json:"adults"`
+	Children  int    `json:"children"`
+	UserID    uint   `json:"user_id"`
+}
+
+// PickupGroupRoomHandler crea la reserva de un huésped del grupo tomando una habitación del cupo
+func PickupGroupRoomHandler(w http.ResponseWriter, r *http.Request) {
+	block, ok := findGroupBlock(w, r)
+	if !ok {
+		return
+	}
+
+	var pickup groupPickup
+	if err := json.NewDecoder(r.Body).Decode(&pickup); err != nil || pickup.GuestName == "" || pickup.RoomType == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	// Si el huésped no indica su email se usa el del contacto del grupo
+	if pickup.Email == "" {
+		pickup.Email = block.ContactEmail
+	}
+
+	var reservation models.Reservation
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloquear el bloqueo de grupo para que dos tomas simultáneas no excedan el cupo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Allotments").First(&block, block.ID).Error; err != nil {
+			return err
+		}
+		if block.Status != models.GroupBlockOpen || time.Now().After(block.ReleaseDate) {
+			return errGroupBlockClosed
+		}
+		if err := inventory.LoadPickups(tx, &block); err != nil {
+			return err
+		}
+		var allotment *models.GroupAllotment
+		for i := range block.Allotments {
+			if block.Allotments[i].RoomType == pickup.RoomType {
+				allotment = &block.Allotments[i]
+			}
+		}
+		if allotment == nil || allotment.Held() == 0 {
+			return errAllotmentFull
+		}
+
+		reservation = models.Reservation{
+			Adults:        pickup.Adults,
+			Checkin:       block.Checkin,
+			Checkout:      block.Checkout,
+			Children:      pickup.Children,
+			Email:         pickup.Email,
+			NumberOfRooms: 1,
+			RoomType:      pickup.RoomType,
+			UserID:        pickup.UserID,
+			GuestName:     pickup.GuestName,
+			GroupBlockID:  &block.ID,
+			RatePlanID:    block.RatePlanID,
+		}
+		if err := tx.Create(&reservation).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceReservation, reservation.ID, nil, &reservation); err != nil {
+			return err
+		}
+		return events.PublishReservation(tx, events.ReservationCreated, reservation)
+	})
+	switch {
+	case errors.Is(err, errGroupBlockClosed), errors.Is(err, errAllotmentFull):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// ReleaseGroupBlockHandler devuelve al inventario las habitaciones del cupo que no fueron tomadas
+func ReleaseGroupBlockHandler(w http.ResponseWriter, r *http.Request) {
+	block, ok := findGroupBlock(w, r)
+	if !ok {
+		return
+	}
+	if block.Status == models.GroupBlockReleased {
+		http.Error(w, "Group block already released", http.StatusConflict)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		before := block
+		before.Allotments = append([]models.GroupAllotment(nil), block.Allotments...)
+		if err := inventory.Release(tx, &block, time.Now()); err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceGroupBlock, block.ID, &before, &block)
+	})
+	if err != nil {
+		http.Error(w, "Failed to release group block", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&block); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetGroupFolioHandler devuelve el folio maestro del grupo con sus movimientos y saldo
+func GetGroupFolioHandler(w http.ResponseWriter, r *http.Request) {
+	block, ok := findGroupBlock(w, r)
+	if !ok {
+		return
+	}
+
+	folio, err := findOrCreateGroupFolio(db.DB, block.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve folio", http.StatusInternalServerError)
+		return
+	}
+	writeFolio(w, folio)
+}
+
+// PostGroupFolioChargeHandler registra un cargo en el folio maestro del grupo
+func PostGroupFolioChargeHandler(w http.ResponseWriter, r *http.Request) {
+	block, ok := findGroupBlock(w, r)
+	if !ok {
+		return
+	}
+	postFolioCharge(w, r, func(tx *gorm.DB) (models.Folio, error) {
+		return findOrCreateGroupFolio(tx, block.ID)
+	})
+}
@@ -0,0 +1,644 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/hotelpb"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// Tamaño de página de los listados gRPC
+const (
+	defaultGRPCPage = 50
+	maxGRPCPage     = 100
+)
+
+// NewGRPCServer crea el servidor gRPC con los servicios de usuarios, reservas, consultas y empleados.
+// Las opciones agregan los interceptores, como middleware.GRPCAuth
+func NewGRPCServer(options ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(options...)
+	hotelpb.RegisterUserServiceServer(server, userService{})
+	hotelpb.RegisterReservationServiceServer(server, reservationService{})
+	hotelpb.RegisterConsultationServiceServer(server, consultationService{})
+	hotelpb.RegisterEmployeeServiceServer(server, employeeService{})
+	return server
+}
+
+// grpcRequest adapta el contexto de una llamada gRPC a la solicitud que reciben las funciones de negocio
+// compartidas con los handlers REST, que la usan para registrar en la auditoría quién hizo el cambio
+func grpcRequest(ctx context.Context) *http.Request {
+	r, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+	return r
+}
+
+// grpcPage interpreta page_size y page_token. Los listados piden un registro de más para saber si
+// hay otra página
+func grpcPage(size int32, token string) (int, uint64, error) {
+	if size < 0 || size > maxGRPCPage {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", maxGRPCPage)
+	}
+	if size == 0 {
+		size = defaultGRPCPage
+	}
+	var afterID uint64
+	if token != "" {
+		var err error
+		if afterID, err = strconv.ParseUint(token, 10, 64); err != nil {
+			return 0, 0, status.Error(codes.InvalidArgument, "Invalid page_token")
+		}
+	}
+	return int(size), afterID, nil
+}
+
+// grpcPageQuery ordena la consulta por id y la limita a la página indicada
+func grpcPageQuery(conn *gorm.DB, size int, afterID uint64) *gorm.DB {
+	return conn.Where("id > ?", afterID).Order("id asc").Limit(size + 1)
+}
+
+// nextPageToken devuelve el token de la página siguiente, o vacío si la página es la última
+func nextPageToken(found, size int, lastID uint) string {
+	if found <= size {
+		return ""
+	}
+	return strconv.FormatUint(uint64(lastID), 10)
+}
+
+// grpcFind busca el registro por id y devuelve NOT_FOUND si no existe
+func grpcFind(conn *gorm.DB, record interface{}, id uint64, notFound, failure string) error {
+	if err := conn.First(record, id).Error; err != nil {
+		if err.Error() == "record not found" {
+			return status.Error(codes.NotFound, notFound)
+		}
+		return status.Error(codes.Internal, failure)
+	}
+	return nil
+}
+
+// checkVersion es el equivalente de If-Match: con una versión distinta de cero la escritura solo se
+// permite si el registro no cambió desde entonces
+func checkVersion(expected uint64, current uint) error {
+	if expected != 0 && expected != uint64(current) {
+		return status.Error(codes.Aborted, "Precondition failed: record was modified")
+	}
+	return nil
+}
+
+// grpcSaveError responde ABORTED cuando la escritura perdió la carrera con otra solicitud, como el 412
+// de writeSaveError, e INTERNAL en otro caso
+func grpcSaveError(err error, message string) error {
+	if errors.Is(err, errStaleWrite) {
+		return status.Error(codes.Aborted, "Precondition failed: record was modified")
+	}
+	return status.Error(codes.Internal, message)
+}
+
+func grpcTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func grpcID(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	value := uint64(*id)
+	return &value
+}
+
+func modelID(id *uint64) *uint {
+	if id == nil {
+		return nil
+	}
+	value := uint(*id)
+	return &value
+}
+
+func userProto(user models.User) *hotelpb.User {
+	return &hotelpb.User{
+		Id:        uint64(user.ID),
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Locale:    user.Locale,
+		Version:   uint64(user.Version),
+	}
+}
+
+func reservationProto(reservation models.Reservation) *hotelpb.Reservation {
+	return &hotelpb.Reservation{
+		Id:              uint64(reservation.ID),
+		CreatedAt:       timestamppb.New(reservation.CreatedAt),
+		UpdatedAt:       timestamppb.New(reservation.UpdatedAt),
+		Status:          reservation.Status,
+		CheckIn:         timestamppb.New(reservation.Checkin),
+		CheckOut:        timestamppb.New(reservation.Checkout),
+		Adults:          int32(reservation.Adults),
+		Children:        int32(reservation.Children),
+		NumberOfRooms:   int32(reservation.NumberOfRooms),
+		RoomType:        reservation.RoomType,
+		GuestName:       reservation.GuestName,
+		Email:           reservation.Email,
+		UserId:          uint64(reservation.UserID),
+		RatePlanId:      grpcID(reservation.RatePlanID),
+		GroupBlockId:    grpcID(reservation.GroupBlockID),
+		CancelledAt:     grpcTime(reservation.CancelledAt),
+		CheckedInAt:     grpcTime(reservation.CheckedInAt),
+		CheckedOutAt:    grpcTime(reservation.CheckedOutAt),
+		CancellationFee: reservation.CancellationFee,
+		Version:         uint64(reservation.Version),
+	}
+}
+
+func consultationProto(consultation models.Consultation) *hotelpb.Consultation {
+	result := &hotelpb.Consultation{
+		Id:                 uint64(consultation.ID),
+		CreatedAt:          timestamppb.New(consultation.CreatedAt),
+		UpdatedAt:          timestamppb.New(consultation.UpdatedAt),
+		Phone:              consultation.Phone,
+		Consultation:       consultation.Consultation,
+		MoreInfo:           consultation.MoreInfo,
+		UserId:             uint64(consultation.UserID),
+		Status:             consultation.Status,
+		AssignedEmployeeId: grpcID(consultation.AssignedEmployeeID),
+		DueAt:              grpcTime(consultation.DueAt),
+		AnsweredAt:         grpcTime(consultation.AnsweredAt),
+		ClosedAt:           grpcTime(consultation.ClosedAt),
+		ReservationId:      grpcID(consultation.ReservationID),
+	}
+	for _, message := range consultation.Messages {
+		result.Messages = append(result.Messages, &hotelpb.ConsultationMessage{
+			Id:         uint64(message.ID),
+			CreatedAt:  timestamppb.New(message.CreatedAt),
+			Author:     message.Author,
+			EmployeeId: grpcID(message.EmployeeID),
+			Body:       message.Body,
+		})
+	}
+	return result
+}
+
+func employeeProto(ctx context.Context, employee models.Employee) *hotelpb.Employee {
+	result := &hotelpb.Employee{
+		Position:    employee.Position,
+		Department:  employee.Department,
+		HireDate:    employee.HireDate,
+		PhoneNumber: employee.PhoneNumber,
+	}
+	// El salario solo se envía al rol manager, como en GraphQL y en las exportaciones
+	if middleware.PrincipalFromContext(ctx).HasRole(middleware.RoleManager) {
+		result.Salary = &employee.Salary
+	}
+	if employee.User != nil {
+		result.User = userProto(*employee.User)
+	}
+	return result
+}
+
+// userService implementa hotelpb.UserServiceServer
+type userService struct {
+	hotelpb.UnimplementedUserServiceServer
+}
+
+func (userService) ListUsers(ctx context.Context, req *hotelpb.ListUsersRequest) (*hotelpb.ListUsersResponse, error) {
+	size, afterID, err := grpcPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := grpcPageQuery(db.DB.WithContext(ctx), size, afterID).Find(&users).Error; err != nil {
+		return nil, status.Error(codes.Internal, "Failed to retrieve users")
+	}
+
+	response := &hotelpb.ListUsersResponse{}
+	for i, user := range users {
+		if i == size {
+			response.NextPageToken = nextPageToken(len(users), size, users[i-1].ID)
+			break
+		}
+		response.Users = append(response.Users, userProto(user))
+	}
+	return response, nil
+}
+
+func (userService) GetUser(ctx context.Context, req *hotelpb.GetUserRequest) (*hotelpb.User, error) {
+	var user models.User
+	if err := grpcFind(db.DB.WithContext(ctx), &user, req.Id, "User not found", "Failed to retrieve user"); err != nil {
+		return nil, err
+	}
+	return userProto(user), nil
+}
+
+func (userService) CreateUser(ctx context.Context, req *hotelpb.CreateUserRequest) (*hotelpb.User, error) {
+	user := models.User{FirstName: req.FirstName, LastName: req.LastName, Email: req.Email, Locale: req.Locale}
+	if err := createUser(grpcRequest(ctx), &user); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return userProto(user), nil
+}
+
+func (userService) UpdateUser(ctx context.Context, req *hotelpb.UpdateUserRequest) (*hotelpb.User, error) {
+	var user models.User
+	if err := grpcFind(db.DB.WithContext(ctx), &user, req.Id, "User not found", "Failed to retrieve user"); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(req.Version, user.Version); err != nil {
+		return nil, err
+	}
+
+	fields := userFields{FirstName: req.FirstName, LastName: req.LastName, Email: req.Email}
+	if err := updateUser(grpcRequest(ctx), &user, fields); err != nil {
+		return nil, grpcSaveError(err, "Failed to update user")
+	}
+	return userProto(user), nil
+}
+
+func (userService) DeleteUser(ctx context.Context, req *hotelpb.DeleteUserRequest) (*emptypb.Empty, error) {
+	var user models.User
+	if err := grpcFind(db.DB.WithContext(ctx), &user, req.Id, "User not found", "Failed to retrieve user"); err != nil {
+		return nil, err
+	}
+	if err := deleteUser(grpcRequest(ctx), user); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to delete user")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// reservationService implementa hotelpb.ReservationServiceServer
+type reservationService struct {
+	hotelpb.UnimplementedReservationServiceServer
+}
+
+func (reservationService) ListReservations(ctx context.Context, req *hotelpb.ListReservationsRequest) (*hotelpb.ListReservationsResponse, error) {
+	size, afterID, err := grpcPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	conn := db.DB.WithContext(ctx)
+	if req.Status != "" {
+		conn = conn.Where("status = ?", req.Status)
+	}
+	if req.UserId != nil {
+		conn = conn.Where("user_id = ?", *req.UserId)
+	}
+	var reservations []models.Reservation
+	if err := grpcPageQuery(conn, size, afterID).Find(&reservations).Error; err != nil {
+		return nil, status.Error(codes.Internal, "Failed to retrieve reservations")
+	}
+
+	response := &hotelpb.ListReservationsResponse{}
+	for i, reservation := range reservations {
+		if i == size {
+			response.NextPageToken = nextPageToken(len(reservations), size, reservations[i-1].ID)
+			break
+		}
+		response.Reservations = append(response.Reservations, reservationProto(reservation))
+	}
+	return response, nil
+}
+
+func (reservationService) GetReservation(ctx context.Context, req *hotelpb.GetReservationRequest) (*hotelpb.Reservation, error) {
+	var reservation models.Reservation
+	if err := grpcFind(db.DB.WithContext(ctx), &reservation, req.Id, "Reservation not found", "Failed to retrieve reservation"); err != nil {
+		return nil, err
+	}
+	return reservationProto(reservation), nil
+}
+
+func (reservationService) CreateReservation(ctx context.Context, req *hotelpb.CreateReservationRequest) (*hotelpb.Reservation, error) {
+	reservation := models.Reservation{
+		Checkin:       req.CheckIn.AsTime(),
+		Checkout:      req.CheckOut.AsTime(),
+		Adults:        int(req.Adults),
+		Children:      int(req.Children),
+		NumberOfRooms: int(req.NumberOfRooms),
+		RoomType:      req.RoomType,
+		GuestName:     req.GuestName,
+		Email:         req.Email,
+		UserID:        uint(req.UserId),
+		RatePlanID:    modelID(req.RatePlanId),
+	}
+	err := createReservation(grpcRequest(ctx), &reservation)
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return reservationProto(reservation), nil
+}
+
+func (reservationService) UpdateReservation(ctx context.Context, req *hotelpb.UpdateReservationRequest) (*hotelpb.Reservation, error) {
+	var reservation models.Reservation
+	if err := grpcFind(db.DB.WithContext(ctx), &reservation, req.Id, "Reservation not found", "Failed to retrieve reservation"); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(req.Version, reservation.Version); err != nil {
+		return nil, err
+	}
+
+	fields := reservationFields{
+		Adults:        int(req.Adults),
+		Checkin:       req.CheckIn.AsTime(),
+		Checkout:      req.CheckOut.AsTime(),
+		Children:      int(req.Children),
+		Email:         req.Email,
+		NumberOfRooms: int(req.NumberOfRooms),
+		RoomType:      req.RoomType,
+		UserID:        uint(req.UserId),
+		RatePlanID:    modelID(req.RatePlanId),
+	}
+	err := updateReservation(grpcRequest(ctx), &reservation, fields)
+	if errors.Is(err, errInvalidStay) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, errRoomsAssigned) || errors.Is(err, errRoomTaken) || errors.Is(err, errRoomOutOfService) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, grpcSaveError(err, "Failed to update reservation")
+	}
+	return reservationProto(reservation), nil
+}
+
+func (reservationService) CancelReservation(ctx context.Context, req *hotelpb.CancelReservationRequest) (*hotelpb.CancelReservationResponse, error) {
+	var reservation models.Reservation
+	if err := grpcFind(db.DB.WithContext(ctx), &reservation, req.Id, "Reservation not found", "Failed to retrieve reservation"); err != nil {
+		return nil, err
+	}
+
+	quote, err := cancelReservation(grpcRequest(ctx), reservation)
+	if errors.Is(err, errAlreadyCancelled) {
+		return nil, status.Error(codes.FailedPrecondition, "Reservation already cancelled")
+	}
+	if errors.Is(err, errNotCancellable) {
+		return nil, status.Error(codes.FailedPrecondition, "Only tentative or confirmed reservations can be cancelled")
+	}
+	if err != nil {
+		return nil, grpcSaveError(err, "Failed to cancel reservation")
+	}
+	return &hotelpb.CancelReservationResponse{
+		Reservation: reservationProto(quote.Reservation),
+		Penalty:     quote.Penalty,
+		FreeUntil:   grpcTime(quote.FreeUntil),
+	}, nil
+}
+
+func (reservationService) ConfirmReservation(ctx context.Context, req *hotelpb.ConfirmReservationRequest) (*hotelpb.Reservation, error) {
+	var reservation models.Reservation
+	if err := grpcFind(db.DB.WithContext(ctx), &reservation, req.Id, "Reservation not found", "Failed to retrieve reservation"); err != nil {
+		return nil, err
+	}
+
+	reservation, err := confirmReservation(grpcRequest(ctx), reservation)
+	if errors.Is(err, errNotTentative) {
+		return nil, status.Error(codes.FailedPrecondition, "Only tentative reservations can be confirmed")
+	}
+	if err != nil {
+		return nil, grpcSaveError(err, "Failed to confirm reservation")
+	}
+	return reservationProto(reservation), nil
+}
+
+func (reservationService) DeleteReservation(ctx context.Context, req *hotelpb.DeleteReservationRequest) (*emptypb.Empty, error) {
+	var reservation models.Reservation
+	if err := grpcFind(db.DB.WithContext(ctx), &reservation, req.Id, "Reservation not found", "Failed to retrieve reservation"); err != nil {
+		return nil, err
+	}
+	if err := deleteReservation(grpcRequest(ctx), reservation); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to delete reservation")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// WatchReservations envía los eventos de dominio de las reservas a medida que se confirman. Los eventos
+// se leen de la bandeja de salida, así que el stream puede retomarse desde el último event_id recibido.
+// Requiere rol staff, como el tema reservations de GET /events/stream, porque incluye datos de los huéspedes
+func (reservationService) WatchReservations(req *hotelpb.WatchReservationsRequest, stream grpc.ServerStreamingServer[hotelpb.ReservationEvent]) error {
+	ctx := stream.Context()
+	if !middleware.PrincipalFromContext(ctx).HasRole(middleware.RoleStaff) {
+		return status.Error(codes.PermissionDenied, "Forbidden")
+	}
+	var afterID uint
+	if req.AfterEventId != nil {
+		afterID = uint(*req.AfterEventId)
+	} else {
+		last, err := events.LastID(db.DB.WithContext(ctx))
+		if err != nil {
+			return status.Error(codes.Internal, "Failed to retrieve events")
+		}
+		afterID = last
+	}
+
+	err := events.Follow(ctx, db.DB, afterID, []string{events.AggregateReservation}, func(event models.DomainEvent) error {
+		var reservation models.Reservation
+		if err := events.Decode(event, &reservation); err != nil {
+			return status.Error(codes.Internal, "Failed to decode event")
+		}
+		return stream.Send(&hotelpb.ReservationEvent{
+			EventId:     uint64(event.ID),
+			Type:        event.Type,
+			CreatedAt:   timestamppb.New(event.CreatedAt),
+			Reservation: reservationProto(reservation),
+		})
+	})
+	// El cliente cerró el stream
+	if ctx.Err() != nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); !ok {
+		return status.Error(codes.Internal, "Failed to retrieve events")
+	}
+	return err
+}
+
+// consultationService implementa hotelpb.ConsultationServiceServer
+type consultationService struct {
+	hotelpb.UnimplementedConsultationServiceServer
+}
+
+func (consultationService) ListConsultations(ctx context.Context, req *hotelpb.ListConsultationsRequest) (*hotelpb.ListConsultationsResponse, error) {
+	size, afterID, err := grpcPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	// Los mismos filtros que GET /consultations
+	query := url.Values{}
+	query.Set("status", req.Status)
+	if req.AssignedEmployeeId != nil {
+		query.Set("assigned_employee_id", strconv.FormatUint(*req.AssignedEmployeeId, 10))
+	}
+	query.Set("unassigned", strconv.FormatBool(req.Unassigned))
+	query.Set("overdue", strconv.FormatBool(req.Overdue))
+
+	var consultations []models.Consultation
+	if err := grpcPageQuery(filterConsultations(db.DB.WithContext(ctx), query), size, afterID).Find(&consultations).Error; err != nil {
+		return nil, status.Error(codes.Internal, "Failed to retrieve consultations")
+	}
+
+	response := &hotelpb.ListConsultationsResponse{}
+	for i, consultation := range consultations {
+		if i == size {
+			response.NextPageToken = nextPageToken(len(consultations), size, consultations[i-1].ID)
+			break
+		}
+		response.Consultations = append(response.Consultations, consultationProto(consultation))
+	}
+	return response, nil
+}
+
+func (consultationService) GetConsultation(ctx context.Context, req *hotelpb.GetConsultationRequest) (*hotelpb.Consultation, error) {
+	var consultation models.Consultation
+	conn := db.DB.WithContext(ctx).Preload("Messages", func(tx *gorm.DB) *gorm.DB { return tx.Order("id asc") })
+	if err := grpcFind(conn, &consultation, req.Id, "Consultation not found", "Failed to retrieve consultation"); err != nil {
+		return nil, err
+	}
+	return consultationProto(consultation), nil
+}
+
+func (consultationService) CreateConsultation(ctx context.Context, req *hotelpb.CreateConsultationRequest) (*hotelpb.Consultation, error) {
+	consultation := models.Consultation{Phone: req.Phone, Consultation: req.Consultation, MoreInfo: req.MoreInfo, UserID: uint(req.UserId)}
+	if err := createConsultation(grpcRequest(ctx), &consultation); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return consultationProto(consultation), nil
+}
+
+func (consultationService) UpdateConsultation(ctx context.Context, req *hotelpb.UpdateConsultationRequest) (*hotelpb.Consultation, error) {
+	var consultation models.Consultation
+	if err := grpcFind(db.DB.WithContext(ctx), &consultation, req.Id, "Consultation not found", "Failed to retrieve consultation"); err != nil {
+		return nil, err
+	}
+
+	updated := models.Consultation{Phone: req.Phone, Consultation: req.Consultation, MoreInfo: req.MoreInfo, UserID: uint(req.UserId)}
+	if err := updateConsultation(grpcRequest(ctx), &consultation, updated); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to update consultation")
+	}
+	return consultationProto(consultation), nil
+}
+
+func (consultationService) DeleteConsultation(ctx context.Context, req *hotelpb.DeleteConsultationRequest) (*emptypb.Empty, error) {
+	var consultation models.Consultation
+	if err := grpcFind(db.DB.WithContext(ctx), &consultation, req.Id, "Consultation not found", "Failed to retrieve consultation"); err != nil {
+		return nil, err
+	}
+	if err := deleteConsultation(grpcRequest(ctx), consultation); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to delete consultation")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// employeeService implementa hotelpb.EmployeeServiceServer
+type employeeService struct {
+	hotelpb.UnimplementedEmployeeServiceServer
+}
+
+func (employeeService) ListEmployees(ctx context.Context, req *hotelpb.ListEmployeesRequest) (*hotelpb.ListEmployeesResponse, error) {
+	size, afterID, err := grpcPage(req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	var employees []models.Employee
+	if err := grpcPageQuery(db.DB.WithContext(ctx), size, afterID).Find(&employees).Error; err != nil {
+		return nil, status.Error(codes.Internal, "Failed to retrieve employees")
+	}
+
+	response := &hotelpb.ListEmployeesResponse{}
+	for i, employee := range employees {
+		if i == size {
+			response.NextPageToken = nextPageToken(len(employees), size, employeeID(employees[i-1]))
+			break
+		}
+		response.Employees = append(response.Employees, employeeProto(ctx, employee))
+	}
+	return response, nil
+}
+
+func (employeeService) GetEmployee(ctx context.Context, req *hotelpb.GetEmployeeRequest) (*hotelpb.Employee, error) {
+	var employee models.Employee
+	if err := grpcFind(db.DB.WithContext(ctx), &employee, req.Id, "Employee not found", "Failed to retrieve employee"); err != nil {
+		return nil, err
+	}
+	return employeeProto(ctx, employee), nil
+}
+
+func (employeeService) CreateEmployee(ctx context.Context, req *hotelpb.CreateEmployeeRequest) (*hotelpb.Employee, error) {
+	employee := models.Employee{
+		User:        &models.User{FirstName: req.FirstName, LastName: req.LastName, Email: req.Email},
+		Position:    req.Position,
+		Salary:      req.Salary,
+		Department:  req.Department,
+		HireDate:    req.HireDate,
+		PhoneNumber: req.PhoneNumber,
+	}
+	if err := createEmployee(grpcRequest(ctx), &employee); err != nil {
+		if errors.Is(err, errSalaryRequiresManager) {
+			return nil, status.Error(codes.PermissionDenied, "Only managers can set the salary")
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return employeeProto(ctx, employee), nil
+}
+
+func (employeeService) UpdateEmployee(ctx context.Context, req *hotelpb.UpdateEmployeeRequest) (*hotelpb.Employee, error) {
+	var employee models.Employee
+	if err := grpcFind(db.DB.WithContext(ctx), &employee, req.Id, "Employee not found", "Failed to retrieve employee"); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(req.Version, *employeeVersion(&employee)); err != nil {
+		return nil, err
+	}
+
+	fields := employeeFields{
+		Position:    req.Position,
+		Department:  req.Department,
+		HireDate:    req.HireDate,
+		PhoneNumber: req.PhoneNumber,
+	}
+	// Un salario en cero conserva el actual, porque los roles que no lo reciben no pueden reenviarlo
+	if req.Salary != 0 {
+		fields.Salary = &req.Salary
+	}
+	fields.User.FirstName = req.FirstName
+	fields.User.LastName = req.LastName
+	fields.User.Email = req.Email
+	if err := updateEmployee(grpcRequest(ctx), &employee, fields); err != nil {
+		if errors.Is(err, errSalaryRequiresManager) {
+			return nil, status.Error(codes.PermissionDenied, "Only managers can set the salary")
+		}
+		return nil, grpcSaveError(err, "Failed to update employee")
+	}
+	return employeeProto(ctx, employee), nil
+}
+
+func (employeeService) DeleteEmployee(ctx context.Context, req *hotelpb.DeleteEmployeeRequest) (*emptypb.Empty, error) {
+	var employee models.Employee
+	if err := grpcFind(db.DB.WithContext(ctx), &employee, req.Id, "Employee not found", "Failed to retrieve employee"); err != nil {
+		return nil, err
+	}
+	if err := deleteEmployee(grpcRequest(ctx), employee); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
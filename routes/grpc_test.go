@@ -0,0 +1,156 @@
+package routes
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/hotelpb"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Levanta el servidor gRPC en memoria y devuelve una conexión de cliente
+func setupGRPC(t *testing.T) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(middleware.GRPCAuth(middleware.ParseTokens("staff-token:staff:bob"))...)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCRequests(t *testing.T) {
+	conn := setupGRPC(t)
+	users := hotelpb.NewUserServiceClient(conn)
+	ctx := context.Background()
+
+	// Un token desconocido se rechaza antes de llegar al servicio
+	_, err := users.ListUsers(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong"), &hotelpb.ListUsersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "Invalid credentials", status.Convert(err).Message())
+
+	// Los parámetros de paginación se validan sin tocar la base de datos
+	_, err = users.ListUsers(ctx, &hotelpb.ListUsersRequest{PageSize: 500})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "page_size must be between 0 and 100", status.Convert(err).Message())
+	_, err = users.ListUsers(ctx, &hotelpb.ListUsersRequest{PageToken: "next"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// Los cambios de las reservas incluyen datos de los huéspedes: sin rol staff no se reciben
+	stream, err := hotelpb.NewReservationServiceClient(conn).WatchReservations(ctx, &hotelpb.WatchReservationsRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestGRPCReservations(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.User{}, &models.CancellationPolicy{}, &models.RatePlan{}, &models.RoomType{}, &models.GroupBlock{}, &models.GroupAllotment{},
+		&models.Reservation{}, &models.Folio{}, &models.FolioEntry{}, &models.AuditLog{}, &models.DomainEvent{})
+	defer cleanUpDB()
+	defer func(interval time.Duration) { events.FollowInterval = interval }(events.FollowInterval)
+	events.FollowInterval = 10 * time.Millisecond
+
+	conn := setupGRPC(t)
+	users := hotelpb.NewUserServiceClient(conn)
+	reservations := hotelpb.NewReservationServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer staff-token")
+
+	// Seguir los cambios de las reservas desde antes de crear la reserva
+	lastID, err := events.LastID(db.DB)
+	assert.NoError(t, err)
+	watchCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	after := uint64(lastID)
+	stream, err := reservations.WatchReservations(watchCtx, &hotelpb.WatchReservationsRequest{AfterEventId: &after})
+	assert.NoError(t, err)
+
+	user, err := users.CreateUser(ctx, &hotelpb.CreateUserRequest{FirstName: "Grace", LastName: "Hopper", Email: "grace.hopper@example.com"})
+	assert.NoError(t, err)
+	checkin := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	reservation, err := reservations.CreateReservation(ctx, &hotelpb.CreateReservationRequest{
+		CheckIn:       timestamppb.New(checkin),
+		CheckOut:      timestamppb.New(checkin.AddDate(0, 0, 2)),
+		Adults:        2,
+		NumberOfRooms: 1,
+		RoomType:      "double",
+		GuestName:     "Grace Hopper",
+		Email:         user.Email,
+		UserId:        user.Id,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationConfirmed, reservation.Status)
+
+	// Una escritura sobre una versión vieja se rechaza como con If-Match
+	_, err = reservations.UpdateReservation(ctx, &hotelpb.UpdateReservationRequest{Id: reservation.Id, Version: reservation.Version + 1})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	cancelled, err := reservations.CancelReservation(ctx, &hotelpb.CancelReservationRequest{Id: reservation.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationCancelled, cancelled.Reservation.Status)
+	_, err = reservations.CancelReservation(ctx, &hotelpb.CancelReservationRequest{Id: reservation.Id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = reservations.DeleteReservation(ctx, &hotelpb.DeleteReservationRequest{Id: reservation.Id})
+	assert.NoError(t, err)
+
+	// El stream recibe el alta, la cancelación y la eliminación en orden
+	for _, expected := range []string{events.ReservationCreated, events.ReservationCancelled, events.ReservationDeleted} {
+		event, err := stream.Recv()
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, event.Type)
+			assert.Equal(t, reservation.Id, event.Reservation.Id)
+		}
+	}
+
+	// La auditoría registra al principal de la llamada gRPC
+	var entry models.AuditLog
+	db.DB.Where("resource_type = ? AND resource_id = ?", audit.ResourceReservation, reservation.Id).Order("id asc").First(&entry)
+	assert.Equal(t, "bob", entry.Actor)
+
+	_, err = reservations.GetReservation(ctx, &hotelpb.GetReservationRequest{Id: reservation.Id + 1000})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "Reservation not found", status.Convert(err).Message())
+}
+
+func TestGRPCEmployeeSalaryRequiresManager(t *testing.T) {
+	employee := models.Employee{User: &models.User{}, Salary: 1500}
+	salary := func(token string) *float64 {
+		var result *hotelpb.Employee
+		handler := middleware.AuthWithTokens(middleware.ParseTokens("staff-token:staff:bob,manager-token:manager:alice"),
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				result = employeeProto(r.Context(), employee)
+			}))
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return result.Salary
+	}
+
+	assert.Nil(t, salary("staff-token"))
+	if value := salary("manager-token"); assert.NotNil(t, value) {
+		assert.Equal(t, 1500.0, *value)
+	}
+}
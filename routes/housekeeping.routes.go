@@ -0,0 +1,385 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/housekeeping"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// Errores de negocio del check-in y check-out
+var (
+	errNoRoomAssigned = errors.New("reservation has no room assigned for today")
+	errRoomNotReady   = errors.New("assigned room is not clean")
+)
+
+// validHousekeepingStatus indica si el estado de limpieza es uno de los soportados
+func validHousekeepingStatus(status string) bool {
+	switch status {
+	case models.RoomDirty, models.RoomClean, models.RoomInspected, models.RoomOutOfOrder:
+		return true
+	}
+	return false
+}
+
+// setRoomHousekeeping cambia el estado de limpieza de una habitación, lo registra en la auditoría y
+// publica RoomStatusChanged
+func setRoomHousekeeping(tx *gorm.DB, r *http.Request, roomID uint, status string) error {
+	var room models.Room
+	if err := tx.First(&room, roomID).Error; err != nil {
+		return err
+	}
+	if room.Housekeeping == status {
+		return nil
+	}
+	before := room
+	room.Housekeeping = status
+	if err := tx.Model(&room).Update("housekeeping", status).Error; err != nil {
+		return err
+	}
+	if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoom, room.ID, &before, &room); err != nil {
+		return err
+	}
+	return events.PublishRoom(tx, events.RoomStatusChanged, room)
+}
+
+// currentAssignments devuelve los tramos de la reserva que están en curso en la fecha indicada
+func currentAssignments(tx *gorm.DB, reservationID uint, date time.Time) ([]models.RoomAssignment, error) {
+	var assignments []models.RoomAssignment
+	err := tx.Preload("Room").
+		Where("reservation_id = ? AND start_date <= ? AND end_date >= ?", reservationID, date, date).
+		Order("id asc").Find(&assignments).Error
+	return assignments, err
+}
+
+// occupiedAssignments devuelve los tramos que el huésped ocupa al salir: los que siguen en curso en la
+// fecha indicada o, si se va después del fin previsto, los últimos tramos de la estancia aunque ya hayan
+// terminado. Los tramos que dejó por un cambio de habitación no se incluyen
+func occupiedAssignments(tx *gorm.DB, reservationID uint, date time.Time) ([]models.RoomAssignment, error) {
+	var started []models.RoomAssignment
+	if err := tx.Preload("Room").Where("reservation_id = ? AND start_date <= ?", reservationID, date).
+		Order("id asc").Find(&started).Error; err != nil {
+		return nil, err
+	}
+	return lastOccupied(started, date), nil
+}
+
+// lastOccupied filtra los tramos empezados que siguen ocupados en la fecha, o los últimos de la estancia
+func lastOccupied(started []models.RoomAssignment, date time.Time) []models.RoomAssignment {
+	var last time.Time
+	for _, assignment := range started {
+		if assignment.EndDate.After(last) {
+			last = assignment.EndDate
+		}
+	}
+	if last.After(date) {
+		last = date
+	}
+	var occupied []models.RoomAssignment
+	for _, assignment := range started {
+		if !assignment.EndDate.Before(last) {
+			occupied = append(occupied, assignment)
+		}
+	}
+	return occupied
+}
+
+// CheckInReservationHandler registra la llegada del huésped. La reserva debe tener habitación asignada
+// para hoy y la habitación tiene que estar limpia o inspeccionada
+func CheckInReservationHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+	if reservation.Status != models.ReservationConfirmed {
+		http.Error(w, "Reservation cannot be checked in from status "+reservation.Status, http.StatusConflict)
+		return
+	}
+
+	now := time.Now()
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		assignments, err := currentAssignments(tx, reservation.ID, inventory.Day(now))
+		if err != nil {
+			return err
+		}
+		if len(assignments) == 0 {
+			return errNoRoomAssigned
+		}
+		for _, assignment := range assignments {
+			if assignment.Room.Housekeeping != models.RoomClean && assignment.Room.Housekeeping != models.RoomInspected {
+				return errRoomNotReady
+			}
+		}
+
+		before := reservation
+		reservation.Status = models.ReservationCheckedIn
+		reservation.CheckedInAt = &now
+		if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+			return err
+		}
+		return events.PublishReservation(tx, events.GuestCheckedIn, reservation)
+	})
+	if errors.Is(err, errNoRoomAssigned) || errors.Is(err, errRoomNotReady) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeSaveError(w, err, "Failed to check in reservation")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CheckOutReservationHandler registra la salida del huésped y marca sus habitaciones como sucias,
+// también si se va después del fin previsto del tramo. Si se va antes de lo previsto el tramo termina
+// hoy y la habitación queda libre
+func CheckOutReservationHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+	if reservation.Status != models.ReservationCheckedIn {
+		http.Error(w, "Reservation cannot be checked out from status "+reservation.Status, http.StatusConflict)
+		return
+	}
+
+	now := time.Now()
+	today := inventory.Day(now)
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		assignments, err := occupiedAssignments(tx, reservation.ID, today)
+		if err != nil {
+			return err
+		}
+		for _, assignment := range assignments {
+			if assignment.EndDate.After(today) && assignment.StartDate.Before(today) {
+				before := assignment
+				assignment.EndDate = today
+				if err := tx.Model(&assignment).Update("end_date", today).Error; err != nil {
+					return err
+				}
+				if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoomAssignment, assignment.ID, &before, &assignment); err != nil {
+					return err
+				}
+			}
+			if err := setRoomHousekeeping(tx, r, assignment.RoomID, models.RoomDirty); err != nil {
+				return err
+			}
+		}
+
+		before := reservation
+		reservation.Status = models.ReservationCheckedOut
+		reservation.CheckedOutAt = &now
+		if err := saveVersioned(tx, &reservation, &reservation.Version); err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, &reservation); err != nil {
+			return err
+		}
+		return events.PublishReservation(tx, events.GuestCheckedOut, reservation)
+	})
+	if err != nil {
+		writeSaveError(w, err, "Failed to check out reservation")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// housekeepingUpdate es el nuevo estado de limpieza de una habitación
+type housekeepingUpdate struct {
+	Housekeeping string `json:"housekeeping"`
+}
+
+// UpdateRoomHousekeepingHandler cambia el estado de limpieza de una habitación
+func UpdateRoomHousekeepingHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var room models.Room
+	if err := db.DB.First(&room, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve room", http.StatusInternalServerError)
+		return
+	}
+
+	var body housekeepingUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !validHousekeepingStatus(body.Housekeeping) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		return setRoomHousekeeping(tx, r, room.ID, body.Housekeeping)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update room", http.StatusInternalServerError)
+		return
+	}
+	room.Housekeeping = body.Housekeeping
+
+	if err := json.NewEncoder(w).Encode(&room); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetHousekeepingTasksHandler obtiene las tareas de limpieza del día (por defecto hoy), con filtros
+// opcionales por employee_id y status
+func GetHousekeepingTasksHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	date := inventory.Day(time.Now())
+	if value := query.Get("date"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			http.Error(w, "Invalid date", http.StatusBadRequest)
+			return
+		}
+		date = inventory.Day(parsed)
+	}
+
+	conn := db.DB.Preload("Room").Where("date = ?", date)
+	if employeeID := query.Get("employee_id"); employeeID != "" {
+		conn = conn.Where("employee_id = ?", employeeID)
+	}
+	if status := query.Get("status"); status != "" {
+		conn = conn.Where("status = ?", status)
+	}
+
+	var tasks []models.HousekeepingTask
+	if err := conn.Order("id asc").Find(&tasks).Error; err != nil {
+		http.Error(w, "Failed to retrieve housekeeping tasks", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&tasks); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GenerateHousekeepingTasksHandler genera las tareas de limpieza del día indicado (por defecto hoy)
+func GenerateHousekeepingTasksHandler(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			http.Error(w, "Invalid date", http.StatusBadRequest)
+			return
+		}
+		date = parsed
+	}
+
+	var tasks []models.HousekeepingTask
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tasks, err = housekeeping.Generate(tx, date)
+		return err
+	})
+	if err != nil {
+		http.Error(w, "Failed to generate housekeeping tasks", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&tasks); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// housekeepingTaskUpdate es el avance de una tarea de limpieza; los campos omitidos se conservan
+type housekeepingTaskUpdate struct {
+	Status     string  `json:"status"`
+	Notes      *string `json:"notes"`
+	EmployeeID *uint   `json:"employee_id"`
+}
+
+// UpdateHousekeepingTaskHandler registra el avance de una tarea de limpieza. Al terminarla la
+// habitación queda limpia
+func UpdateHousekeepingTaskHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var task models.HousekeepingTask
+	if err := db.DB.First(&task, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Housekeeping task not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve housekeeping task", http.StatusInternalServerError)
+		return
+	}
+
+	var body housekeepingTaskUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if body.Status != "" && body.Status != models.TaskPending && body.Status != models.TaskInProgress && body.Status != models.TaskDone {
+		http.Error(w, "Invalid task status", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	before := task
+	if body.Notes != nil {
+		task.Notes = *body.Notes
+	}
+	if body.EmployeeID != nil {
+		task.EmployeeID = body.EmployeeID
+	}
+	if body.Status != "" && body.Status != task.Status {
+		task.Status = body.Status
+		switch body.Status {
+		case models.TaskInProgress:
+			task.StartedAt = &now
+		case models.TaskDone:
+			if task.StartedAt == nil {
+				task.StartedAt = &now
+			}
+			task.CompletedAt = &now
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceHousekeepingTask, task.ID, &before, &task); err != nil {
+			return err
+		}
+		// Una habitación fuera de servicio sigue así aunque se limpie
+		if task.Status == models.TaskDone && before.Status != models.TaskDone {
+			var room models.Room
+			if err := tx.First(&room, task.RoomID).Error; err != nil {
+				return err
+			}
+			if room.Housekeeping != models.RoomOutOfOrder {
+				return setRoomHousekeeping(tx, r, room.ID, models.RoomClean)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, "Failed to update housekeeping task", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&task); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
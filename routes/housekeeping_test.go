@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastOccupied(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2030, 6, d, 0, 0, 0, 0, time.UTC) }
+	// El huésped cambió de la 101 a la 102 el día 3; la estancia terminaba el día 5
+	moved := models.RoomAssignment{ID: 1, RoomID: 101, StartDate: day(1), EndDate: day(3)}
+	current := models.RoomAssignment{ID: 2, RoomID: 102, StartDate: day(3), EndDate: day(5)}
+	started := []models.RoomAssignment{moved, current}
+
+	assert.Equal(t, []models.RoomAssignment{current}, lastOccupied(started, day(4)))
+	assert.Equal(t, []models.RoomAssignment{current}, lastOccupied(started, day(5)))
+	// Una salida tardía sigue ensuciando la habitación que ocupaba, no la que dejó
+	assert.Equal(t, []models.RoomAssignment{current}, lastOccupied(started, day(7)))
+	assert.Empty(t, lastOccupied(nil, day(7)))
+}
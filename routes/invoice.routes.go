@@ -0,0 +1,238 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/invoices"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errNothingToInvoice indica que el folio no tiene cargos pendientes de facturar
+var errNothingToInvoice = errors.New("no uninvoiced charges in folio")
+
+// issueInvoice emite una factura con todos los cargos del folio que todavía no fueron facturados
+func issueInvoice(tx *gorm.DB, r *http.Request, reservation models.Reservation) (models.Invoice, error) {
+	hotel := invoices.LoadHotel()
+	invoice := models.Invoice{
+		Kind:          models.InvoiceKindInvoice,
+		ReservationID: reservation.ID,
+		GuestEmail:    reservation.Email,
+		Currency:      hotel.Currency,
+	}
+
+	folio, err := findOrCreateFolio(tx, reservation.ID)
+	if err != nil {
+		return invoice, err
+	}
+	invoice.FolioID = folio.ID
+	// Bloquear el folio hasta el final de la transacción para que dos facturas simultáneas no incluyan
+	// los mismos cargos
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&folio, folio.ID).Error; err != nil {
+		return invoice, err
+	}
+
+	var charges []models.FolioEntry
+	if err := tx.Order("id asc").
+		Where("folio_id = ? AND type = ? AND invoice_id IS NULL", folio.ID, models.FolioEntryCharge).
+		Find(&charges).Error; err != nil {
+		return invoice, err
+	}
+	if len(charges) == 0 {
+		return invoice, errNothingToInvoice
+	}
+
+	// Los datos del huésped se toman del usuario titular de la reserva
+	var user models.User
+	if err := tx.First(&user, reservation.UserID).Error; err == nil {
+		invoice.GuestName = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		invoice.GuestEmail = user.Email
+	}
+
+	for _, charge := range charges {
+		line := invoices.NewLine(charge.Description, charge.Amount, charge.TaxRate)
+		id := charge.ID
+		line.FolioEntryID = &id
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	if err := invoices.Issue(tx, &invoice); err != nil {
+		return invoice, err
+	}
+
+	// Marcar los cargos como facturados para no incluirlos en otra factura
+	ids := make([]uint, len(charges))
+	for i, charge := range charges {
+		ids[i] = charge.ID
+	}
+	if err := tx.Model(&models.FolioEntry{}).Where("id IN ?", ids).Update("invoice_id", invoice.ID).Error; err != nil {
+		return invoice, err
+	}
+	return invoice, audit.Record(tx, r, audit.ActionCreate, audit.ResourceInvoice, invoice.ID, nil, &invoice)
+}
+
+// issueCreditNote emite una nota de crédito por un reembolso si la reserva ya fue facturada
+func issueCreditNote(tx *gorm.DB, r *http.Request, payment models.Payment, amount float64) error {
+	var last models.Invoice
+	err := tx.Where("reservation_id = ? AND kind = ?", payment.ReservationID, models.InvoiceKindInvoice).
+		Order("id desc").First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Sin factura previa no corresponde emitir nota de crédito
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	hotel := invoices.LoadHotel()
+	paymentID := payment.ID
+	creditNote := models.Invoice{
+		Kind:          models.InvoiceKindCreditNote,
+		ReservationID: payment.ReservationID,
+		FolioID:       payment.FolioID,
+		PaymentID:     &paymentID,
+		GuestName:     last.GuestName,
+		GuestEmail:    last.GuestEmail,
+		Currency:      last.Currency,
+		Lines: []models.InvoiceLine{
+			invoices.NewLine(fmt.Sprintf("Reembolso del pago %s (factura %s)", payment.ProviderRef, last.Number), amount, hotel.TaxRate),
+		},
+	}
+	if err := invoices.Issue(tx, &creditNote); err != nil {
+		return err
+	}
+	return audit.Record(tx, r, audit.ActionCreate, audit.ResourceInvoice, creditNote.ID, nil, &creditNote)
+}
+
+// GetReservationInvoicesHandler devuelve las facturas y notas de crédito de una reserva
+func GetReservationInvoicesHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var invoiceList []models.Invoice
+	if err := db.DB.Preload("Lines").Order("id asc").Where("reservation_id = ?", params["id"]).Find(&invoiceList).Error; err != nil {
+		http.Error(w, "Failed to retrieve invoices", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&invoiceList); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// PostReservationInvoiceHandler emite una factura con los cargos pendientes de facturar
+func PostReservationInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var invoice models.Invoice
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		invoice, err = issueInvoice(tx, r, reservation)
+		return err
+	})
+	if errors.Is(err, errNothingToInvoice) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to issue invoice", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&invoice); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// reservationInvoice devuelve la última factura de la reserva y responde 404 si todavía no se emitió
+// ninguna. Es de solo lectura: las facturas se emiten con POST /reservations/{id}/invoices
+func reservationInvoice(w http.ResponseWriter, r *http.Request) (models.Invoice, bool) {
+	var invoice models.Invoice
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return invoice, false
+	}
+
+	err := db.DB.Preload("Lines").Where("reservation_id = ? AND kind = ?", reservation.ID, models.InvoiceKindInvoice).
+		Order("id desc").First(&invoice).Error
+	if err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Invoice not found"))
+			return invoice, false
+		}
+		http.Error(w, "Failed to retrieve invoice", http.StatusInternalServerError)
+		return invoice, false
+	}
+	return invoice, true
+}
+
+// findInvoice busca un comprobante por el ID de la URL y responde 404 si no existe
+func findInvoice(w http.ResponseWriter, r *http.Request) (models.Invoice, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var invoice models.Invoice
+	if err := db.DB.Preload("Lines").First(&invoice, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Invoice not found"))
+			return invoice, false
+		}
+		http.Error(w, "Failed to retrieve invoice", http.StatusInternalServerError)
+		return invoice, false
+	}
+	return invoice, true
+}
+
+// writeInvoicePDF envía el comprobante como PDF
+func writeInvoicePDF(w http.ResponseWriter, invoice models.Invoice) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", invoice.Number+".pdf"))
+	if err := invoices.RenderPDF(w, invoices.LoadHotel(), invoice); err != nil {
+		http.Error(w, "Failed to render invoice", http.StatusInternalServerError)
+	}
+}
+
+// writeInvoiceHTML envía el comprobante como página HTML
+func writeInvoiceHTML(w http.ResponseWriter, invoice models.Invoice) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := invoices.RenderHTML(w, invoices.LoadHotel(), invoice); err != nil {
+		http.Error(w, "Failed to render invoice", http.StatusInternalServerError)
+	}
+}
+
+// GetReservationInvoicePDFHandler devuelve la factura de la reserva en PDF
+func GetReservationInvoicePDFHandler(w http.ResponseWriter, r *http.Request) {
+	if invoice, ok := reservationInvoice(w, r); ok {
+		writeInvoicePDF(w, invoice)
+	}
+}
+
+// GetReservationInvoiceHTMLHandler devuelve la factura de la reserva en HTML
+func GetReservationInvoiceHTMLHandler(w http.ResponseWriter, r *http.Request) {
+	if invoice, ok := reservationInvoice(w, r); ok {
+		writeInvoiceHTML(w, invoice)
+	}
+}
+
+// GetInvoicePDFHandler devuelve una factura o nota de crédito en PDF
+func GetInvoicePDFHandler(w http.ResponseWriter, r *http.Request) {
+	if invoice, ok := findInvoice(w, r); ok {
+		writeInvoicePDF(w, invoice)
+	}
+}
+
+// GetInvoiceHTMLHandler devuelve una factura o nota de crédito en HTML
+func GetInvoiceHTMLHandler(w http.ResponseWriter, r *http.Request) {
+	if invoice, ok := findInvoice(w, r); ok {
+		writeInvoiceHTML(w, invoice)
+	}
+}
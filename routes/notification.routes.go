@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// GetNotificationsHandler obtiene las notificaciones de la bandeja de salida, las más recientes primero,
+// con filtros opcionales por status, kind y recipient
+func GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Order("id desc").Limit(100)
+	query := r.URL.Query()
+	for _, param := range []string{"status", "kind", "recipient"} {
+		if value := query.Get(param); value != "" {
+			conn = conn.Where(param+" = ?", value)
+		}
+	}
+
+	var notifications []models.Notification
+	if err := conn.Find(&notifications).Error; err != nil {
+		http.Error(w, "Failed to retrieve notifications", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&notifications); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// RetryNotificationHandler vuelve a encolar una notificación fallida para que se reintente su entrega
+func RetryNotificationHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var notification models.Notification
+	if err := db.DB.First(&notification, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Notification not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve notification", http.StatusInternalServerError)
+		return
+	}
+	if notification.Status != models.NotificationFailed {
+		http.Error(w, "Only failed notifications can be retried", http.StatusConflict)
+		return
+	}
+
+	before := notification
+	notification.Status = models.NotificationPending
+	notification.Attempts = 0
+	notification.NextAttemptAt = time.Now()
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&notification).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceNotification, notification.ID, &before, &notification)
+	})
+	if err != nil {
+		http.Error(w, "Failed to retry notification", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&notification); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
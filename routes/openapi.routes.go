@@ -0,0 +1,280 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/channels"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/graph"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/openapi"
+	"github.com/graphql-go/graphql"
+)
+
+// Parámetros de consulta compartidos por varias rutas
+var (
+	includeDeletedParam = openapi.Query("include_deleted", openapi.Boolean, "Incluir los registros eliminados (solo admin)")
+	fromDateParam       = openapi.Query("from", openapi.Date, "Fecha inicial")
+	toDateParam         = openapi.Query("to", openapi.Date, "Fecha final")
+	statusParam         = openapi.Query("status", openapi.String, "Filtrar por estado")
+	exportFormatParam   = openapi.Query("format", openapi.Enum("csv", "xlsx"), "Formato del archivo (csv por defecto)")
+)
+
+// apiRoutes documenta cada ruta registrada en NewRouter con sus parámetros, cuerpos y rol requerido
+var apiRoutes = []openapi.Route{
+	// User
+	{Method: "GET", Path: "/users", Tag: "Users", Summary: "Listar usuarios", Query: []openapi.Parameter{includeDeletedParam}, Response: []models.User{}},
+	{Method: "GET", Path: "/users/{id}", Tag: "Users", Summary: "Obtener un usuario con sus reservas y consultas", Query: []openapi.Parameter{includeDeletedParam}, Response: models.User{}},
+	{Method: "POST", Path: "/users", Tag: "Users", Summary: "Crear un usuario", Request: models.User{}, Response: models.User{}},
+	{Method: "PUT", Path: "/users/{id}", Tag: "Users", Summary: "Reemplazar un usuario (admite If-Match)", Request: models.User{}, Response: models.User{}},
+	{Method: "PATCH", Path: "/users/{id}", Tag: "Users", Summary: "Modificar un usuario con JSON Merge Patch", Request: userFields{}, RequestType: "application/merge-patch+json", Response: models.User{}},
+	{Method: "DELETE", Path: "/users/{id}", Tag: "Users", Summary: "Eliminar un usuario"},
+	{Method: "POST", Path: "/users/{id}/restore", Tag: "Users", Summary: "Recuperar un usuario eliminado", Role: middleware.RoleAdmin, Response: models.User{}},
+
+	// Reservation
+	{Method: "GET", Path: "/reservations", Tag: "Reservations", Summary: "Listar reservas", Query: []openapi.Parameter{includeDeletedParam}, Response: []models.Reservation{}},
+	{Method: "GET", Path: "/reservations/{id}", Tag: "Reservations", Summary: "Obtener una reserva", Query: []openapi.Parameter{includeDeletedParam}, Response: models.Reservation{}},
+	{Method: "POST", Path: "/reservations", Tag: "Reservations", Summary: "Crear una reserva", Request: newReservationFields{}, Response: models.Reservation{}},
+	{Method: "PUT", Path: "/reservations/{id}", Tag: "Reservations", Summary: "Reemplazar una reserva (admite If-Match)", Request: models.Reservation{}, Response: models.Reservation{}},
+	{Method: "PATCH", Path: "/reservations/{id}", Tag: "Reservations", Summary: "Modificar una reserva con JSON Merge Patch", Request: reservationFields{}, RequestType: "application/merge-patch+json", Response: models.Reservation{}},
+	{Method: "DELETE", Path: "/reservations/{id}", Tag: "Reservations", Summary: "Eliminar una reserva"},
+	{Method: "POST", Path: "/reservations/{id}/restore", Tag: "Reservations", Summary: "Recuperar una reserva eliminada", Role: middleware.RoleAdmin, Response: models.Reservation{}},
+	{Method: "GET", Path: "/reservations/{id}/cancellation", Tag: "Reservations", Summary: "Calcular la penalización por cancelar", Response: cancellationQuote{}},
+	{Method: "POST", Path: "/reservations/{id}/cancel", Tag: "Reservations", Summary: "Cancelar una reserva cobrando la penalización", Response: cancellationQuote{}},
+	{Method: "POST", Path: "/reservations/{id}/confirm", Tag: "Reservations", Summary: "Confirmar una reserva tentativa", Response: models.Reservation{}},
+
+	// RatePlan y CancellationPolicy
+	{Method: "GET", Path: "/rate-plans", Tag: "Rates", Summary: "Listar tarifas", Response: []models.RatePlan{}},
+	{Method: "POST", Path: "/rate-plans", Tag: "Rates", Summary: "Crear una tarifa", Request: models.RatePlan{}, Response: models.RatePlan{}},
+	{Method: "PUT", Path: "/rate-plans/{id}", Tag: "Rates", Summary: "Modificar una tarifa", Request: models.RatePlan{}, Response: models.RatePlan{}},
+	{Method: "GET", Path: "/cancellation-policies", Tag: "Rates", Summary: "Listar políticas de cancelación", Response: []models.CancellationPolicy{}},
+	{Method: "POST", Path: "/cancellation-policies", Tag: "Rates", Summary: "Crear una política de cancelación", Request: models.CancellationPolicy{}, Response: models.CancellationPolicy{}},
+	{Method: "PUT", Path: "/cancellation-policies/{id}", Tag: "Rates", Summary: "Modificar una política de cancelación", Request: models.CancellationPolicy{}, Response: models.CancellationPolicy{}},
+
+	// RoomType e inventario
+	{Method: "GET", Path: "/room-types", Tag: "Inventory", Summary: "Listar tipos de habitación", Response: []models.RoomType{}},
+	{Method: "POST", Path: "/room-types", Tag: "Inventory", Summary: "Crear un tipo de habitación", Request: models.RoomType{}, Response: models.RoomType{}},
+	{Method: "PUT", Path: "/room-types/{id}", Tag: "Inventory", Summary: "Modificar un tipo de habitación", Request: models.RoomType{}, Response: models.RoomType{}},
+	{Method: "GET", Path: "/availability", Tag: "Inventory", Summary: "Consultar la disponibilidad de un tipo de habitación", Query: []openapi.Parameter{
+		openapi.RequiredQuery("room_type", openapi.String, "Código del tipo de habitación"),
+		openapi.RequiredQuery("from", openapi.Date, "Fecha de llegada"),
+		openapi.RequiredQuery("to", openapi.Date, "Fecha de salida"),
+	}, Response: availabilityResult{}},
+	{Method: "GET", Path: "/room-types/{id}/calendar.ics", Tag: "Inventory", Summary: "Exportar las fechas ocupadas en formato iCalendar", Query: []openapi.Parameter{
+		openapi.Query("exclude_source", openapi.String, "Omitir las reservas importadas de este calendario"),
+	}, Response: "", ContentType: "text/calendar"},
+	{Method: "POST", Path: "/room-types/{id}/calendar/import", Tag: "Inventory", Summary: "Importar un calendario .ics externo", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		openapi.Query("source", openapi.String, "Nombre del calendario externo (por defecto ical)"),
+	}, Request: "", RequestType: "text/calendar", Response: calendarImportResult{}},
+
+	// Room y asignación de habitaciones
+	{Method: "GET", Path: "/rooms", Tag: "Rooms", Summary: "Listar habitaciones", Query: []openapi.Parameter{
+		openapi.Query("room_type", openapi.String, "Filtrar por tipo de habitación"), statusParam,
+	}, Response: []models.Room{}},
+	{Method: "POST", Path: "/rooms", Tag: "Rooms", Summary: "Crear una habitación", Request: models.Room{}, Response: models.Room{}},
+	{Method: "PUT", Path: "/rooms/{id}", Tag: "Rooms", Summary: "Modificar una habitación", Request: models.Room{}, Response: models.Room{}},
+	{Method: "GET", Path: "/room-suggestions", Tag: "Rooms", Summary: "Sugerir habitaciones para las llegadas sin asignar", Query: []openapi.Parameter{
+		openapi.Query("date", openapi.Date, "Fecha de llegada (por defecto mañana)"),
+	}, Response: []roomSuggestion{}},
+	{Method: "GET", Path: "/reservations/{id}/rooms", Tag: "Rooms", Summary: "Listar las habitaciones asignadas a una reserva", Response: []models.RoomAssignment{}},
+	{Method: "POST", Path: "/reservations/{id}/rooms", Tag: "Rooms", Summary: "Asignar una habitación a una reserva", Request: roomAssignmentRequest{}, Response: models.RoomAssignment{}},
+	{Method: "DELETE", Path: "/reservations/{id}/rooms/{assignment_id}", Tag: "Rooms", Summary: "Quitar una habitación asignada"},
+	{Method: "POST", Path: "/reservations/{id}/move", Tag: "Rooms", Summary: "Cambiar de habitación durante la estancia", Request: roomMoveRequest{}, Response: models.RoomAssignment{}},
+	{Method: "POST", Path: "/reservations/{id}/check-in", Tag: "Rooms", Summary: "Registrar la llegada del huésped", Response: models.Reservation{}},
+	{Method: "POST", Path: "/reservations/{id}/check-out", Tag: "Rooms", Summary: "Registrar la salida del huésped", Response: models.Reservation{}},
+
+	// Housekeeping
+	{Method: "PUT", Path: "/rooms/{id}/housekeeping", Tag: "Housekeeping", Summary: "Cambiar el estado de limpieza de una habitación", Request: housekeepingUpdate{}, Response: models.Room{}},
+	{Method: "GET", Path: "/housekeeping/tasks", Tag: "Housekeeping", Summary: "Listar las tareas de limpieza del día", Query: []openapi.Parameter{
+		openapi.Query("date", openapi.Date, "Día (por defecto hoy)"),
+		openapi.Query("employee_id", openapi.Integer, "Filtrar por empleado"), statusParam,
+	}, Response: []models.HousekeepingTask{}},
+	{Method: "POST", Path: "/housekeeping/tasks/generate", Tag: "Housekeeping", Summary: "Generar las tareas de limpieza del día", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		openapi.Query("date", openapi.Date, "Día (por defecto hoy)"),
+	}, Response: []models.HousekeepingTask{}},
+	{Method: "PUT", Path: "/housekeeping/tasks/{id}", Tag: "Housekeeping", Summary: "Registrar el avance de una tarea de limpieza", Request: housekeepingTaskUpdate{}, Response: models.HousekeepingTask{}},
+
+	// WorkOrder y RoomOutage
+	{Method: "GET", Path: "/work-orders", Tag: "Maintenance", Summary: "Listar órdenes de trabajo", Query: []openapi.Parameter{
+		statusParam,
+		openapi.Query("room_id", openapi.Integer, "Filtrar por habitación"),
+		openapi.Query("employee_id", openapi.Integer, "Filtrar por empleado"),
+		openapi.Query("priority", openapi.String, "Filtrar por prioridad"),
+	}, Response: []models.WorkOrder{}},
+	{Method: "POST", Path: "/work-orders", Tag: "Maintenance", Summary: "Crear una orden de trabajo", Request: workOrderRequest{}, Response: models.WorkOrder{}},
+	{Method: "GET", Path: "/work-orders/{id}", Tag: "Maintenance", Summary: "Obtener una orden de trabajo", Response: models.WorkOrder{}},
+	{Method: "PUT", Path: "/work-orders/{id}", Tag: "Maintenance", Summary: "Registrar el avance de una orden de trabajo", Request: workOrderUpdate{}, Response: models.WorkOrder{}},
+	{Method: "GET", Path: "/room-outages", Tag: "Maintenance", Summary: "Listar períodos fuera de servicio", Query: []openapi.Parameter{
+		openapi.Query("room_id", openapi.Integer, "Filtrar por habitación"),
+	}, Response: []models.RoomOutage{}},
+	{Method: "POST", Path: "/room-outages", Tag: "Maintenance", Summary: "Poner una habitación fuera de servicio", Request: models.RoomOutage{}, Response: models.RoomOutage{}},
+	{Method: "POST", Path: "/room-outages/{id}/end", Tag: "Maintenance", Summary: "Terminar un período fuera de servicio"},
+
+	// GroupBlock
+	{Method: "GET", Path: "/groups", Tag: "Groups", Summary: "Listar bloqueos de grupo", Response: []models.GroupBlock{}},
+	{Method: "POST", Path: "/groups", Tag: "Groups", Summary: "Crear un bloqueo de grupo", Request: models.GroupBlock{}, Response: models.GroupBlock{}},
+	{Method: "GET", Path: "/groups/{id}", Tag: "Groups", Summary: "Obtener un bloqueo de grupo con sus reservas", Response: groupBlockResponse{}},
+	{Method: "POST", Path: "/groups/{id}/pickups", Tag: "Groups", Summary: "Reservar una habitación del cupo del grupo", Request: groupPickup{}, Response: models.Reservation{}},
+	{Method: "POST", Path: "/groups/{id}/release", Tag: "Groups", Summary: "Liberar el cupo no reservado", Response: models.GroupBlock{}},
+	{Method: "GET", Path: "/groups/{id}/folio", Tag: "Groups", Summary: "Obtener el folio maestro del grupo", Response: folioResponse{}},
+	{Method: "POST", Path: "/groups/{id}/folio/charges", Tag: "Groups", Summary: "Registrar un cargo en el folio maestro", Request: folioCharge{}, Response: models.FolioEntry{}},
+
+	// Consultation
+	{Method: "GET", Path: "/consultations", Tag: "Consultations", Summary: "Listar consultas", Query: []openapi.Parameter{
+		includeDeletedParam, statusParam,
+		openapi.Query("assigned_employee_id", openapi.Integer, "Filtrar por empleado asignado"),
+		openapi.Query("unassigned", openapi.Boolean, "Solo las consultas abiertas sin asignar"),
+		openapi.Query("overdue", openapi.Boolean, "Solo las consultas con el plazo vencido"),
+	}, Response: []models.Consultation{}},
+	{Method: "GET", Path: "/consultations/{id}", Tag: "Consultations", Summary: "Obtener una consulta con su hilo", Query: []openapi.Parameter{includeDeletedParam}, Response: models.Consultation{}},
+	{Method: "POST", Path: "/consultations", Tag: "Consultations", Summary: "Crear una consulta", Request: models.Consultation{}, Response: models.Consultation{}},
+	{Method: "PUT", Path: "/consultations/{id}", Tag: "Consultations", Summary: "Modificar una consulta", Request: models.Consultation{}, Response: models.Consultation{}},
+	{Method: "DELETE", Path: "/consultations/{id}", Tag: "Consultations", Summary: "Eliminar una consulta"},
+	{Method: "POST", Path: "/consultations/{id}/restore", Tag: "Consultations", Summary: "Recuperar una consulta eliminada", Role: middleware.RoleAdmin, Response: models.Consultation{}},
+	{Method: "GET", Path: "/consultations/{id}/messages", Tag: "Consultations", Summary: "Listar los mensajes del hilo", Response: []models.ConsultationMessage{}},
+	{Method: "POST", Path: "/consultations/{id}/messages", Tag: "Consultations", Summary: "Agregar un mensaje al hilo", Request: consultationMessageRequest{}, Response: models.ConsultationMessage{}},
+	{Method: "POST", Path: "/consultations/{id}/assign", Tag: "Consultations", Summary: "Asignar una consulta a un empleado", Role: middleware.RoleStaff, Request: consultationAssignment{}, Response: models.Consultation{}},
+	{Method: "PUT", Path: "/consultations/{id}/status", Tag: "Consultations", Summary: "Cambiar el estado de una consulta", Role: middleware.RoleStaff, Request: consultationStatusUpdate{}, Response: models.Consultation{}},
+	{Method: "POST", Path: "/consultations/{id}/convert", Tag: "Consultations", Summary: "Convertir una consulta en reserva tentativa", Role: middleware.RoleStaff, Request: conversionRequest{}, Response: conversionResult{}},
+	{Method: "GET", Path: "/reports/consultation-conversions", Tag: "Consultations", Summary: "Tasa de conversión de consultas por empleado", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		fromDateParam, toDateParam,
+	}, Response: []conversionStats{}},
+
+	// Employee
+	{Method: "GET", Path: "/employees", Tag: "Employees", Summary: "Listar empleados", Query: []openapi.Parameter{includeDeletedParam}, Response: []models.Employee{}},
+	{Method: "GET", Path: "/employees/{id}", Tag: "Employees", Summary: "Obtener un empleado", Query: []openapi.Parameter{includeDeletedParam}, Response: models.Employee{}},
+	{Method: "POST", Path: "/employees", Tag: "Employees", Summary: "Crear un empleado", Request: models.Employee{}, Response: models.Employee{}},
+	{Method: "PUT", Path: "/employees/{id}", Tag: "Employees", Summary: "Reemplazar un empleado (admite If-Match)", Request: models.Employee{}, Response: models.Employee{}},
+	{Method: "PATCH", Path: "/employees/{id}", Tag: "Employees", Summary: "Modificar un empleado con JSON Merge Patch", Request: employeeFields{}, RequestType: "application/merge-patch+json", Response: models.Employee{}},
+	{Method: "DELETE", Path: "/employees/{id}", Tag: "Employees", Summary: "Eliminar un empleado"},
+	{Method: "POST", Path: "/employees/{id}/restore", Tag: "Employees", Summary: "Recuperar un empleado eliminado", Role: middleware.RoleAdmin, Response: models.Employee{}},
+
+	// Folio y Payment
+	{Method: "GET", Path: "/reservations/{id}/folio", Tag: "Billing", Summary: "Obtener el folio de una reserva", Response: folioResponse{}},
+	{Method: "POST", Path: "/reservations/{id}/folio/charges", Tag: "Billing", Summary: "Registrar un cargo en el folio", Request: folioCharge{}, Response: models.FolioEntry{}},
+	{Method: "GET", Path: "/reservations/{id}/payments", Tag: "Billing", Summary: "Listar los pagos de una reserva", Response: []models.Payment{}},
+	{Method: "POST", Path: "/reservations/{id}/payments", Tag: "Billing", Summary: "Autorizar un pago (admite Idempotency-Key)", Request: paymentRequest{}, Response: models.Payment{}},
+	{Method: "POST", Path: "/payments/webhook", Tag: "Billing", Summary: "Notificación firmada del proveedor de pagos", Request: map[string]interface{}{}},
+	{Method: "POST", Path: "/payments/{id}/capture", Tag: "Billing", Summary: "Capturar un pago autorizado", Request: paymentAmount{}, OptionalBody: true, Response: models.Payment{}},
+	{Method: "POST", Path: "/payments/{id}/refund", Tag: "Billing", Summary: "Devolver un pago capturado", Request: paymentAmount{}, OptionalBody: true, Response: models.Payment{}},
+	{Method: "POST", Path: "/payments/{id}/void", Tag: "Billing", Summary: "Anular un pago autorizado", Response: models.Payment{}},
+
+	// Invoice
+	{Method: "GET", Path: "/reservations/{id}/invoices", Tag: "Billing", Summary: "Listar las facturas de una reserva", Response: []models.Invoice{}},
+	{Method: "POST", Path: "/reservations/{id}/invoices", Tag: "Billing", Summary: "Emitir la factura de una reserva", Response: models.Invoice{}},
+	{Method: "GET", Path: "/reservations/{id}/invoice.pdf", Tag: "Billing", Summary: "Descargar la última factura en PDF", Response: []byte{}, ContentType: "application/pdf"},
+	{Method: "GET", Path: "/reservations/{id}/invoice.html", Tag: "Billing", Summary: "Ver la última factura en HTML", Response: "", ContentType: "text/html"},
+	{Method: "GET", Path: "/invoices/{id:[0-9]+}.pdf", Tag: "Billing", Summary: "Descargar una factura en PDF", Response: []byte{}, ContentType: "application/pdf"},
+	{Method: "GET", Path: "/invoices/{id:[0-9]+}.html", Tag: "Billing", Summary: "Ver una factura en HTML", Response: "", ContentType: "text/html"},
+
+	// DomainEvent
+	{Method: "GET", Path: "/events", Tag: "Events", Summary: "Listar eventos de dominio", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		openapi.Query("type", openapi.String, "Filtrar por tipo de evento"), statusParam,
+		openapi.Query("aggregate_type", openapi.String, "Filtrar por tipo de entidad"),
+		openapi.Query("aggregate_id", openapi.Integer, "Filtrar por entidad"),
+	}, Response: []models.DomainEvent{}},
+	{Method: "GET", Path: "/events/stream", Tag: "Events", Summary: "Recibir los cambios en tiempo real como server-sent events", Query: []openapi.Parameter{
+		openapi.Query("topics", openapi.String, "Temas separados por comas: rooms, reservations o consultations (los dos últimos requieren rol staff)"),
+		openapi.Query("last_event_id", openapi.Integer, "Retomar después de este evento; el navegador envía la cabecera Last-Event-ID"),
+	}, Response: "", ContentType: "text/event-stream"},
+	{Method: "POST", Path: "/events/{id}/retry", Tag: "Events", Summary: "Reintentar un evento fallido", Role: middleware.RoleManager, Response: models.DomainEvent{}},
+
+	// Exportaciones
+	{Method: "GET", Path: "/exports/reservations", Tag: "Exports", Summary: "Exportar las reservas en CSV o Excel", Role: middleware.RoleStaff, Query: []openapi.Parameter{
+		exportFormatParam, includeDeletedParam,
+	}, Response: "", ContentType: "text/csv"},
+	{Method: "GET", Path: "/exports/users", Tag: "Exports", Summary: "Exportar los usuarios en CSV o Excel", Role: middleware.RoleStaff, Query: []openapi.Parameter{
+		exportFormatParam, includeDeletedParam,
+	}, Response: "", ContentType: "text/csv"},
+	{Method: "GET", Path: "/exports/consultations", Tag: "Exports", Summary: "Exportar las consultas en CSV o Excel con los filtros de GET /consultations", Role: middleware.RoleStaff, Query: []openapi.Parameter{
+		exportFormatParam, includeDeletedParam, statusParam,
+		openapi.Query("assigned_employee_id", openapi.Integer, "Filtrar por empleado asignado"),
+		openapi.Query("unassigned", openapi.Boolean, "Solo las consultas abiertas sin asignar"),
+		openapi.Query("overdue", openapi.Boolean, "Solo las consultas con el plazo vencido"),
+	}, Response: "", ContentType: "text/csv"},
+	{Method: "GET", Path: "/exports/employees", Tag: "Exports", Summary: "Exportar los empleados en CSV o Excel; el salario solo para el rol manager", Role: middleware.RoleStaff, Query: []openapi.Parameter{
+		exportFormatParam, includeDeletedParam,
+	}, Response: "", ContentType: "text/csv"},
+
+	// Restriction y Channel
+	{Method: "GET", Path: "/restrictions", Tag: "Channels", Summary: "Listar restricciones de venta", Query: []openapi.Parameter{
+		openapi.RequiredQuery("from", openapi.Date, "Fecha inicial"),
+		openapi.RequiredQuery("to", openapi.Date, "Fecha final"),
+		openapi.Query("room_type", openapi.String, "Filtrar por tipo de habitación"),
+	}, Response: []models.Restriction{}},
+	{Method: "PUT", Path: "/restrictions", Tag: "Channels", Summary: "Aplicar restricciones de venta a un rango de fechas", Role: middleware.RoleManager, Request: restrictionUpdate{}, Response: []models.Restriction{}},
+	{Method: "GET", Path: "/channels", Tag: "Channels", Summary: "Listar canales", Role: middleware.RoleManager, Response: []models.Channel{}},
+	{Method: "POST", Path: "/channels", Tag: "Channels", Summary: "Crear un canal", Role: middleware.RoleManager, Request: models.Channel{}, Response: models.Channel{}},
+	{Method: "GET", Path: "/channels/{id}", Tag: "Channels", Summary: "Obtener un canal con sus correspondencias", Role: middleware.RoleManager, Response: models.Channel{}},
+	{Method: "PUT", Path: "/channels/{id}", Tag: "Channels", Summary: "Modificar un canal", Role: middleware.RoleManager, Request: models.Channel{}, Response: models.Channel{}},
+	{Method: "PUT", Path: "/channels/{id}/mappings", Tag: "Channels", Summary: "Reemplazar las correspondencias de habitaciones y tarifas", Role: middleware.RoleManager, Request: channelMappings{}, Response: models.Channel{}},
+	{Method: "POST", Path: "/channels/{id}/push", Tag: "Channels", Summary: "Enviar disponibilidad, tarifas y restricciones al canal", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		fromDateParam, toDateParam,
+	}, Response: channelPushResult{}},
+	{Method: "POST", Path: "/channels/{id}/pull", Tag: "Channels", Summary: "Importar las reservas del canal", Role: middleware.RoleManager, Response: channels.PullResult{}},
+	{Method: "GET", Path: "/channels/{id}/conflicts", Tag: "Channels", Summary: "Listar los conflictos de importación", Role: middleware.RoleManager, Query: []openapi.Parameter{statusParam}, Response: []models.ChannelConflict{}},
+	{Method: "POST", Path: "/channel-conflicts/{id}/resolve", Tag: "Channels", Summary: "Marcar un conflicto como resuelto", Role: middleware.RoleManager, Response: models.ChannelConflict{}},
+
+	// WebhookSubscription y WebhookDelivery
+	{Method: "GET", Path: "/webhooks", Tag: "Webhooks", Summary: "Listar suscripciones", Role: middleware.RoleManager, Response: []models.WebhookSubscription{}},
+	{Method: "POST", Path: "/webhooks", Tag: "Webhooks", Summary: "Crear una suscripción; el secreto solo se devuelve aquí", Role: middleware.RoleManager, Request: models.WebhookSubscription{}, Response: models.WebhookSubscription{}},
+	{Method: "GET", Path: "/webhooks/dead-letters", Tag: "Webhooks", Summary: "Listar las entregas agotadas", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		openapi.Query("subscription_id", openapi.Integer, "Filtrar por suscripción"),
+		openapi.Query("event_type", openapi.String, "Filtrar por tipo de evento"),
+	}, Response: []models.WebhookDelivery{}},
+	{Method: "GET", Path: "/webhooks/{id:[0-9]+}", Tag: "Webhooks", Summary: "Obtener una suscripción", Role: middleware.RoleManager, Response: models.WebhookSubscription{}},
+	{Method: "PUT", Path: "/webhooks/{id:[0-9]+}", Tag: "Webhooks", Summary: "Modificar una suscripción", Role: middleware.RoleManager, Request: models.WebhookSubscription{}, Response: models.WebhookSubscription{}},
+	{Method: "DELETE", Path: "/webhooks/{id:[0-9]+}", Tag: "Webhooks", Summary: "Eliminar una suscripción", Role: middleware.RoleManager},
+	{Method: "GET", Path: "/webhooks/{id:[0-9]+}/deliveries", Tag: "Webhooks", Summary: "Listar las últimas entregas de una suscripción", Role: middleware.RoleManager, Query: []openapi.Parameter{statusParam}, Response: []models.WebhookDelivery{}},
+	{Method: "POST", Path: "/webhook-deliveries/{id}/replay", Tag: "Webhooks", Summary: "Volver a enviar una entrega", Role: middleware.RoleManager, Response: models.WebhookDelivery{}},
+
+	// Notification
+	{Method: "GET", Path: "/notifications", Tag: "Notifications", Summary: "Listar notificaciones", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		statusParam,
+		openapi.Query("kind", openapi.String, "Filtrar por tipo de notificación"),
+		openapi.Query("recipient", openapi.String, "Filtrar por destinatario"),
+	}, Response: []models.Notification{}},
+	{Method: "POST", Path: "/notifications/{id}/retry", Tag: "Notifications", Summary: "Reintentar una notificación fallida", Role: middleware.RoleManager, Response: models.Notification{}},
+
+	// AuditLog
+	{Method: "GET", Path: "/audit", Tag: "Audit", Summary: "Consultar el registro de auditoría", Role: middleware.RoleManager, Query: []openapi.Parameter{
+		openapi.Query("resource_type", openapi.String, "Filtrar por tipo de recurso"),
+		openapi.Query("resource_id", openapi.Integer, "Filtrar por recurso"),
+		openapi.Query("actor", openapi.String, "Filtrar por autor"),
+		openapi.Query("action", openapi.String, "Filtrar por acción"),
+		openapi.Query("request_id", openapi.String, "Filtrar por solicitud"),
+		openapi.Query("from", openapi.DateTime, "Desde (RFC 3339)"),
+		openapi.Query("to", openapi.DateTime, "Hasta (RFC 3339)"),
+		openapi.Query("limit", openapi.Integer, "Cantidad máxima de registros (1 a 1000, por defecto 100)"),
+		openapi.Query("offset", openapi.Integer, "Registros a saltear"),
+	}, Response: []models.AuditLog{}},
+
+	// GraphQL
+	{Method: "GET", Path: "/graphql", Tag: "GraphQL", Summary: "Ejecutar una consulta GraphQL (las mutaciones solo por POST)", Query: []openapi.Parameter{
+		openapi.RequiredQuery("query", openapi.String, "Documento GraphQL"),
+		openapi.Query("operationName", openapi.String, "Operación a ejecutar si el documento tiene varias"),
+		openapi.Query("variables", openapi.String, "Variables en formato JSON"),
+	}, Response: graphql.Result{}},
+	{Method: "POST", Path: "/graphql", Tag: "GraphQL", Summary: "Ejecutar una consulta o mutación GraphQL", Request: graph.Request{}, Response: graphql.Result{}},
+
+	// Especificación OpenAPI
+	{Method: "GET", Path: "/openapi.json", Tag: "Docs", Summary: "Especificación OpenAPI de la API", Response: map[string]interface{}{}},
+	{Method: "GET", Path: "/docs", Tag: "Docs", Summary: "Documentación interactiva (Swagger UI)", Response: "", ContentType: "text/html"},
+}
+
+// OpenAPI es la especificación de la API generada a partir de apiRoutes
+var OpenAPI = openapi.Build(openapi.Info{
+	Title:       "Hotel API REST",
+	Version:     "1.0.0",
+	Description: "API REST de gestión hotelera. Los errores se devuelven como texto plano con el código HTTP correspondiente",
+}, apiRoutes)
+
+// GetOpenAPIHandler devuelve la especificación OpenAPI en formato JSON
+func GetOpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	openapi.Handler(OpenAPI)(w, r)
+}
+
+// GetDocsHandler sirve Swagger UI con la especificación de /openapi.json
+func GetDocsHandler(w http.ResponseWriter, r *http.Request) {
+	openapi.UIHandler("/openapi.json")(w, r)
+}
@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/openapi"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// registeredRoutes devuelve las rutas del enrutador como claves "MÉTODO /ruta" ordenadas
+func registeredRoutes(t *testing.T, r *mux.Router) []string {
+	var keys []string
+	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			keys = append(keys, openapi.Key(method, path))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Strings(keys)
+	return keys
+}
+
+// La especificación documenta exactamente las rutas registradas en el enrutador
+func TestOpenAPICoversRoutes(t *testing.T) {
+	routes := registeredRoutes(t, NewRouter())
+	documented := OpenAPI.Operations()
+	assert.Equal(t, routes, documented)
+	assert.Len(t, documented, len(apiRoutes), "route documented twice")
+}
+
+func TestGetOpenAPIHandler(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	rr := httptest.NewRecorder()
+	NewRouter().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc["openapi"])
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Contains(t, schemas, "Reservation")
+	assert.Contains(t, schemas, "CancellationQuote")
+
+	req, _ = http.NewRequest("GET", "/docs", nil)
+	rr = httptest.NewRecorder()
+	NewRouter().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "swagger-ui")
+}
+
+// Las respuestas que codifican los handlers cumplen los esquemas documentados
+func TestOpenAPIResponseSchemas(t *testing.T) {
+	for _, route := range apiRoutes {
+		if route.Response == nil || route.ContentType != "" {
+			continue
+		}
+		operation, _, ok := OpenAPI.Match(route.Method, openapi.Path(route.Path))
+		assert.True(t, ok, route.Path)
+		body, err := json.Marshal(route.Response)
+		assert.NoError(t, err)
+		schema := operation.Responses["200"].Content["application/json"].Schema
+		assert.NoError(t, OpenAPI.ValidateResponseBody(schema, body), route.Method+" "+route.Path)
+	}
+
+	// Un usuario con sus reservas, como lo devuelve GetUserHandler
+	user := models.User{FirstName: "Ana", Email: "ana@example.com", Reservations: []models.Reservation{{Adults: 2, Checkin: time.Now()}}}
+	body, _ := json.Marshal(user)
+	operation, _, _ := OpenAPI.Match("GET", "/users/1")
+	schema := operation.Responses["200"].Content["application/json"].Schema
+	assert.NoError(t, OpenAPI.ValidateResponseBody(schema, body))
+	assert.Error(t, OpenAPI.ValidateResponseBody(schema, []byte(`{"ID":1,"first_name":"Ana"}`)))
+}
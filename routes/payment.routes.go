@@ -0,0 +1,439 @@
+package routes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/payments"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newIdempotencyKey genera una clave aleatoria cuando el cliente no envía una
+func newIdempotencyKey() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// requestIdempotencyKey obtiene la clave de idempotencia de la cabecera o genera una nueva
+func requestIdempotencyKey(r *http.Request, fallback string) string {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		return key
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return newIdempotencyKey()
+}
+
+// writeProviderError traduce los errores del proveedor de pagos a respuestas HTTP
+func writeProviderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, payments.ErrInvalidAmount):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, payments.ErrInvalidState):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, payments.ErrUnknownReference):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "Payment provider error: "+err.Error(), http.StatusBadGateway)
+	}
+}
+
+// postPaymentToFolio registra en el folio el movimiento correspondiente a un pago o reembolso.
+// La referencia identifica la operación del proveedor, de modo que un reintento no duplica el movimiento
+func postPaymentToFolio(tx *gorm.DB, payment models.Payment, entryType string, amount float64, reference string) error {
+	var count int64
+	if err := tx.Model(&models.FolioEntry{}).
+		Where("payment_id = ? AND type = ? AND reference = ?", payment.ID, entryType, reference).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	entry := models.FolioEntry{
+		FolioID:     payment.FolioID,
+		Type:        entryType,
+		Description: fmt.Sprintf("%s %s (%s)", payment.Kind, entryType, payment.ProviderRef),
+		Amount:      amount,
+		PaymentID:   &payment.ID,
+		Reference:   reference,
+	}
+	return tx.Create(&entry).Error
+}
+
+// findPayment busca un pago por el ID de la URL y responde 404 si no existe
+func findPayment(w http.ResponseWriter, r *http.Request) (models.Payment, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var payment models.Payment
+	if err := db.DB.First(&payment, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Payment not found"))
+			return payment, false
+		}
+		http.Error(w, "Failed to retrieve payment", http.StatusInternalServerError)
+		return payment, false
+	}
+	return payment, true
+}
+
+// GetReservationPaymentsHandler devuelve los pagos asociados a una reserva
+func GetReservationPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var paymentList []models.Payment
+	if err := db.DB.Order("id asc").Where("reservation_id = ?", params["id"]).Find(&paymentList).Error; err != nil {
+		http.Error(w, "Failed to retrieve payments", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&paymentList); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// paymentRequest es el cuerpo de un cobro nuevo
+type paymentRequest struct {
+	Amount         float64 `json:"amount"`
+	Currency       string  `json:"currency"`
+	Kind           string  `json:"kind"`
+	Token          string  `json:"token"`
+	Capture        bool    `json:"capture"`
+	IdempotencyKey string  `json:"idempotency_key"`
+}
+
+// CreatePaymentHandler autoriza un pago para una reserva y, si se solicita, lo captura en el momento
+func CreatePaymentHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var body paymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Amount <= 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if body.Kind == "" {
+		body.Kind = models.PaymentDeposit
+	}
+	if body.Kind != models.PaymentDeposit && body.Kind != models.PaymentSettlement {
+		http.Error(w, "Invalid payment kind", http.StatusBadRequest)
+		return
+	}
+	if body.Currency == "" {
+		body.Currency = "ARS"
+	}
+	key := requestIdempotencyKey(r, body.IdempotencyKey)
+
+	// Si el pago ya fue creado con esta clave, devolver el resultado original
+	var existing models.Payment
+	if err := db.DB.Where("idempotency_key = ?", key).First(&existing).Error; err == nil {
+		if existing.ReservationID != reservation.ID {
+			http.Error(w, "Idempotency key already used for another reservation", http.StatusUnprocessableEntity)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(&existing); err != nil {
+			http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	folio, err := findOrCreateFolio(db.DB, reservation.ID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve folio", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := payments.Provider.Authorize(r.Context(), payments.AuthorizeRequest{
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		Token:          body.Token,
+		Description:    fmt.Sprintf("Reservation %d", reservation.ID),
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		writeProviderError(w, err)
+		return
+	}
+
+	payment := models.Payment{
+		ReservationID:  reservation.ID,
+		FolioID:        folio.ID,
+		Kind:           body.Kind,
+		Amount:         body.Amount,
+		Currency:       body.Currency,
+		Status:         result.Status,
+		Provider:       payments.Provider.Name(),
+		ProviderRef:    result.Reference,
+		IdempotencyKey: key,
+		FailureReason:  result.FailureReason,
+	}
+
+	// Capturar en el momento si el cliente lo pidió y la autorización fue aprobada
+	if body.Capture && result.Status == models.PaymentAuthorized {
+		captured, err := payments.Provider.Capture(r.Context(), result.Reference, 0, key+":capture")
+		if err != nil {
+			writeProviderError(w, err)
+			return
+		}
+		payment.Status = captured.Status
+		payment.CapturedAmount = captured.Amount
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+		if payment.Status == models.PaymentCaptured {
+			if err := postPaymentToFolio(tx, payment, models.FolioEntryPayment, payment.CapturedAmount, key+":capture"); err != nil {
+				return err
+			}
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourcePayment, payment.ID, nil, &payment)
+	})
+	if err != nil {
+		http.Error(w, "Failed to save payment", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&payment); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// paymentAmount es el importe opcional de una captura o devolución; sin importe se usa el total
+type paymentAmount struct {
+	Amount float64 `json:"amount"`
+}
+
+// CapturePaymentHandler captura total o parcialmente un pago autorizado y lo registra en el folio
+func CapturePaymentHandler(w http.ResponseWriter, r *http.Request) {
+	payment, ok := findPayment(w, r)
+	if !ok {
+		return
+	}
+
+	var body paymentAmount
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	key := requestIdempotencyKey(r, payment.IdempotencyKey+":capture")
+	result, err := payments.Provider.Capture(r.Context(), payment.ProviderRef, body.Amount, key)
+	if err != nil {
+		writeProviderError(w, err)
+		return
+	}
+
+	before := payment
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		payment.Status = result.Status
+		payment.CapturedAmount = result.Amount
+		if err := tx.Save(&payment).Error; err != nil {
+			return err
+		}
+		if err := postPaymentToFolio(tx, payment, models.FolioEntryPayment, result.Amount, key); err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourcePayment, payment.ID, &before, &payment)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update payment", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&payment); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// RefundPaymentHandler reembolsa total o parcialmente un pago capturado y lo registra en el folio
+func RefundPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	payment, ok := findPayment(w, r)
+	if !ok {
+		return
+	}
+
+	var body paymentAmount
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Sin cabecera, la clave depende del importe: un reintento del mismo reembolso no reembolsa dos veces
+	key := requestIdempotencyKey(r, payment.IdempotencyKey+":refund:"+strconv.FormatFloat(body.Amount, 'f', -1, 64))
+	result, err := payments.Provider.Refund(r.Context(), payment.ProviderRef, body.Amount, key)
+	if err != nil {
+		writeProviderError(w, err)
+		return
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// Releer el pago bloqueado: otro reembolso o un webhook pueden estar modificándolo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, payment.ID).Error; err != nil {
+			return err
+		}
+		// Un reintento con la misma clave ya quedó registrado en el folio
+		var count int64
+		if err := tx.Model(&models.FolioEntry{}).Where("payment_id = ? AND reference = ?", payment.ID, key).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		before := payment
+		if err := applyRefund(tx, r, &payment, result.Status, result.Amount, key); err != nil {
+			return err
+		}
+		if err := tx.Save(&payment).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourcePayment, payment.ID, &before, &payment)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update payment", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&payment); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// applyRefund registra en el pago, en el folio y en una nota de crédito la parte del reembolso que todavía
+// no estaba registrada, de modo que un reembolso informado por la API y por un webhook no se cuente dos
+// veces. El pago debe estar bloqueado en la transacción; el llamador lo guarda
+func applyRefund(tx *gorm.DB, r *http.Request, payment *models.Payment, status string, amount float64, reference string) error {
+	if payment.CanMoveTo(status) {
+		payment.Status = status
+	}
+	if pending := payment.CapturedAmount - payment.RefundedAmount; amount > pending {
+		amount = pending
+	}
+	if amount <= 0 {
+		return nil
+	}
+	payment.RefundedAmount += amount
+	if err := postPaymentToFolio(tx, *payment, models.FolioEntryRefund, amount, reference); err != nil {
+		return err
+	}
+	return issueCreditNote(tx, r, *payment, amount)
+}
+
+// VoidPaymentHandler anula una autorización que todavía no fue capturada
+func VoidPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	payment, ok := findPayment(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := payments.Provider.Void(r.Context(), payment.ProviderRef, requestIdempotencyKey(r, payment.IdempotencyKey+":void"))
+	if err != nil {
+		writeProviderError(w, err)
+		return
+	}
+
+	before := payment
+	payment.Status = result.Status
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&payment).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourcePayment, payment.ID, &before, &payment)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update payment", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&payment); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// PaymentWebhookHandler recibe los resultados asíncronos del proveedor de pagos
+func PaymentWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	event, err := payments.Provider.ParseWebhook(r)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, payments.ErrNoWebhookSecret) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	var payment models.Payment
+	if err := db.DB.Where("provider_ref = ?", event.Reference).First(&payment).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Payment not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve payment", http.StatusInternalServerError)
+		return
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		// Releer el pago bloqueado: un reembolso de la API puede estar modificándolo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, payment.ID).Error; err != nil {
+			return err
+		}
+		// Los webhooks pueden llegar repetidos o desordenados: solo se aplican si hacen avanzar el estado,
+		// así que un webhook viejo no devuelve a "authorized" un pago ya anulado o reembolsado
+		if !payment.CanMoveTo(event.Status) {
+			return nil
+		}
+
+		before := payment
+		if event.Status == models.PaymentFailed {
+			payment.FailureReason = "rejected asynchronously"
+		}
+		if event.Status == models.PaymentCaptured {
+			payment.CapturedAmount = event.Amount
+			if err := postPaymentToFolio(tx, payment, models.FolioEntryPayment, event.Amount, event.ID); err != nil {
+				return err
+			}
+		}
+		// El pago quedó reembolsado por completo: se registra lo que faltaba reembolsar
+		if event.Status == models.PaymentRefunded {
+			if err := applyRefund(tx, r, &payment, event.Status, payment.CapturedAmount-payment.RefundedAmount, event.ID); err != nil {
+				return err
+			}
+		}
+		payment.Status = event.Status
+		if err := tx.Save(&payment).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourcePayment, payment.ID, &before, &payment)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update payment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,185 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// validPenaltyType indica si el tipo de penalización es uno de los soportados
+func validPenaltyType(penaltyType string) bool {
+	return penaltyType == models.PenaltyPercentage || penaltyType == models.PenaltyFirstNight
+}
+
+// GetCancellationPoliciesHandler obtiene todas las políticas de cancelación en orden ascendente por ID
+func GetCancellationPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	var policies []models.CancellationPolicy
+	if err := db.DB.Order("id asc").Find(&policies).Error; err != nil {
+		http.Error(w, "Failed to retrieve cancellation policies", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&policies); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateCancellationPolicyHandler crea una nueva política de cancelación
+func CreateCancellationPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	var policy models.CancellationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if !validPenaltyType(policy.PenaltyType) || policy.FreeUntilDays < 0 || policy.PenaltyPercent < 0 || policy.PenaltyPercent > 100 {
+		http.Error(w, "Invalid cancellation policy", http.StatusBadRequest)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&policy).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceCancellationPolicy, policy.ID, nil, &policy)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&policy); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateCancellationPolicyHandler actualiza una política de cancelación existente por ID
+func UpdateCancellationPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var policy models.CancellationPolicy
+	if err := db.DB.First(&policy, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Cancellation policy not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve cancellation policy", http.StatusInternalServerError)
+		return
+	}
+
+	var updatedPolicy models.CancellationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&updatedPolicy); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if !validPenaltyType(updatedPolicy.PenaltyType) || updatedPolicy.FreeUntilDays < 0 || updatedPolicy.PenaltyPercent < 0 || updatedPolicy.PenaltyPercent > 100 {
+		http.Error(w, "Invalid cancellation policy", http.StatusBadRequest)
+		return
+	}
+
+	before := policy
+	policy.Name = updatedPolicy.Name
+	policy.FreeUntilDays = updatedPolicy.FreeUntilDays
+	policy.PenaltyType = updatedPolicy.PenaltyType
+	policy.PenaltyPercent = updatedPolicy.PenaltyPercent
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&policy).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceCancellationPolicy, policy.ID, &before, &policy)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update cancellation policy", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&policy); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetRatePlansHandler obtiene todas las tarifas con su política de cancelación
+func GetRatePlansHandler(w http.ResponseWriter, r *http.Request) {
+	var ratePlans []models.RatePlan
+	if err := db.DB.Preload("CancellationPolicy").Order("id asc").Find(&ratePlans).Error; err != nil {
+		http.Error(w, "Failed to retrieve rate plans", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&ratePlans); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateRatePlanHandler crea una nueva tarifa
+func CreateRatePlanHandler(w http.ResponseWriter, r *http.Request) {
+	var ratePlan models.RatePlan
+	if err := json.NewDecoder(r.Body).Decode(&ratePlan); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	// La política se asocia por ID, no se crea junto con la tarifa
+	ratePlan.CancellationPolicy = nil
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ratePlan).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceRatePlan, ratePlan.ID, nil, &ratePlan)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&ratePlan); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateRatePlanHandler actualiza una tarifa existente por ID
+func UpdateRatePlanHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var ratePlan models.RatePlan
+	if err := db.DB.First(&ratePlan, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Rate plan not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve rate plan", http.StatusInternalServerError)
+		return
+	}
+
+	var updatedRatePlan models.RatePlan
+	if err := json.NewDecoder(r.Body).Decode(&updatedRatePlan); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before := ratePlan
+	ratePlan.Code = updatedRatePlan.Code
+	ratePlan.Name = updatedRatePlan.Name
+	ratePlan.NightlyRate = updatedRatePlan.NightlyRate
+	ratePlan.CancellationPolicyID = updatedRatePlan.CancellationPolicyID
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&ratePlan).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRatePlan, ratePlan.ID, &before, &ratePlan)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update rate plan", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&ratePlan); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -2,18 +2,30 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/mergepatch"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GetReservationsHandler obtiene todas las reservas desde la base de datos en orden ascendente por ID y las devuelve en formato JSON
 func GetReservationsHandler(w http.ResponseWriter, r *http.Request) {
 	var reservations []models.Reservation
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
 	// Buscar todas las reservas en la base de datos y ordenarlas por ID en orden ascendente
-	if err := db.DB.Order("id asc").Find(&reservations).Error; err != nil {
+	if err := conn.Order("id asc").Find(&reservations).Error; err != nil {
 		// Manejar el error si ocurre al buscar las reservas
 		http.Error(w, "Failed to retrieve reservations", http.StatusInternalServerError)
 		return
@@ -30,8 +42,12 @@ func GetReservationsHandler(w http.ResponseWriter, r *http.Request) {
 func GetReservationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
 	var reservation models.Reservation
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
 	// Buscar una reserva específica por ID
-	if err := db.DB.First(&reservation, params["id"]).Error; err != nil {
+	if err := conn.First(&reservation, params["id"]).Error; err != nil {
 		// Verificar si la reserva no fue encontrada
 		if err.Error() == "record not found" {
 			// Si la reserva no existe, devolver un error 404
@@ -44,7 +60,8 @@ func GetReservationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Codificar la reserva en formato JSON y enviarla como respuesta
+	// Codificar la reserva en formato JSON y enviarla como respuesta junto con su versión
+	setETag(w, reservation.Version)
 	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
 		// Manejar el error si ocurre al codificar el JSON
 		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
@@ -53,16 +70,36 @@ func GetReservationHandler(w http.ResponseWriter, r *http.Request) {
 
 // CreateReservationHandler crea una nueva reserva en la base de datos
 func CreateReservationHandler(w http.ResponseWriter, r *http.Request) {
-	var reservation models.Reservation
+	var fields newReservationFields
 	// Decodificar el cuerpo de la solicitud para obtener los datos de la nueva reserva
-	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
 		// Manejar el error si la carga útil de la solicitud es inválida
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	// Solo se toman los campos que el cliente puede indicar; el estado, la penalización y las
+	// referencias de canales y calendarios los asigna la API
+	reservation := models.Reservation{
+		Adults:        fields.Adults,
+		Checkin:       fields.Checkin,
+		Checkout:      fields.Checkout,
+		Children:      fields.Children,
+		Email:         fields.Email,
+		NumberOfRooms: fields.NumberOfRooms,
+		RoomType:      fields.RoomType,
+		UserID:        fields.UserID,
+		GuestName:     fields.GuestName,
+		RatePlanID:    fields.RatePlanID,
+	}
 
-	// Crear la nueva reserva en la base de datos
-	if err := db.DB.Create(&reservation).Error; err != nil {
+	// Crear la nueva reserva en la base de datos y registrarla en la auditoría
+	err := createReservation(r, &reservation)
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		// Manejar el error si no quedan habitaciones
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
 		// Manejar el error si ocurre al crear la reserva
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -75,6 +112,39 @@ func CreateReservationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// errInvalidStay se devuelve cuando el check-out no es posterior al check-in
+var errInvalidStay = errors.New("check_out must be after check_in")
+
+// errRoomsAssigned se devuelve al cambiar el tipo o la cantidad de habitaciones de una reserva que ya
+// tiene habitaciones asignadas
+var errRoomsAssigned = errors.New("unassign the rooms before changing the room type or number of rooms")
+
+// createReservation da de alta la reserva confirmada si quedan habitaciones del tipo pedido, la registra
+// en la auditoría y publica el evento. La comparten el handler REST, la mutación GraphQL y el servicio gRPC
+func createReservation(r *http.Request, reservation *models.Reservation) error {
+	if !reservation.Checkout.After(reservation.Checkin) {
+		return errInvalidStay
+	}
+	// Las habitaciones de un grupo se toman desde su bloqueo
+	reservation.GroupBlockID = nil
+	reservation.Status = models.ReservationConfirmed
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		// Comprobar que queden habitaciones del tipo pedido, si tiene inventario configurado
+		if err := inventory.Reserve(tx, reservation.RoomType, reservation.Checkin, reservation.Checkout, reservation.NumberOfRooms); err != nil {
+			return err
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceReservation, reservation.ID, nil, reservation); err != nil {
+			return err
+		}
+		// Enviar la confirmación al huésped
+		return events.PublishReservation(tx, events.ReservationCreated, *reservation)
+	})
+}
+
 // UpdateReservationHandler actualiza una reserva existente por ID con los datos proporcionados
 func UpdateReservationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -94,6 +164,11 @@ func UpdateReservationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Rechazar la escritura si el cliente editó una versión anterior
+	if !checkIfMatch(w, r, reservation.Version) {
+		return
+	}
+
 	// Decodificar el cuerpo de la solicitud para obtener los datos actualizados
 	var updatedReservation models.Reservation
 	if err := json.NewDecoder(r.Body).Decode(&updatedReservation); err != nil {
@@ -103,29 +178,166 @@ func UpdateReservationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Actualizar los campos de la reserva existente con los datos proporcionados
-	reservation.Adults = updatedReservation.Adults
-	reservation.Checkin = updatedReservation.Checkin
-	reservation.Checkout = updatedReservation.Checkout
-	reservation.Children = updatedReservation.Children
-	reservation.Email = updatedReservation.Email
-	reservation.NumberOfRooms = updatedReservation.NumberOfRooms
-	reservation.RoomType = updatedReservation.RoomType
-	reservation.UserID = updatedReservation.UserID
-
-	// Guardar los cambios en la base de datos
-	if err := db.DB.Save(&reservation).Error; err != nil {
+	fields := reservationFields{
+		Adults:        updatedReservation.Adults,
+		Checkin:       updatedReservation.Checkin,
+		Checkout:      updatedReservation.Checkout,
+		Children:      updatedReservation.Children,
+		Email:         updatedReservation.Email,
+		NumberOfRooms: updatedReservation.NumberOfRooms,
+		RoomType:      updatedReservation.RoomType,
+		UserID:        updatedReservation.UserID,
+		RatePlanID:    updatedReservation.RatePlanID,
+	}
+
+	// Guardar los cambios en la base de datos junto con su registro de auditoría
+	err := updateReservation(r, &reservation, fields)
+	if err != nil {
 		// Manejar el error si ocurre al guardar la reserva actualizada
-		http.Error(w, "Failed to update reservation", http.StatusInternalServerError)
+		writeReservationUpdateError(w, err)
 		return
 	}
 
 	// Codificar la reserva actualizada en formato JSON y enviarla como respuesta
+	setETag(w, reservation.Version)
 	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
 		// Manejar el error si ocurre al codificar el JSON
 		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
 	}
 }
 
+// reservationFields son los campos de una reserva que el cliente puede modificar
+type reservationFields struct {
+	Adults        int       `json:"adults"`
+	Checkin       time.Time `json:"check_in"`
+	Checkout      time.Time `json:"check_out"`
+	Children      int       `json:"children"`
+	Email         string    `json:"email"`
+	NumberOfRooms int       `json:"number_of_rooms"`
+	RoomType      string    `json:"room_type"`
+	UserID        uint      `json:"user_id"`
+	RatePlanID    *uint     `json:"rate_plan_id"`
+}
+
+// writeReservationUpdateError traduce los errores de updateReservation a respuestas HTTP
+func writeReservationUpdateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errInvalidStay):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, inventory.ErrNoAvailability), errors.Is(err, errRoomsAssigned),
+		errors.Is(err, errRoomTaken), errors.Is(err, errRoomOutOfService):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeSaveError(w, err, "Failed to update reservation")
+	}
+}
+
+// newReservationFields son los campos que el cliente indica al crear una reserva
+type newReservationFields struct {
+	reservationFields
+	GuestName string `json:"guest_name"`
+}
+
+// PatchReservationHandler modifica parcialmente una reserva aplicando un JSON Merge Patch:
+// los campos omitidos se conservan
+func PatchReservationHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+	if !checkIfMatch(w, r, reservation.Version) {
+		return
+	}
+	patch, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	// Aplicar el parche sobre los campos editables de la reserva
+	fields := reservationFields{
+		Adults:        reservation.Adults,
+		Checkin:       reservation.Checkin,
+		Checkout:      reservation.Checkout,
+		Children:      reservation.Children,
+		Email:         reservation.Email,
+		NumberOfRooms: reservation.NumberOfRooms,
+		RoomType:      reservation.RoomType,
+		UserID:        reservation.UserID,
+		RatePlanID:    reservation.RatePlanID,
+	}
+	if err := mergepatch.ApplyTo(&fields, patch); err != nil {
+		http.Error(w, "Invalid merge patch: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := updateReservation(r, &reservation, fields)
+	if err != nil {
+		writeReservationUpdateError(w, err)
+		return
+	}
+
+	setETag(w, reservation.Version)
+	if err := json.NewEncoder(w).Encode(&reservation); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// updateReservation guarda los campos editables de la reserva si su versión no cambió, registra la
+// modificación en la auditoría y publica el evento. Si cambia la estancia comprueba que queden
+// habitaciones para las fechas nuevas y devuelve inventory.ErrNoAvailability si no, o errInvalidStay si
+// el check-out no es posterior al check-in. Las habitaciones asignadas se ajustan a las fechas nuevas;
+// con habitaciones asignadas no se puede cambiar el tipo ni la cantidad (errRoomsAssigned). La comparten
+// PUT, PATCH y el servicio gRPC
+func updateReservation(r *http.Request, reservation *models.Reservation, fields reservationFields) error {
+	if !fields.Checkout.After(fields.Checkin) {
+		return errInvalidStay
+	}
+	before := *reservation
+	reservation.Adults = fields.Adults
+	reservation.Checkin = fields.Checkin
+	reservation.Checkout = fields.Checkout
+	reservation.Children = fields.Children
+	reservation.Email = fields.Email
+	reservation.NumberOfRooms = fields.NumberOfRooms
+	reservation.RoomType = fields.RoomType
+	reservation.UserID = fields.UserID
+	reservation.RatePlanID = fields.RatePlanID
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if stayChanged(before, *reservation) && before.Status != models.ReservationCancelled {
+			// Bloquear la reserva para que no se le asignen habitaciones mientras cambia la estancia
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Reservation{}, before.ID).Error; err != nil {
+				return err
+			}
+			if err := adjustAssignments(tx, r, before, *reservation); err != nil {
+				return err
+			}
+			// Liberar las habitaciones que ocupa la reserva antes de comprobar la estancia nueva; el
+			// estado original se vuelve a guardar con el resto de los campos
+			if err := tx.Model(&before).UpdateColumn("status", models.ReservationCancelled).Error; err != nil {
+				return err
+			}
+			if err := inventory.Reserve(tx, reservation.RoomType, reservation.Checkin, reservation.Checkout, reservation.NumberOfRooms); err != nil {
+				return err
+			}
+		}
+		// Al cambiar de tarifa se pactan las condiciones de cancelación de la nueva
+		if !sameRatePlan(before.RatePlanID, reservation.RatePlanID) {
+			if err := reservation.AgreeCancellationTerms(tx); err != nil {
+				return err
+			}
+		}
+		if err := saveVersioned(tx, reservation, &reservation.Version); err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceReservation, reservation.ID, &before, reservation); err != nil {
+			return err
+		}
+		// Avisar al huésped de los cambios
+		return events.PublishReservation(tx, events.ReservationModified, *reservation)
+	})
+}
+
 // DeleteReservationHandler elimina una reserva específica por ID
 func DeleteReservationHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -144,8 +356,8 @@ func DeleteReservationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Eliminar la reserva de la base de datos
-	if err := db.DB.Unscoped().Delete(&reservation).Error; err != nil {
+	// Eliminar lógicamente la reserva: puede recuperarse hasta que se purgue
+	if err := deleteReservation(r, reservation); err != nil {
 		// Manejar el error si ocurre al eliminar la reserva
 		http.Error(w, "Failed to delete reservation", http.StatusInternalServerError)
 		return
@@ -154,3 +366,46 @@ func DeleteReservationHandler(w http.ResponseWriter, r *http.Request) {
 	// Devolver un estado 200 OK si la eliminación fue exitosa
 	w.WriteHeader(http.StatusOK)
 }
+
+// deleteReservation elimina lógicamente la reserva, la registra en la auditoría y publica el evento
+func deleteReservation(r *http.Request, reservation models.Reservation) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&reservation).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionDelete, audit.ResourceReservation, reservation.ID, &reservation, nil); err != nil {
+			return err
+		}
+		return events.PublishReservation(tx, events.ReservationDeleted, reservation)
+	})
+}
+
+// findReservation busca la reserva indicada en la URL y responde 404 si no existe
+func findReservation(w http.ResponseWriter, r *http.Request) (models.Reservation, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var reservation models.Reservation
+	if err := db.DB.First(&reservation, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Reservation not found"))
+			return reservation, false
+		}
+		http.Error(w, "Failed to retrieve reservation", http.StatusInternalServerError)
+		return reservation, false
+	}
+	return reservation, true
+}
+
+// stayChanged indica si la modificación cambia las habitaciones que ocupa la reserva
+func stayChanged(before, after models.Reservation) bool {
+	return before.RoomType != after.RoomType || before.NumberOfRooms != after.NumberOfRooms ||
+		!before.Checkin.Equal(after.Checkin) || !before.Checkout.Equal(after.Checkout)
+}
+
+// sameRatePlan indica si dos reservas tienen la misma tarifa, o ninguna
+func sameRatePlan(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,146 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// Configura el router de las reservas para las pruebas de disponibilidad
+func setupReservationRouter() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/reservations", CreateReservationHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}", UpdateReservationHandler).Methods("PUT")
+	r.HandleFunc("/reservations/{id}", PatchReservationHandler).Methods("PATCH")
+	r.HandleFunc("/reservations/{id}", DeleteReservationHandler).Methods("DELETE")
+	r.HandleFunc("/reservations/{id}/restore", RestoreReservationHandler).Methods("POST")
+	return r
+}
+
+func reservationRequest(router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestReservationAvailability(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.User{}, &models.CancellationPolicy{}, &models.RatePlan{}, &models.RoomType{}, &models.GroupBlock{}, &models.GroupAllotment{},
+		&models.Reservation{}, &models.Room{}, &models.RoomAssignment{}, &models.RoomOutage{}, &models.AuditLog{}, &models.DomainEvent{})
+	defer cleanUpDB()
+	defer db.DB.Unscoped().Exec("DELETE FROM room_types")
+	defer db.DB.Exec("DELETE FROM rooms")
+	defer db.DB.Exec("DELETE FROM room_assignments")
+
+	user := models.User{FirstName: "Grace", LastName: "Hopper", Email: "grace.hopper@example.com"}
+	assert.NoError(t, db.DB.Create(&user).Error)
+	assert.NoError(t, db.DB.Create(&models.RoomType{Code: "suite", Name: "Suite", TotalRooms: 2}).Error)
+	router := setupReservationRouter()
+
+	checkin := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	booking := func(rooms int) models.Reservation {
+		return models.Reservation{
+			Checkin:       checkin,
+			Checkout:      checkin.AddDate(0, 0, 3),
+			Adults:        2,
+			NumberOfRooms: rooms,
+			RoomType:      "suite",
+			Email:         user.Email,
+			UserID:        user.ID,
+		}
+	}
+
+	// Las dos habitaciones del tipo se reservan; la siguiente reserva no tiene lugar
+	rr := reservationRequest(router, "POST", "/reservations", booking(1))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var first models.Reservation
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &first))
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "POST", "/reservations", booking(1)).Code)
+	assert.Equal(t, http.StatusConflict, reservationRequest(router, "POST", "/reservations", booking(1)).Code)
+
+	// Ampliar una reserva tampoco puede superar el inventario, pero cambiarle las fechas sí, porque
+	// libera sus propias habitaciones
+	id := strconv.Itoa(int(first.ID))
+	assert.Equal(t, http.StatusConflict, reservationRequest(router, "PATCH", "/reservations/"+id, map[string]int{"number_of_rooms": 2}).Code)
+	moved := booking(1)
+	moved.Checkin = checkin.AddDate(0, 0, 1)
+	moved.Checkout = checkin.AddDate(0, 0, 4)
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "PUT", "/reservations/"+id, moved).Code)
+	reversed := moved
+	reversed.Checkout = moved.Checkin
+	assert.Equal(t, http.StatusBadRequest, reservationRequest(router, "PUT", "/reservations/"+id, reversed).Code)
+
+	// La habitación asignada acompaña el cambio de fechas, pero no un cambio de tipo de habitación
+	room := models.Room{Number: "201", RoomType: "suite", Status: models.RoomInService}
+	assert.NoError(t, db.DB.Create(&room).Error)
+	assignment := models.RoomAssignment{ReservationID: first.ID, RoomID: room.ID, StartDate: moved.Checkin, EndDate: moved.Checkout}
+	assert.NoError(t, db.DB.Create(&assignment).Error)
+	longer := moved
+	longer.Checkout = moved.Checkout.AddDate(0, 0, 1)
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "PUT", "/reservations/"+id, longer).Code)
+	assert.NoError(t, db.DB.First(&assignment, assignment.ID).Error)
+	assert.True(t, assignment.EndDate.Equal(longer.Checkout))
+	assert.Equal(t, http.StatusConflict, reservationRequest(router, "PATCH", "/reservations/"+id, map[string]string{"room_type": "double"}).Code)
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "PUT", "/reservations/"+id, moved).Code)
+	assert.NoError(t, db.DB.Delete(&assignment).Error)
+
+	// Mientras la reserva está eliminada otra toma su habitación, y ya no puede recuperarse
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "DELETE", "/reservations/"+id, nil).Code)
+	assert.Equal(t, http.StatusOK, reservationRequest(router, "POST", "/reservations", moved).Code)
+	assert.Equal(t, http.StatusConflict, reservationRequest(router, "POST", "/reservations/"+id+"/restore", nil).Code)
+}
+
+func TestCreateReservationIgnoresServerFields(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.User{}, &models.Reservation{}, &models.AuditLog{}, &models.DomainEvent{})
+	defer cleanUpDB()
+
+	user := models.User{FirstName: "Grace", LastName: "Hopper", Email: "grace.hopper@example.com"}
+	assert.NoError(t, db.DB.Create(&user).Error)
+	checkin := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	rr := reservationRequest(setupReservationRouter(), "POST", "/reservations", map[string]interface{}{
+		"check_in":          checkin,
+		"check_out":         checkin.AddDate(0, 0, 2),
+		"email":             user.Email,
+		"user_id":           user.ID,
+		"status":            models.ReservationCheckedIn,
+		"cancellation_fee":  100,
+		"external_uid":      "uid@example.com",
+		"channel_reference": "ABC123",
+		"version":           7,
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var created models.Reservation
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, models.ReservationConfirmed, created.Status)
+	assert.Zero(t, created.CancellationFee)
+	assert.Nil(t, created.ExternalUID)
+	assert.Nil(t, created.ChannelRef)
+	assert.Equal(t, uint(1), created.Version)
+}
+
+func TestCreateReservationRequiresCheckoutAfterCheckin(t *testing.T) {
+	checkin := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, checkout := range []time.Time{checkin, checkin.AddDate(0, 0, -1)} {
+		rr := reservationRequest(setupReservationRouter(), "POST", "/reservations", models.Reservation{Checkin: checkin, Checkout: checkout, NumberOfRooms: 1})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+)
+
+// maxRestrictionDays limita el período que se puede modificar en una sola solicitud
+const maxRestrictionDays = 731
+
+// GetRestrictionsHandler obtiene las restricciones de venta de un tipo de habitación entre from y to
+func GetRestrictionsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := parseDate(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDate(query.Get("to"))
+	if err != nil || !to.After(from) {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	conn := db.DB.Where("date >= ? AND date < ?", inventory.Day(from), inventory.Day(to)).Order("room_type asc, date asc")
+	if roomType := query.Get("room_type"); roomType != "" {
+		conn = conn.Where("room_type = ?", roomType)
+	}
+	var restrictions []models.Restriction
+	if err := conn.Find(&restrictions).Error; err != nil {
+		http.Error(w, "Failed to retrieve restrictions", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&restrictions); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// restrictionUpdate son las restricciones que se aplican a un rango de fechas
+type restrictionUpdate struct {
+	RoomType          string `json:"room_type"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	MinStay           int    `json:"min_stay"`
+	ClosedToArrival   bool   `json:"closed_to_arrival"`
+	ClosedToDeparture bool   `json:"closed_to_departure"`
+	StopSell          bool   `json:"stop_sell"`
+}
+
+// UpdateRestrictionsHandler fija las mismas restricciones para cada fecha de llegada entre from y to
+// (sin incluir to) de un tipo de habitación, reemplazando las que hubiera
+func UpdateRestrictionsHandler(w http.ResponseWriter, r *http.Request) {
+	var body restrictionUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RoomType == "" || body.MinStay < 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	from, err := parseDate(body.From)
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDate(body.To)
+	from, to = inventory.Day(from), inventory.Day(to)
+	if err != nil || !to.After(from) || to.After(from.AddDate(0, 0, maxRestrictionDays)) {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+
+	var restrictions []models.Restriction
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			var restriction models.Restriction
+			if err := tx.Where("room_type = ? AND date = ?", body.RoomType, day).Limit(1).Find(&restriction).Error; err != nil {
+				return err
+			}
+			before := restriction
+			restriction.RoomType = body.RoomType
+			restriction.Date = day
+			restriction.MinStay = body.MinStay
+			restriction.ClosedToArrival = body.ClosedToArrival
+			restriction.ClosedToDeparture = body.ClosedToDeparture
+			restriction.StopSell = body.StopSell
+			if err := tx.Save(&restriction).Error; err != nil {
+				return err
+			}
+			if before.ID == 0 {
+				if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceRestriction, restriction.ID, nil, &restriction); err != nil {
+					return err
+				}
+			} else if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRestriction, restriction.ID, &before, &restriction); err != nil {
+				return err
+			}
+			restrictions = append(restrictions, restriction)
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, "Failed to update restrictions", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&restrictions); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,542 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errores de negocio al asignar habitaciones
+var (
+	errRoomNotFound      = errors.New("room not found")
+	errRoomTypeMismatch  = errors.New("room type does not match the reservation")
+	errRoomOutOfService  = errors.New("room is out of service")
+	errRoomTaken         = errors.New("room is already assigned for these dates")
+	errReservationFull   = errors.New("all rooms of the reservation are already assigned")
+	errReservationClosed = errors.New("reservation is cancelled")
+	errAssignmentStarted = errors.New("assignment already started, use a room move instead")
+	errNoSegmentToMove   = errors.New("no room assigned on the move date")
+	errAmbiguousRoomMove = errors.New("several rooms assigned on the move date, from_room_id is required")
+	errInvalidMoveDate   = errors.New("move date must be after the start and before the end of the stay segment")
+)
+
+// writeAssignmentError traduce los errores de asignación a respuestas HTTP
+func writeAssignmentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errRoomNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, errAmbiguousRoomMove), errors.Is(err, errInvalidMoveDate):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errRoomTypeMismatch), errors.Is(err, errRoomOutOfService), errors.Is(err, errRoomTaken),
+		errors.Is(err, errReservationFull), errors.Is(err, errReservationClosed), errors.Is(err, errAssignmentStarted),
+		errors.Is(err, errNoSegmentToMove):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// GetRoomsHandler obtiene las habitaciones, opcionalmente filtradas por room_type y status
+func GetRoomsHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Order("number asc")
+	query := r.URL.Query()
+	if roomType := query.Get("room_type"); roomType != "" {
+		conn = conn.Where("room_type = ?", roomType)
+	}
+	if status := query.Get("status"); status != "" {
+		conn = conn.Where("status = ?", status)
+	}
+
+	var rooms []models.Room
+	if err := conn.Find(&rooms).Error; err != nil {
+		http.Error(w, "Failed to retrieve rooms", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&rooms); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// validRoom indica si los datos de la habitación son aceptables
+func validRoom(room models.Room) bool {
+	return room.Number != "" && room.RoomType != "" && (room.Status == models.RoomInService || room.Status == models.RoomOutOfService)
+}
+
+// CreateRoomHandler crea una nueva habitación
+func CreateRoomHandler(w http.ResponseWriter, r *http.Request) {
+	var room models.Room
+	if err := json.NewDecoder(r.Body).Decode(&room); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if room.Status == "" {
+		room.Status = models.RoomInService
+	}
+	if !validRoom(room) {
+		http.Error(w, "Invalid room", http.StatusBadRequest)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&room).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceRoom, room.ID, nil, &room)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&room); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateRoomHandler actualiza una habitación existente por ID. Si cambia su estado de servicio publica RoomStatusChanged
+func UpdateRoomHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var room models.Room
+	if err := db.DB.First(&room, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve room", http.StatusInternalServerError)
+		return
+	}
+
+	var updatedRoom models.Room
+	if err := json.NewDecoder(r.Body).Decode(&updatedRoom); err != nil || !validRoom(updatedRoom) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before := room
+	room.Number = updatedRoom.Number
+	room.RoomType = updatedRoom.RoomType
+	room.Floor = updatedRoom.Floor
+	room.Status = updatedRoom.Status
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&room).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoom, room.ID, &before, &room); err != nil {
+			return err
+		}
+		if room.Status == before.Status {
+			return nil
+		}
+		return events.PublishRoom(tx, events.RoomStatusChanged, room)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update room", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&room); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetReservationRoomsHandler devuelve los tramos de la estancia con la habitación asignada en cada uno
+func GetReservationRoomsHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var assignments []models.RoomAssignment
+	if err := db.DB.Preload("Room").Where("reservation_id = ?", reservation.ID).Order("start_date asc, id asc").Find(&assignments).Error; err != nil {
+		http.Error(w, "Failed to retrieve room assignments", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&assignments); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// lockAssignableRoom bloquea la habitación y comprueba que pueda asignarse a la reserva en [start, end)
+func lockAssignableRoom(tx *gorm.DB, reservation models.Reservation, roomID uint, start, end time.Time) (models.Room, error) {
+	var room models.Room
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, roomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return room, errRoomNotFound
+		}
+		return room, err
+	}
+	if reservation.RoomType != "" && room.RoomType != reservation.RoomType {
+		return room, errRoomTypeMismatch
+	}
+	if room.Status != models.RoomInService {
+		return room, errRoomOutOfService
+	}
+	free, err := inventory.RoomFree(tx, room.ID, start, end)
+	if err != nil {
+		return room, err
+	}
+	if !free {
+		return room, errRoomTaken
+	}
+	return room, nil
+}
+
+// adjustAssignments lleva las habitaciones asignadas a las fechas nuevas de la reserva, dentro de la
+// transacción que cambia la estancia. Las asignaciones que empezaban en el check-in o terminaban en el
+// check-out anterior se mueven al nuevo, las noches que quedan fuera de la estancia se quitan y las
+// asignaciones sin noches se eliminan. Las noches que se agregan exigen que la habitación siga libre
+func adjustAssignments(tx *gorm.DB, r *http.Request, before, after models.Reservation) error {
+	var assignments []models.RoomAssignment
+	if err := tx.Where("reservation_id = ?", after.ID).Order("start_date asc, id asc").Find(&assignments).Error; err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	if before.RoomType != after.RoomType || before.NumberOfRooms != after.NumberOfRooms {
+		return errRoomsAssigned
+	}
+
+	oldIn, oldOut := inventory.Day(before.Checkin), inventory.Day(before.Checkout)
+	newIn, newOut := inventory.Day(after.Checkin), inventory.Day(after.Checkout)
+	for _, assignment := range assignments {
+		previous := assignment
+		start, end := inventory.Day(assignment.StartDate), inventory.Day(assignment.EndDate)
+		if start.Equal(oldIn) || start.Before(newIn) {
+			start = newIn
+		}
+		if end.Equal(oldOut) || end.After(newOut) {
+			end = newOut
+		}
+
+		if !start.Before(end) {
+			if err := tx.Delete(&assignment).Error; err != nil {
+				return err
+			}
+			if err := audit.Record(tx, r, audit.ActionDelete, audit.ResourceRoomAssignment, assignment.ID, &previous, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if start.Equal(previous.StartDate) && end.Equal(previous.EndDate) {
+			continue
+		}
+
+		// Comprobar solo las noches que la asignación no tenía
+		if start.Before(previous.StartDate) {
+			added := previous.StartDate
+			if end.Before(added) {
+				added = end
+			}
+			if _, err := lockAssignableRoom(tx, after, assignment.RoomID, start, added); err != nil {
+				return err
+			}
+		}
+		if end.After(previous.EndDate) {
+			added := previous.EndDate
+			if start.After(added) {
+				added = start
+			}
+			if _, err := lockAssignableRoom(tx, after, assignment.RoomID, added, end); err != nil {
+				return err
+			}
+		}
+		assignment.StartDate, assignment.EndDate = start, end
+		if err := tx.Model(&assignment).Select("StartDate", "EndDate").Updates(&assignment).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoomAssignment, assignment.ID, &previous, &assignment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roomAssignmentRequest indica la habitación que se asigna a una reserva
+type roomAssignmentRequest struct {
+	RoomID uint `json:"room_id"`
+}
+
+// AssignRoomHandler asigna una habitación a la reserva para toda la estancia
+func AssignRoomHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var body roomAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RoomID == 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var assignment models.RoomAssignment
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloquear la reserva para que dos asignaciones simultáneas no superen sus habitaciones y
+		// para asignar con sus fechas actuales
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, reservation.ID).Error; err != nil {
+			return err
+		}
+		if reservation.Status == models.ReservationCancelled {
+			return errReservationClosed
+		}
+		assignment = models.RoomAssignment{
+			ReservationID: reservation.ID,
+			RoomID:        body.RoomID,
+			StartDate:     inventory.Day(reservation.Checkin),
+			EndDate:       inventory.Day(reservation.Checkout),
+		}
+		// Una reserva de varias habitaciones admite tantas asignaciones simultáneas como habitaciones
+		var assigned int64
+		if err := tx.Model(&models.RoomAssignment{}).
+			Where("reservation_id = ? AND start_date < ? AND end_date > ?", reservation.ID, assignment.EndDate, assignment.StartDate).
+			Count(&assigned).Error; err != nil {
+			return err
+		}
+		rooms := reservation.NumberOfRooms
+		if rooms < 1 {
+			rooms = 1
+		}
+		if int(assigned) >= rooms {
+			return errReservationFull
+		}
+
+		room, err := lockAssignableRoom(tx, reservation, body.RoomID, assignment.StartDate, assignment.EndDate)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&assignment).Error; err != nil {
+			return err
+		}
+		assignment.Room = &room
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceRoomAssignment, assignment.ID, nil, &assignment)
+	})
+	if err != nil {
+		writeAssignmentError(w, err, "Failed to assign room")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&assignment); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UnassignRoomHandler quita una asignación que todavía no empezó
+func UnassignRoomHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var assignment models.RoomAssignment
+	if err := db.DB.Where("reservation_id = ?", reservation.ID).First(&assignment, params["assignment_id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room assignment not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve room assignment", http.StatusInternalServerError)
+		return
+	}
+	// Una vez empezada la estancia el historial se conserva: hay que mover al huésped
+	if !assignment.StartDate.After(inventory.Day(time.Now())) {
+		writeAssignmentError(w, errAssignmentStarted, "")
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&assignment).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceRoomAssignment, assignment.ID, &assignment, nil)
+	})
+	if err != nil {
+		http.Error(w, "Failed to delete room assignment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// roomMoveRequest es el cambio de habitación de una reserva en curso
+type roomMoveRequest struct {
+	RoomID     uint   `json:"room_id"`
+	FromRoomID uint   `json:"from_room_id"`
+	Date       string `json:"date"`
+	Reason     string `json:"reason"`
+}
+
+// MoveRoomHandler cambia de habitación a un huésped durante la estancia: cierra el tramo actual en la
+// fecha del cambio y abre uno nuevo en la habitación indicada hasta el final del tramo original
+func MoveRoomHandler(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := findReservation(w, r)
+	if !ok {
+		return
+	}
+
+	var body roomMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RoomID == 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	// Sin fecha el cambio se hace hoy
+	date := inventory.Day(time.Now())
+	if body.Date != "" {
+		parsed, err := parseDate(body.Date)
+		if err != nil {
+			http.Error(w, "Invalid move date", http.StatusBadRequest)
+			return
+		}
+		date = inventory.Day(parsed)
+	}
+
+	var moved models.RoomAssignment
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Bloquear la reserva para que el cambio no se cruce con otra asignación o cancelación
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, reservation.ID).Error; err != nil {
+			return err
+		}
+		if reservation.Status == models.ReservationCancelled {
+			return errReservationClosed
+		}
+
+		// Buscar el tramo en curso en la fecha del cambio
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reservation_id = ? AND start_date <= ? AND end_date > ?", reservation.ID, date, date)
+		if body.FromRoomID != 0 {
+			query = query.Where("room_id = ?", body.FromRoomID)
+		}
+		var segments []models.RoomAssignment
+		if err := query.Find(&segments).Error; err != nil {
+			return err
+		}
+		switch {
+		case len(segments) == 0:
+			return errNoSegmentToMove
+		case len(segments) > 1:
+			return errAmbiguousRoomMove
+		}
+		current := segments[0]
+		if !date.After(current.StartDate) {
+			return errInvalidMoveDate
+		}
+
+		room, err := lockAssignableRoom(tx, reservation, body.RoomID, date, current.EndDate)
+		if err != nil {
+			return err
+		}
+
+		before := current
+		current.EndDate = date
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoomAssignment, current.ID, &before, &current); err != nil {
+			return err
+		}
+		// La habitación que se deja queda pendiente de limpieza
+		if err := setRoomHousekeeping(tx, r, current.RoomID, models.RoomDirty); err != nil {
+			return err
+		}
+
+		moved = models.RoomAssignment{
+			ReservationID: reservation.ID,
+			RoomID:        room.ID,
+			StartDate:     date,
+			EndDate:       before.EndDate,
+			Reason:        body.Reason,
+		}
+		if err := tx.Create(&moved).Error; err != nil {
+			return err
+		}
+		moved.Room = &room
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceRoomAssignment, moved.ID, nil, &moved)
+	})
+	if err != nil {
+		writeAssignmentError(w, err, "Failed to move room")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&moved); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// roomSuggestion es la propuesta de habitaciones para una llegada
+type roomSuggestion struct {
+	Reservation models.Reservation `json:"reservation"`
+	Missing     int                `json:"missing"`
+	Rooms       []models.Room      `json:"rooms"`
+}
+
+// GetRoomSuggestionsHandler propone habitaciones libres para las llegadas del día indicado (por defecto mañana)
+// que todavía no tienen todas sus habitaciones asignadas
+func GetRoomSuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	date := inventory.Day(time.Now()).AddDate(0, 0, 1)
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := parseDate(value)
+		if err != nil {
+			http.Error(w, "Invalid date", http.StatusBadRequest)
+			return
+		}
+		date = inventory.Day(parsed)
+	}
+
+	var arrivals []models.Reservation
+	if err := db.DB.Where("checkin >= ? AND checkin < ? AND status <> ?", date, date.AddDate(0, 0, 1), models.ReservationCancelled).
+		Order("id asc").Find(&arrivals).Error; err != nil {
+		http.Error(w, "Failed to retrieve arrivals", http.StatusInternalServerError)
+		return
+	}
+
+	candidates := make(map[uint][]models.Room)
+	missing := make(map[uint]int)
+	for _, reservation := range arrivals {
+		var assigned int64
+		if err := db.DB.Model(&models.RoomAssignment{}).Where("reservation_id = ?", reservation.ID).Count(&assigned).Error; err != nil {
+			http.Error(w, "Failed to retrieve room assignments", http.StatusInternalServerError)
+			return
+		}
+		rooms := reservation.NumberOfRooms
+		if rooms < 1 {
+			rooms = 1
+		}
+		if missing[reservation.ID] = rooms - int(assigned); missing[reservation.ID] <= 0 {
+			continue
+		}
+		free, err := inventory.FreeRooms(db.DB, reservation.RoomType, reservation.Checkin, reservation.Checkout)
+		if err != nil {
+			http.Error(w, "Failed to retrieve free rooms", http.StatusInternalServerError)
+			return
+		}
+		candidates[reservation.ID] = free
+	}
+
+	suggested := inventory.Suggest(arrivals, candidates, missing)
+	suggestions := make([]roomSuggestion, 0, len(arrivals))
+	for _, reservation := range arrivals {
+		if missing[reservation.ID] <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, roomSuggestion{Reservation: reservation, Missing: missing[reservation.ID], Rooms: suggested[reservation.ID]})
+	}
+
+	if err := json.NewEncoder(w).Encode(&suggestions); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// GetRoomTypesHandler obtiene todos los tipos de habitación en orden ascendente por ID
+func GetRoomTypesHandler(w http.ResponseWriter, r *http.Request) {
+	var roomTypes []models.RoomType
+	if err := db.DB.Order("id asc").Find(&roomTypes).Error; err != nil {
+		http.Error(w, "Failed to retrieve room types", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&roomTypes); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateRoomTypeHandler crea un nuevo tipo de habitación
+func CreateRoomTypeHandler(w http.ResponseWriter, r *http.Request) {
+	var roomType models.RoomType
+	if err := json.NewDecoder(r.Body).Decode(&roomType); err != nil || roomType.Code == "" || roomType.TotalRooms < 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&roomType).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceRoomType, roomType.ID, nil, &roomType)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&roomType); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateRoomTypeHandler actualiza un tipo de habitación existente por ID
+func UpdateRoomTypeHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var roomType models.RoomType
+	if err := db.DB.First(&roomType, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room type not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve room type", http.StatusInternalServerError)
+		return
+	}
+
+	var updatedRoomType models.RoomType
+	if err := json.NewDecoder(r.Body).Decode(&updatedRoomType); err != nil || updatedRoomType.Code == "" || updatedRoomType.TotalRooms < 0 {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before := roomType
+	roomType.Code = updatedRoomType.Code
+	roomType.Name = updatedRoomType.Name
+	roomType.TotalRooms = updatedRoomType.TotalRooms
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&roomType).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoomType, roomType.ID, &before, &roomType)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update room type", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&roomType); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// parseDate interpreta una fecha en formato AAAA-MM-DD o RFC 3339
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// availabilityResult es la disponibilidad de un tipo de habitación en un rango de fechas
+type availabilityResult struct {
+	RoomType  string    `json:"room_type"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Available int       `json:"available"`
+}
+
+// GetAvailabilityHandler informa cuántas habitaciones de un tipo quedan libres entre dos fechas
+func GetAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := parseDate(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	to, err := parseDate(query.Get("to"))
+	if err != nil || !to.After(from) {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+	roomType := query.Get("room_type")
+
+	available, err := inventory.Availability(db.DB, roomType, from, to)
+	if err != nil {
+		http.Error(w, "Failed to compute availability", http.StatusInternalServerError)
+		return
+	}
+	if available < 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Room type not found"))
+		return
+	}
+
+	response := availabilityResult{roomType, from, to, available}
+	if err := json.NewEncoder(w).Encode(&response); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,176 @@
+package routes
+
+import (
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/gorilla/mux"
+)
+
+// NewRouter crea el enrutador con todas las rutas de la API. Cada ruta nueva debe documentarse también
+// en la especificación OpenAPI (openapi.routes.go)
+func NewRouter() *mux.Router {
+	r := mux.NewRouter()
+
+	// Rutas para User
+	r.HandleFunc("/users", GetUsersHandler).Methods("GET")
+	r.HandleFunc("/users/{id}", GetUserHandler).Methods("GET")
+	r.HandleFunc("/users", PostUserHandler).Methods("POST")
+	r.HandleFunc("/users/{id}", UpdateUserHandler).Methods("PUT")
+	r.HandleFunc("/users/{id}", PatchUserHandler).Methods("PATCH")
+	r.HandleFunc("/users/{id}", DeleteUserHandler).Methods("DELETE")
+	r.HandleFunc("/users/{id}/restore", middleware.RequireRole(middleware.RoleAdmin, RestoreUserHandler)).Methods("POST")
+
+	// Rutas para Reservation
+	r.HandleFunc("/reservations", GetReservationsHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}", GetReservationHandler).Methods("GET")
+	r.HandleFunc("/reservations", CreateReservationHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}", UpdateReservationHandler).Methods("PUT")
+	r.HandleFunc("/reservations/{id}", PatchReservationHandler).Methods("PATCH")
+	r.HandleFunc("/reservations/{id}", DeleteReservationHandler).Methods("DELETE")
+	r.HandleFunc("/reservations/{id}/restore", middleware.RequireRole(middleware.RoleAdmin, RestoreReservationHandler)).Methods("POST")
+	r.HandleFunc("/reservations/{id}/cancellation", GetCancellationQuoteHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/cancel", CancelReservationHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/confirm", ConfirmReservationHandler).Methods("POST")
+
+	// Rutas para RatePlan y CancellationPolicy
+	r.HandleFunc("/rate-plans", GetRatePlansHandler).Methods("GET")
+	r.HandleFunc("/rate-plans", CreateRatePlanHandler).Methods("POST")
+	r.HandleFunc("/rate-plans/{id}", UpdateRatePlanHandler).Methods("PUT")
+	r.HandleFunc("/cancellation-policies", GetCancellationPoliciesHandler).Methods("GET")
+	r.HandleFunc("/cancellation-policies", CreateCancellationPolicyHandler).Methods("POST")
+	r.HandleFunc("/cancellation-policies/{id}", UpdateCancellationPolicyHandler).Methods("PUT")
+
+	// Rutas para RoomType e inventario
+	r.HandleFunc("/room-types", GetRoomTypesHandler).Methods("GET")
+	r.HandleFunc("/room-types", CreateRoomTypeHandler).Methods("POST")
+	r.HandleFunc("/room-types/{id}", UpdateRoomTypeHandler).Methods("PUT")
+	r.HandleFunc("/availability", GetAvailabilityHandler).Methods("GET")
+	r.HandleFunc("/room-types/{id}/calendar.ics", GetRoomTypeCalendarHandler).Methods("GET")
+	r.HandleFunc("/room-types/{id}/calendar/import", middleware.RequireRole(middleware.RoleManager, ImportRoomTypeCalendarHandler)).Methods("POST")
+
+	// Rutas para Room y asignación de habitaciones
+	r.HandleFunc("/rooms", GetRoomsHandler).Methods("GET")
+	r.HandleFunc("/rooms", CreateRoomHandler).Methods("POST")
+	r.HandleFunc("/rooms/{id}", UpdateRoomHandler).Methods("PUT")
+	r.HandleFunc("/room-suggestions", GetRoomSuggestionsHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/rooms", GetReservationRoomsHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/rooms", AssignRoomHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/rooms/{assignment_id}", UnassignRoomHandler).Methods("DELETE")
+	r.HandleFunc("/reservations/{id}/move", MoveRoomHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/check-in", CheckInReservationHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/check-out", CheckOutReservationHandler).Methods("POST")
+
+	// Rutas para Housekeeping
+	r.HandleFunc("/rooms/{id}/housekeeping", UpdateRoomHousekeepingHandler).Methods("PUT")
+	r.HandleFunc("/housekeeping/tasks", GetHousekeepingTasksHandler).Methods("GET")
+	r.HandleFunc("/housekeeping/tasks/generate", middleware.RequireRole(middleware.RoleManager, GenerateHousekeepingTasksHandler)).Methods("POST")
+	r.HandleFunc("/housekeeping/tasks/{id}", UpdateHousekeepingTaskHandler).Methods("PUT")
+
+	// Rutas para WorkOrder y RoomOutage
+	r.HandleFunc("/work-orders", GetWorkOrdersHandler).Methods("GET")
+	r.HandleFunc("/work-orders", CreateWorkOrderHandler).Methods("POST")
+	r.HandleFunc("/work-orders/{id}", GetWorkOrderHandler).Methods("GET")
+	r.HandleFunc("/work-orders/{id}", UpdateWorkOrderHandler).Methods("PUT")
+	r.HandleFunc("/room-outages", GetRoomOutagesHandler).Methods("GET")
+	r.HandleFunc("/room-outages", CreateRoomOutageHandler).Methods("POST")
+	r.HandleFunc("/room-outages/{id}/end", EndRoomOutageHandler).Methods("POST")
+
+	// Rutas para GroupBlock
+	r.HandleFunc("/groups", GetGroupBlocksHandler).Methods("GET")
+	r.HandleFunc("/groups", CreateGroupBlockHandler).Methods("POST")
+	r.HandleFunc("/groups/{id}", GetGroupBlockHandler).Methods("GET")
+	r.HandleFunc("/groups/{id}/pickups", PickupGroupRoomHandler).Methods("POST")
+	r.HandleFunc("/groups/{id}/release", ReleaseGroupBlockHandler).Methods("POST")
+	r.HandleFunc("/groups/{id}/folio", GetGroupFolioHandler).Methods("GET")
+	r.HandleFunc("/groups/{id}/folio/charges", PostGroupFolioChargeHandler).Methods("POST")
+
+	// Rutas para Consultation
+	r.HandleFunc("/consultations", GetConsultationsHandler).Methods("GET")
+	r.HandleFunc("/consultations/{id}", GetConsultationHandler).Methods("GET")
+	r.HandleFunc("/consultations", CreateConsultationHandler).Methods("POST")
+	r.HandleFunc("/consultations/{id}", UpdateConsultationHandler).Methods("PUT")
+	r.HandleFunc("/consultations/{id}", DeleteConsultationHandler).Methods("DELETE")
+	r.HandleFunc("/consultations/{id}/restore", middleware.RequireRole(middleware.RoleAdmin, RestoreConsultationHandler)).Methods("POST")
+	r.HandleFunc("/consultations/{id}/messages", GetConsultationMessagesHandler).Methods("GET")
+	r.HandleFunc("/consultations/{id}/messages", PostConsultationMessageHandler).Methods("POST")
+	r.HandleFunc("/consultations/{id}/assign", middleware.RequireRole(middleware.RoleStaff, AssignConsultationHandler)).Methods("POST")
+	r.HandleFunc("/consultations/{id}/status", middleware.RequireRole(middleware.RoleStaff, UpdateConsultationStatusHandler)).Methods("PUT")
+	r.HandleFunc("/consultations/{id}/convert", middleware.RequireRole(middleware.RoleStaff, ConvertConsultationHandler)).Methods("POST")
+	r.HandleFunc("/reports/consultation-conversions", middleware.RequireRole(middleware.RoleManager, GetConversionStatsHandler)).Methods("GET")
+
+	// Rutas para Employee
+	r.HandleFunc("/employees", GetEmployeesHandler).Methods("GET")
+	r.HandleFunc("/employees/{id}", GetEmployeeHandler).Methods("GET")
+	r.HandleFunc("/employees", PostEmployeeHandler).Methods("POST")
+	r.HandleFunc("/employees/{id}", UpdateEmployeeHandler).Methods("PUT")
+	r.HandleFunc("/employees/{id}", PatchEmployeeHandler).Methods("PATCH")
+	r.HandleFunc("/employees/{id}", DeleteEmployeeHandler).Methods("DELETE")
+	r.HandleFunc("/employees/{id}/restore", middleware.RequireRole(middleware.RoleAdmin, RestoreEmployeeHandler)).Methods("POST")
+
+	// Rutas para Folio y Payment
+	r.HandleFunc("/reservations/{id}/folio", GetReservationFolioHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/folio/charges", PostFolioChargeHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/payments", GetReservationPaymentsHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/payments", CreatePaymentHandler).Methods("POST")
+	r.HandleFunc("/payments/webhook", PaymentWebhookHandler).Methods("POST")
+	r.HandleFunc("/payments/{id}/capture", CapturePaymentHandler).Methods("POST")
+	r.HandleFunc("/payments/{id}/refund", RefundPaymentHandler).Methods("POST")
+	r.HandleFunc("/payments/{id}/void", VoidPaymentHandler).Methods("POST")
+
+	// Rutas para Invoice
+	r.HandleFunc("/reservations/{id}/invoices", GetReservationInvoicesHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/invoices", PostReservationInvoiceHandler).Methods("POST")
+	r.HandleFunc("/reservations/{id}/invoice.pdf", GetReservationInvoicePDFHandler).Methods("GET")
+	r.HandleFunc("/reservations/{id}/invoice.html", GetReservationInvoiceHTMLHandler).Methods("GET")
+	r.HandleFunc("/invoices/{id:[0-9]+}.pdf", GetInvoicePDFHandler).Methods("GET")
+	r.HandleFunc("/invoices/{id:[0-9]+}.html", GetInvoiceHTMLHandler).Methods("GET")
+
+	// Rutas para DomainEvent
+	r.HandleFunc("/events", middleware.RequireRole(middleware.RoleManager, GetEventsHandler)).Methods("GET")
+	r.HandleFunc("/events/stream", StreamEventsHandler).Methods("GET")
+	r.HandleFunc("/events/{id}/retry", middleware.RequireRole(middleware.RoleManager, RetryEventHandler)).Methods("POST")
+
+	// Rutas para exportaciones
+	r.HandleFunc("/exports/reservations", middleware.RequireRole(middleware.RoleStaff, ExportReservationsHandler)).Methods("GET")
+	r.HandleFunc("/exports/users", middleware.RequireRole(middleware.RoleStaff, ExportUsersHandler)).Methods("GET")
+	r.HandleFunc("/exports/consultations", middleware.RequireRole(middleware.RoleStaff, ExportConsultationsHandler)).Methods("GET")
+	r.HandleFunc("/exports/employees", middleware.RequireRole(middleware.RoleStaff, ExportEmployeesHandler)).Methods("GET")
+
+	// Rutas para Restriction y Channel
+	r.HandleFunc("/restrictions", GetRestrictionsHandler).Methods("GET")
+	r.HandleFunc("/restrictions", middleware.RequireRole(middleware.RoleManager, UpdateRestrictionsHandler)).Methods("PUT")
+	r.HandleFunc("/channels", middleware.RequireRole(middleware.RoleManager, GetChannelsHandler)).Methods("GET")
+	r.HandleFunc("/channels", middleware.RequireRole(middleware.RoleManager, CreateChannelHandler)).Methods("POST")
+	r.HandleFunc("/channels/{id}", middleware.RequireRole(middleware.RoleManager, GetChannelHandler)).Methods("GET")
+	r.HandleFunc("/channels/{id}", middleware.RequireRole(middleware.RoleManager, UpdateChannelHandler)).Methods("PUT")
+	r.HandleFunc("/channels/{id}/mappings", middleware.RequireRole(middleware.RoleManager, UpdateChannelMappingsHandler)).Methods("PUT")
+	r.HandleFunc("/channels/{id}/push", middleware.RequireRole(middleware.RoleManager, PushChannelHandler)).Methods("POST")
+	r.HandleFunc("/channels/{id}/pull", middleware.RequireRole(middleware.RoleManager, PullChannelHandler)).Methods("POST")
+	r.HandleFunc("/channels/{id}/conflicts", middleware.RequireRole(middleware.RoleManager, GetChannelConflictsHandler)).Methods("GET")
+	r.HandleFunc("/channel-conflicts/{id}/resolve", middleware.RequireRole(middleware.RoleManager, ResolveChannelConflictHandler)).Methods("POST")
+
+	// Rutas para WebhookSubscription y WebhookDelivery
+	r.HandleFunc("/webhooks", middleware.RequireRole(middleware.RoleManager, GetWebhooksHandler)).Methods("GET")
+	r.HandleFunc("/webhooks", middleware.RequireRole(middleware.RoleManager, CreateWebhookHandler)).Methods("POST")
+	r.HandleFunc("/webhooks/dead-letters", middleware.RequireRole(middleware.RoleManager, GetWebhookDeadLettersHandler)).Methods("GET")
+	r.HandleFunc("/webhooks/{id:[0-9]+}", middleware.RequireRole(middleware.RoleManager, GetWebhookHandler)).Methods("GET")
+	r.HandleFunc("/webhooks/{id:[0-9]+}", middleware.RequireRole(middleware.RoleManager, UpdateWebhookHandler)).Methods("PUT")
+	r.HandleFunc("/webhooks/{id:[0-9]+}", middleware.RequireRole(middleware.RoleManager, DeleteWebhookHandler)).Methods("DELETE")
+	r.HandleFunc("/webhooks/{id:[0-9]+}/deliveries", middleware.RequireRole(middleware.RoleManager, GetWebhookDeliveriesHandler)).Methods("GET")
+	r.HandleFunc("/webhook-deliveries/{id}/replay", middleware.RequireRole(middleware.RoleManager, ReplayWebhookDeliveryHandler)).Methods("POST")
+
+	// Rutas para Notification
+	r.HandleFunc("/notifications", middleware.RequireRole(middleware.RoleManager, GetNotificationsHandler)).Methods("GET")
+	r.HandleFunc("/notifications/{id}/retry", middleware.RequireRole(middleware.RoleManager, RetryNotificationHandler)).Methods("POST")
+
+	// Rutas para AuditLog
+	r.HandleFunc("/audit", middleware.RequireRole(middleware.RoleManager, GetAuditLogsHandler)).Methods("GET")
+
+	// Ruta para GraphQL
+	r.HandleFunc("/graphql", GraphQLHandler).Methods("GET", "POST")
+
+	// Rutas para la especificación OpenAPI
+	r.HandleFunc("/openapi.json", GetOpenAPIHandler).Methods("GET")
+	r.HandleFunc("/docs", GetDocsHandler).Methods("GET")
+
+	return r
+}
@@ -0,0 +1,138 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// readDB devuelve la conexión a usar en las consultas de lectura. Con ?include_deleted=true los
+// administradores también ven los registros eliminados; el resto recibe un 403
+func readDB(w http.ResponseWriter, r *http.Request) (*gorm.DB, bool) {
+	if r.URL.Query().Get("include_deleted") != "true" {
+		return db.DB, true
+	}
+	if !middleware.HasRole(r, middleware.RoleAdmin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return db.DB.Unscoped(), true
+}
+
+// errRestoreConflict indica que otro registro activo usa el email del registro a recuperar
+var errRestoreConflict = errors.New("email already in use")
+
+// restoreRecord recupera un registro eliminado lógicamente y lo devuelve en formato JSON. Si se indica,
+// check se ejecuta en la misma transacción antes de recuperarlo y puede rechazar la recuperación
+func restoreRecord(w http.ResponseWriter, r *http.Request, record interface{}, resourceType, notFound string, check func(tx *gorm.DB, id uint) error) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	id, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if check != nil {
+			if err := check(tx, uint(id)); err != nil {
+				return err
+			}
+		}
+		// Quitar la marca de eliminación solo si el registro estaba eliminado
+		result := tx.Unscoped().Model(record).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+		// El email solo es único entre los registros no eliminados: otro registro puede estar usándolo
+		if result.Error != nil && strings.Contains(result.Error.Error(), "SQLSTATE 23505") {
+			return errRestoreConflict
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.First(record, id).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionRestore, resourceType, uint(id), nil, record)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(notFound))
+		return
+	}
+	if errors.Is(err, errRestoreConflict) {
+		http.Error(w, "Email already in use", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, inventory.ErrNoAvailability) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to restore record", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(record); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// RestoreUserHandler recupera un usuario eliminado
+func RestoreUserHandler(w http.ResponseWriter, r *http.Request) {
+	restoreRecord(w, r, &models.User{}, audit.ResourceUser, "Deleted user not found", nil)
+}
+
+// RestoreReservationHandler recupera una reserva eliminada
+func RestoreReservationHandler(w http.ResponseWriter, r *http.Request) {
+	restoreRecord(w, r, &models.Reservation{}, audit.ResourceReservation, "Deleted reservation not found", checkReservationRestore)
+}
+
+// checkReservationRestore comprueba que sigan libres el inventario y las habitaciones asignadas de una
+// reserva vigente antes de recuperarla: mientras estuvo eliminada otras reservas pudieron ocuparlos
+func checkReservationRestore(tx *gorm.DB, id uint) error {
+	var reservation models.Reservation
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&reservation, id).Error; err != nil {
+		return err
+	}
+	if reservation.Status == models.ReservationCancelled {
+		return nil
+	}
+	if err := inventory.Reserve(tx, reservation.RoomType, reservation.Checkin, reservation.Checkout, reservation.NumberOfRooms); err != nil {
+		return err
+	}
+	var assignments []models.RoomAssignment
+	if err := tx.Where("reservation_id = ?", id).Find(&assignments).Error; err != nil {
+		return err
+	}
+	for _, assignment := range assignments {
+		free, err := inventory.RoomFree(tx, assignment.RoomID, assignment.StartDate, assignment.EndDate)
+		if err != nil {
+			return err
+		}
+		if !free {
+			return inventory.ErrNoAvailability
+		}
+	}
+	return nil
+}
+
+// RestoreConsultationHandler recupera una consulta eliminada
+func RestoreConsultationHandler(w http.ResponseWriter, r *http.Request) {
+	restoreRecord(w, r, &models.Consultation{}, audit.ResourceConsultation, "Deleted consultation not found", nil)
+}
+
+// RestoreEmployeeHandler recupera un empleado eliminado
+func RestoreEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	restoreRecord(w, r, &models.Employee{}, audit.ResourceEmployee, "Deleted employee not found", nil)
+}
@@ -0,0 +1,181 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/webhooks"
+)
+
+// sseHeartbeat es cada cuánto se envía un comentario al cliente para que los proxies no corten la
+// conexión mientras no hay cambios
+var sseHeartbeat = 15 * time.Second
+
+// sseRetry es la espera, en milisegundos, que se pide al navegador antes de reconectarse
+const sseRetry = 3000
+
+// streamTopic agrupa los tipos de agregado que recibe un tema y el rol mínimo para suscribirse. El
+// estado de las habitaciones no tiene datos personales; las reservas y las consultas sí
+type streamTopic struct {
+	aggregateType string
+	role          string // Vacío si no requiere autenticación
+}
+
+// streamTopics son los temas de GET /events/stream. Las reservas incluyen la llegada y la salida de los huéspedes
+var streamTopics = map[string]streamTopic{
+	"rooms":         {aggregateType: events.AggregateRoom},
+	"reservations":  {aggregateType: events.AggregateReservation, role: middleware.RoleStaff},
+	"consultations": {aggregateType: events.AggregateConsultation, role: middleware.RoleStaff},
+}
+
+var (
+	errInvalidTopic   = errors.New("Invalid topic")
+	errForbiddenTopic = errors.New("Forbidden")
+)
+
+// allowedTopic indica si quien hace la solicitud puede suscribirse al tema
+func allowedTopic(r *http.Request, topic streamTopic) bool {
+	return topic.role == "" || middleware.HasRole(r, topic.role)
+}
+
+// streamAggregateTypes devuelve los tipos de agregado de los temas pedidos en ?topics=, separados por
+// comas. Sin el parámetro se envían todos los temas que permite el rol del principal
+func streamAggregateTypes(r *http.Request) ([]string, error) {
+	var aggregateTypes []string
+	param := r.URL.Query().Get("topics")
+	if param == "" {
+		for _, name := range []string{"rooms", "reservations", "consultations"} {
+			if topic := streamTopics[name]; allowedTopic(r, topic) {
+				aggregateTypes = append(aggregateTypes, topic.aggregateType)
+			}
+		}
+		return aggregateTypes, nil
+	}
+
+	for _, name := range strings.Split(param, ",") {
+		topic, ok := streamTopics[strings.TrimSpace(name)]
+		if !ok {
+			return nil, errInvalidTopic
+		}
+		if !allowedTopic(r, topic) {
+			return nil, errForbiddenTopic
+		}
+		aggregateTypes = append(aggregateTypes, topic.aggregateType)
+	}
+	return aggregateTypes, nil
+}
+
+// lastEventID devuelve el id desde el que se retoma el stream: la cabecera Last-Event-ID que envía el
+// navegador al reconectarse o el parámetro last_event_id para la primera conexión
+func lastEventID(r *http.Request) (uint, bool, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	return uint(id), true, err
+}
+
+// writeSSE escribe un evento de dominio con el mismo cuerpo que las entregas de los webhooks
+func writeSSE(w http.ResponseWriter, event models.DomainEvent) error {
+	data, err := json.Marshal(webhooks.Envelope{
+		ID:            event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// StreamEventsHandler envía como server-sent events los cambios de las reservas (incluidas la llegada
+// y la salida), de las habitaciones y de las consultas a medida que se confirman. Cada evento lleva su
+// id, así que el navegador se reconecta con Last-Event-ID y recibe los que se perdió; sin él se
+// reciben solo los cambios posteriores a la conexión
+func StreamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	aggregateTypes, err := streamAggregateTypes(r)
+	if errors.Is(err, errForbiddenTopic) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	afterID, resume, err := lastEventID(r)
+	if err != nil {
+		http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	if !resume {
+		if afterID, err = events.LastID(db.DB); err != nil {
+			http.Error(w, "Failed to retrieve events", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Evita que nginx guarde el stream en su buffer
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+	flusher.Flush()
+
+	// Follow lee la bandeja de salida en otra goroutine; las escrituras se hacen solo en esta
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	found := make(chan models.DomainEvent)
+	done := make(chan error, 1)
+	go func() {
+		done <- events.Follow(ctx, db.DB, afterID, aggregateTypes, func(event models.DomainEvent) error {
+			select {
+			case found <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event := <-found:
+			if err := writeSSE(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-done:
+			// La conexión se cerró o falló la lectura de la bandeja; el navegador se reconecta solo
+			return
+		}
+	}
+}
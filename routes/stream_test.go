@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStreamRouter() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/events/stream", StreamEventsHandler).Methods("GET")
+	return middleware.AuthWithTokens(middleware.ParseTokens("staff-token:staff:bob"), middleware.ValidationWithResponses(OpenAPI, true, r))
+}
+
+func TestStreamEventsInvalidRequests(t *testing.T) {
+	router := setupStreamRouter()
+	cases := []struct {
+		name   string
+		url    string
+		header string
+		status int
+	}{
+		{"Tema desconocido", "/events/stream?topics=rooms,payments", "", http.StatusBadRequest},
+		{"Reservas sin autenticación", "/events/stream?topics=reservations", "", http.StatusForbidden},
+		{"Last-Event-ID inválido", "/events/stream", "abc", http.StatusBadRequest},
+		{"Parámetro last_event_id inválido", "/events/stream?last_event_id=abc", "", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", c.url, nil)
+			if c.header != "" {
+				req.Header.Set("Last-Event-ID", c.header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, c.status, rr.Code)
+		})
+	}
+}
+
+// sseEvent es un evento leído del stream
+type sseEvent struct {
+	id        string
+	eventType string
+	data      string
+}
+
+// readSSE lee eventos del stream, salteando los comentarios y la línea retry, hasta juntar count
+func readSSE(t *testing.T, reader *bufio.Reader, count int) []sseEvent {
+	var found []sseEvent
+	var current sseEvent
+	for len(found) < count {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return found
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if current.eventType != "" {
+				found = append(found, current)
+			}
+			current = sseEvent{}
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			current.eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	return found
+}
+
+func TestStreamEvents(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.DomainEvent{})
+	defer db.DB.Exec("DELETE FROM domain_events")
+	defer func(interval time.Duration) { events.FollowInterval = interval }(events.FollowInterval)
+	events.FollowInterval = 10 * time.Millisecond
+
+	server := httptest.NewServer(setupStreamRouter())
+	defer server.Close()
+	lastID, err := events.LastID(db.DB)
+	assert.NoError(t, err)
+
+	reservation := models.Reservation{GuestName: "Grace Hopper"}
+	reservation.ID = 7
+	room := models.Room{Number: "101", Status: models.RoomInService, Housekeeping: models.RoomClean}
+	room.ID = 3
+	assert.NoError(t, events.PublishReservation(db.DB, events.GuestCheckedIn, reservation))
+	assert.NoError(t, events.PublishRoom(db.DB, events.RoomStatusChanged, room))
+
+	// Un cliente anónimo solo recibe el estado de las habitaciones
+	req, _ := http.NewRequest("GET", server.URL+"/events/stream", nil)
+	req.Header.Set("Last-Event-ID", strconv.Itoa(int(lastID)))
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	received := readSSE(t, bufio.NewReader(resp.Body), 1)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, events.RoomStatusChanged, received[0].eventType)
+		assert.Contains(t, received[0].data, `"aggregate_type":"room"`)
+	}
+
+	// El personal retoma desde el último evento recibido y sigue recibiendo los cambios nuevos
+	req, _ = http.NewRequest("GET", server.URL+"/events/stream", nil)
+	req.Header.Set("Authorization", "Bearer staff-token")
+	req.Header.Set("Last-Event-ID", strconv.Itoa(int(lastID)))
+	staff, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer staff.Body.Close()
+	reader := bufio.NewReader(staff.Body)
+	received = readSSE(t, reader, 2)
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, events.GuestCheckedIn, received[0].eventType)
+		assert.Equal(t, events.RoomStatusChanged, received[1].eventType)
+	}
+	assert.NoError(t, events.PublishReservation(db.DB, events.GuestCheckedOut, reservation))
+	received = readSSE(t, reader, 1)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, events.GuestCheckedOut, received[0].eventType)
+	}
+}
@@ -4,16 +4,23 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/mergepatch"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
 // GetUsersHandler obtiene todos los usuarios desde la base de datos en orden ascendente por ID y los devuelve en formato JSON
 func GetUsersHandler(w http.ResponseWriter, r *http.Request) {
 	var users []models.User
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
 	// Buscar todos los usuarios en la base de datos y ordenarlos por ID en orden ascendente
-	if err := db.DB.Order("id asc").Find(&users).Error; err != nil {
+	if err := conn.Order("id asc").Find(&users).Error; err != nil {
 		// Manejar el error si ocurre al buscar los usuarios
 		http.Error(w, "Failed to retrieve users", http.StatusInternalServerError)
 		return
@@ -31,9 +38,13 @@ func GetUsersHandler(w http.ResponseWriter, r *http.Request) {
 func GetUserHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
 	var user models.User
+	conn, ok := readDB(w, r)
+	if !ok {
+		return
+	}
 
 	// Buscar un usuario específico por ID e incluir reservas y consultas asociadas
-	if err := db.DB.Preload("Reservations").Preload("Consultations").First(&user, params["id"]).Error; err != nil {
+	if err := conn.Preload("Reservations").Preload("Consultations").First(&user, params["id"]).Error; err != nil {
 		// Verificar si el usuario no fue encontrado o si hubo un error
 		if err.Error() == "record not found" {
 			// Si el usuario no existe, devolver un error 404
@@ -46,7 +57,8 @@ func GetUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Codificar el usuario en formato JSON y enviarlo como respuesta
+	// Codificar el usuario en formato JSON y enviarlo como respuesta junto con su versión
+	setETag(w, user.Version)
 	if err := json.NewEncoder(w).Encode(&user); err != nil {
 		// Manejar el error si ocurre al codificar el JSON
 		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
@@ -64,9 +76,8 @@ func PostUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Crear el nuevo usuario en la base de datos
-	createdUser := db.DB.Create(&user)
-	if err := createdUser.Error; err != nil {
+	// Crear el nuevo usuario en la base de datos y registrarlo en la auditoría
+	if err := createUser(r, &user); err != nil {
 		// Manejar el error si ocurre al crear el usuario
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -79,6 +90,17 @@ func PostUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// createUser da de alta el usuario y lo registra en la auditoría. La comparten el handler REST y el
+// servicio gRPC
+func createUser(r *http.Request, user *models.User) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceUser, user.ID, nil, user)
+	})
+}
+
 // UpdateUserHandler actualiza un usuario existente por ID con los datos proporcionados
 func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -97,6 +119,11 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Rechazar la escritura si el cliente editó una versión anterior
+	if !checkIfMatch(w, r, user.Version) {
+		return
+	}
+
 	// Decodificar el cuerpo de la solicitud para obtener los datos actualizados
 	var updatedUser models.User
 	if err := json.NewDecoder(r.Body).Decode(&updatedUser); err != nil {
@@ -106,15 +133,13 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Actualizar los campos del usuario existente con los datos proporcionados
-	user.FirstName = updatedUser.FirstName
-	user.LastName = updatedUser.LastName
-	user.Email = updatedUser.Email
 	// Nota: No actualizamos el campo `Reservations` ya que es una relación y no suele actualizarse directamente en un PUT
+	fields := userFields{FirstName: updatedUser.FirstName, LastName: updatedUser.LastName, Email: updatedUser.Email}
 
-	// Guardar los cambios en la base de datos
-	if err := db.DB.Save(&user).Error; err != nil {
+	// Guardar los cambios en la base de datos junto con su registro de auditoría
+	if err := updateUser(r, &user, fields); err != nil {
 		// Manejar el error si ocurre al guardar el usuario actualizado
-		http.Error(w, "Failed to update user", http.StatusInternalServerError)
+		writeSaveError(w, err, "Failed to update user")
 		return
 	}
 
@@ -125,12 +150,76 @@ func UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Codificar el usuario actualizado en formato JSON y enviarlo como respuesta
+	setETag(w, user.Version)
 	if err := json.NewEncoder(w).Encode(&user); err != nil {
 		// Manejar el error si ocurre al codificar el JSON
 		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
 	}
 }
 
+// userFields son los campos de un usuario que el cliente puede modificar
+type userFields struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// PatchUserHandler modifica parcialmente un usuario aplicando un JSON Merge Patch:
+// los campos omitidos se conservan
+func PatchUserHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var user models.User
+	if err := db.DB.First(&user, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("User not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve user", http.StatusInternalServerError)
+		return
+	}
+	if !checkIfMatch(w, r, user.Version) {
+		return
+	}
+	patch, ok := readMergePatch(w, r)
+	if !ok {
+		return
+	}
+
+	// Aplicar el parche sobre los campos editables del usuario
+	fields := userFields{FirstName: user.FirstName, LastName: user.LastName, Email: user.Email}
+	if err := mergepatch.ApplyTo(&fields, patch); err != nil {
+		http.Error(w, "Invalid merge patch: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := updateUser(r, &user, fields); err != nil {
+		writeSaveError(w, err, "Failed to update user")
+		return
+	}
+
+	setETag(w, user.Version)
+	if err := json.NewEncoder(w).Encode(&user); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// updateUser guarda los campos editables del usuario si su versión no cambió y registra la
+// modificación en la auditoría. La comparten PUT, PATCH y el servicio gRPC
+func updateUser(r *http.Request, user *models.User, fields userFields) error {
+	before := *user
+	user.FirstName = fields.FirstName
+	user.LastName = fields.LastName
+	user.Email = fields.Email
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, user, &user.Version); err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceUser, user.ID, &before, user)
+	})
+}
+
 // DeleteUserHandler elimina un usuario específico por ID
 func DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r) // Extraer parámetros de la URL
@@ -146,8 +235,8 @@ func DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Eliminar el usuario de la base de datos
-	if err := db.DB.Unscoped().Delete(&user).Error; err != nil {
+	// Eliminar lógicamente el usuario: puede recuperarse hasta que se purgue
+	if err := deleteUser(r, user); err != nil {
 		// Manejar el error si ocurre al eliminar el usuario
 		http.Error(w, "Failed to delete user", http.StatusInternalServerError)
 		return
@@ -156,3 +245,13 @@ func DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	// Devolver un estado 200 OK si la eliminación fue exitosa
 	w.WriteHeader(http.StatusOK)
 }
+
+// deleteUser elimina lógicamente el usuario y lo registra en la auditoría
+func deleteUser(r *http.Request, user models.User) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceUser, user.ID, &user, nil)
+	})
+}
@@ -9,26 +9,27 @@ import (
 	"testing"
 
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	middleware "github.com/germancaradec/Go-API-REST-PostgresSQL.git/midle"
 	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
-// Configura el router para las pruebas
-func setupRouter() *mux.Router {
+// Configura el router para las pruebas. Las respuestas se validan contra la especificación OpenAPI
+func setupRouter() http.Handler {
 	r := mux.NewRouter()
 	r.HandleFunc("/users", GetUsersHandler).Methods("GET")
 	r.HandleFunc("/users/{id}", GetUserHandler).Methods("GET")
 	r.HandleFunc("/users", PostUserHandler).Methods("POST")
 	r.HandleFunc("/users/{id}", UpdateUserHandler).Methods("PUT")
 	r.HandleFunc("/users/{id}", DeleteUserHandler).Methods("DELETE")
-	return r
+	return middleware.ValidationWithResponses(OpenAPI, true, r)
 }
 
 // Conecta a la base de datos y realiza las migraciones necesarias para los tests
 func setupDB() {
 	db.DBConnection() // Conectar a la base de datos
-	db.DB.AutoMigrate(&models.User{}, &models.Reservation{}) // Migrar los modelos User y Reservation
+	db.DB.AutoMigrate(&models.User{}, &models.Reservation{}, &models.AuditLog{}) // Migrar los modelos User y Reservation y la auditoría
 }
 
 // Limpia las tablas de la base de datos para evitar errores de clave duplicada y restricciones de clave externa
@@ -201,7 +202,11 @@ func TestDeleteUserHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
+	// El usuario queda eliminado lógicamente: no aparece en las consultas pero sigue en la tabla
 	var deletedUser models.User
-	err = db.DB.Unscoped().First(&deletedUser, user.ID).Error
+	err = db.DB.First(&deletedUser, user.ID).Error
 	assert.Error(t, err)
+	err = db.DB.Unscoped().First(&deletedUser, user.ID).Error
+	assert.NoError(t, err)
+	assert.True(t, deletedUser.DeletedAt.Valid)
 }
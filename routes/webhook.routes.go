@@ -0,0 +1,267 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/webhooks"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// validWebhookSubscription comprueba que la URL sea absoluta http(s) y que los tipos de evento existan
+func validWebhookSubscription(subscription models.WebhookSubscription) bool {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return false
+	}
+	if strings.TrimSpace(subscription.EventTypes) == "" {
+		return false
+	}
+	for _, eventType := range strings.Split(subscription.EventTypes, ",") {
+		eventType = strings.TrimSpace(eventType)
+		if eventType != events.All && !events.Known(eventType) {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutSecret devuelve la suscripción sin su secreto, para responder y auditar
+func withoutSecret(subscription models.WebhookSubscription) models.WebhookSubscription {
+	subscription.Secret = ""
+	return subscription
+}
+
+// findWebhookSubscription busca la suscripción indicada en la URL y responde 404 si no existe
+func findWebhookSubscription(w http.ResponseWriter, r *http.Request) (models.WebhookSubscription, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var subscription models.WebhookSubscription
+	if err := db.DB.First(&subscription, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Webhook subscription not found"))
+			return subscription, false
+		}
+		http.Error(w, "Failed to retrieve webhook subscription", http.StatusInternalServerError)
+		return subscription, false
+	}
+	return subscription, true
+}
+
+// GetWebhooksHandler obtiene las suscripciones de webhooks
+func GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	var subscriptions []models.WebhookSubscription
+	if err := db.DB.Order("id asc").Find(&subscriptions).Error; err != nil {
+		http.Error(w, "Failed to retrieve webhook subscriptions", http.StatusInternalServerError)
+		return
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	if err := json.NewEncoder(w).Encode(&subscriptions); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetWebhookHandler obtiene una suscripción de webhooks por su ID
+func GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := findWebhookSubscription(w, r)
+	if !ok {
+		return
+	}
+	subscription = withoutSecret(subscription)
+
+	if err := json.NewEncoder(w).Encode(&subscription); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateWebhookHandler crea una suscripción. Si no se indica un secreto se genera uno, que solo se
+// devuelve en esta respuesta
+func CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription := models.WebhookSubscription{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil || !validWebhookSubscription(subscription) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = webhooks.NewSecret()
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&subscription).Error; err != nil {
+			return err
+		}
+		// Active tiene valor por defecto en la base de datos: guardar explícitamente una suscripción inactiva
+		if !subscription.Active {
+			if err := tx.Model(&subscription).Update("active", false).Error; err != nil {
+				return err
+			}
+		}
+		after := withoutSecret(subscription)
+		return audit.Record(tx, r, audit.ActionCreate, audit.ResourceWebhookSubscription, subscription.ID, nil, &after)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&subscription); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// UpdateWebhookHandler modifica la URL, los tipos de evento, la descripción o el estado de una
+// suscripción. El secreto solo cambia si se envía uno nuevo
+func UpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := findWebhookSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	updated := subscription
+	if err := json.NewDecoder(r.Body).Decode(&updated); err != nil || !validWebhookSubscription(updated) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	before := withoutSecret(subscription)
+	subscription.URL = updated.URL
+	subscription.EventTypes = updated.EventTypes
+	subscription.Description = updated.Description
+	subscription.Active = updated.Active
+	if updated.Secret != "" {
+		subscription.Secret = updated.Secret
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&subscription).Error; err != nil {
+			return err
+		}
+		after := withoutSecret(subscription)
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceWebhookSubscription, subscription.ID, &before, &after)
+	})
+	if err != nil {
+		http.Error(w, "Failed to update webhook subscription", http.StatusInternalServerError)
+		return
+	}
+
+	subscription = withoutSecret(subscription)
+	if err := json.NewEncoder(w).Encode(&subscription); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// DeleteWebhookHandler elimina una suscripción. Sus entregas pendientes pasan a la lista de mensajes muertos
+func DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := findWebhookSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&subscription).Error; err != nil {
+			return err
+		}
+		before := withoutSecret(subscription)
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceWebhookSubscription, subscription.ID, &before, nil)
+	})
+	if err != nil {
+		http.Error(w, "Failed to delete webhook subscription", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetWebhookDeliveriesHandler obtiene las últimas entregas de una suscripción, con filtro opcional por status
+func GetWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := findWebhookSubscription(w, r)
+	if !ok {
+		return
+	}
+
+	conn := db.DB.Where("subscription_id = ?", subscription.ID).Order("id desc").Limit(100)
+	if status := r.URL.Query().Get("status"); status != "" {
+		conn = conn.Where("status = ?", status)
+	}
+	var deliveries []models.WebhookDelivery
+	if err := conn.Find(&deliveries).Error; err != nil {
+		http.Error(w, "Failed to retrieve webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&deliveries); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetWebhookDeadLettersHandler obtiene las entregas que agotaron sus reintentos, con filtros
+// opcionales por subscription_id y event_type
+func GetWebhookDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Where("status = ?", models.WebhookDead).Order("id desc").Limit(100)
+	query := r.URL.Query()
+	for _, param := range []string{"subscription_id", "event_type"} {
+		if value := query.Get(param); value != "" {
+			conn = conn.Where(param+" = ?", value)
+		}
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := conn.Find(&deliveries).Error; err != nil {
+		http.Error(w, "Failed to retrieve webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&deliveries); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// ReplayWebhookDeliveryHandler vuelve a enviar una entrega, por ejemplo una de la lista de mensajes
+// muertos después de corregir el receptor. El cuerpo y el ID del evento no cambian
+func ReplayWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var delivery models.WebhookDelivery
+	if err := db.DB.First(&delivery, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Webhook delivery not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve webhook delivery", http.StatusInternalServerError)
+		return
+	}
+	if delivery.Status == models.WebhookPending {
+		http.Error(w, "Webhook delivery is already pending", http.StatusConflict)
+		return
+	}
+
+	before := delivery
+	delivery.Status = models.WebhookPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&delivery).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionUpdate, audit.ResourceWebhookDelivery, delivery.ID, &before, &delivery)
+	})
+	if err != nil {
+		http.Error(w, "Failed to replay webhook delivery", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&delivery); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
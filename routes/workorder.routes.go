@@ -0,0 +1,400 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/audit"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/inventory"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// Errores de validación de órdenes de trabajo y períodos fuera de servicio
+var (
+	errEmployeeNotFound = errors.New("employee not found")
+	errInvalidOutage    = errors.New("invalid outage period")
+)
+
+// validPriority indica si la prioridad es una de las soportadas
+func validPriority(priority string) bool {
+	switch priority {
+	case models.PriorityLow, models.PriorityNormal, models.PriorityHigh, models.PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// validWorkOrderStatus indica si el estado de la orden es uno de los soportados
+func validWorkOrderStatus(status string) bool {
+	switch status {
+	case models.WorkOrderOpen, models.WorkOrderInProgress, models.WorkOrderResolved, models.WorkOrderCancelled:
+		return true
+	}
+	return false
+}
+
+// checkEmployee comprueba que exista el empleado al que se asigna la tarea
+func checkEmployee(tx *gorm.DB, employeeID *uint) error {
+	if employeeID == nil {
+		return nil
+	}
+	if err := tx.First(&models.Employee{}, *employeeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errEmployeeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// createOutage registra un período fuera de servicio. Si empieza hoy o antes y deja la habitación
+// fuera de orden, la habitación se marca como tal para housekeeping
+func createOutage(tx *gorm.DB, r *http.Request, outage *models.RoomOutage) error {
+	if outage.Type != models.OutageOutOfOrder && outage.Type != models.OutageOutOfService {
+		return errInvalidOutage
+	}
+	outage.StartDate = inventory.Day(outage.StartDate)
+	if outage.EndDate != nil {
+		end := inventory.Day(*outage.EndDate)
+		if !end.After(outage.StartDate) {
+			return errInvalidOutage
+		}
+		outage.EndDate = &end
+	}
+	if err := tx.First(&models.Room{}, outage.RoomID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errRoomNotFound
+		}
+		return err
+	}
+	if err := tx.Create(outage).Error; err != nil {
+		return err
+	}
+	if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceRoomOutage, outage.ID, nil, outage); err != nil {
+		return err
+	}
+	today := inventory.Day(time.Now())
+	if outage.Type == models.OutageOutOfOrder && !outage.StartDate.After(today) && (outage.EndDate == nil || outage.EndDate.After(today)) {
+		return setRoomHousekeeping(tx, r, outage.RoomID, models.RoomOutOfOrder)
+	}
+	return nil
+}
+
+// closeOutage termina hoy un período abierto. Una habitación que estaba fuera de orden vuelve como
+// sucia para que housekeeping la prepare antes de venderla
+func closeOutage(tx *gorm.DB, r *http.Request, outage models.RoomOutage) error {
+	today := inventory.Day(time.Now())
+	if outage.EndDate != nil && !outage.EndDate.After(today) {
+		return nil
+	}
+	before := outage
+	end := today
+	if !end.After(outage.StartDate) {
+		// El período todavía no empezó: se descarta
+		if err := tx.Delete(&outage).Error; err != nil {
+			return err
+		}
+		return audit.Record(tx, r, audit.ActionDelete, audit.ResourceRoomOutage, outage.ID, &before, nil)
+	}
+	outage.EndDate = &end
+	if err := tx.Model(&outage).Update("end_date", end).Error; err != nil {
+		return err
+	}
+	if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceRoomOutage, outage.ID, &before, &outage); err != nil {
+		return err
+	}
+	if outage.Type == models.OutageOutOfOrder {
+		var room models.Room
+		if err := tx.First(&room, outage.RoomID).Error; err != nil {
+			return err
+		}
+		if room.Housekeeping == models.RoomOutOfOrder {
+			return setRoomHousekeeping(tx, r, room.ID, models.RoomDirty)
+		}
+	}
+	return nil
+}
+
+// writeWorkOrderError traduce los errores de órdenes de trabajo a respuestas HTTP
+func writeWorkOrderError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errRoomNotFound), errors.Is(err, errEmployeeNotFound), errors.Is(err, errInvalidOutage):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// GetWorkOrdersHandler obtiene las órdenes de trabajo, con filtros opcionales por status, room_id,
+// employee_id y priority
+func GetWorkOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Preload("Room").Order("id asc")
+	query := r.URL.Query()
+	for _, param := range []string{"status", "room_id", "employee_id", "priority"} {
+		if value := query.Get(param); value != "" {
+			conn = conn.Where(param+" = ?", value)
+		}
+	}
+
+	var workOrders []models.WorkOrder
+	if err := conn.Find(&workOrders).Error; err != nil {
+		http.Error(w, "Failed to retrieve work orders", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&workOrders); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// findWorkOrder busca la orden de trabajo indicada en la URL y responde 404 si no existe
+func findWorkOrder(w http.ResponseWriter, r *http.Request) (models.WorkOrder, bool) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var workOrder models.WorkOrder
+	if err := db.DB.Preload("Room").Preload("Outages").First(&workOrder, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Work order not found"))
+			return workOrder, false
+		}
+		http.Error(w, "Failed to retrieve work order", http.StatusInternalServerError)
+		return workOrder, false
+	}
+	return workOrder, true
+}
+
+// GetWorkOrderHandler obtiene una orden de trabajo con sus períodos fuera de servicio
+func GetWorkOrderHandler(w http.ResponseWriter, r *http.Request) {
+	workOrder, ok := findWorkOrder(w, r)
+	if !ok {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&workOrder); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// workOrderRequest es el cuerpo de una orden de trabajo nueva
+type workOrderRequest struct {
+	RoomID      uint   `json:"room_id"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+	EmployeeID  *uint  `json:"employee_id"`
+	Outage      string `json:"outage"`
+}
+
+// CreateWorkOrderHandler crea una orden de trabajo. Con "outage" la habitación sale de inventario
+// ("out_of_order") o deja de asignarse ("out_of_service") desde hoy hasta que la orden se resuelva
+func CreateWorkOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var body workOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RoomID == 0 || body.Description == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if body.Priority == "" {
+		body.Priority = models.PriorityNormal
+	}
+	if !validPriority(body.Priority) {
+		http.Error(w, "Invalid priority", http.StatusBadRequest)
+		return
+	}
+
+	workOrder := models.WorkOrder{
+		RoomID:      body.RoomID,
+		Description: body.Description,
+		Priority:    body.Priority,
+		EmployeeID:  body.EmployeeID,
+		Status:      models.WorkOrderOpen,
+	}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Room{}, body.RoomID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errRoomNotFound
+			}
+			return err
+		}
+		if err := checkEmployee(tx, body.EmployeeID); err != nil {
+			return err
+		}
+		if err := tx.Create(&workOrder).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionCreate, audit.ResourceWorkOrder, workOrder.ID, nil, &workOrder); err != nil {
+			return err
+		}
+		if body.Outage == "" {
+			return nil
+		}
+		outage := models.RoomOutage{
+			RoomID:      workOrder.RoomID,
+			WorkOrderID: &workOrder.ID,
+			Type:        body.Outage,
+			StartDate:   time.Now(),
+			Reason:      workOrder.Description,
+		}
+		if err := createOutage(tx, r, &outage); err != nil {
+			return err
+		}
+		workOrder.Outages = append(workOrder.Outages, outage)
+		return nil
+	})
+	if err != nil {
+		writeWorkOrderError(w, err, "Failed to create work order")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&workOrder); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// workOrderUpdate es el avance de una orden de trabajo; los campos omitidos se conservan
+type workOrderUpdate struct {
+	Description *string `json:"description"`
+	Priority    *string `json:"priority"`
+	EmployeeID  *uint   `json:"employee_id"`
+	Status      *string `json:"status"`
+}
+
+// UpdateWorkOrderHandler actualiza la descripción, prioridad, empleado o estado de una orden. Al
+// resolverla o cancelarla se cierran sus períodos fuera de servicio abiertos
+func UpdateWorkOrderHandler(w http.ResponseWriter, r *http.Request) {
+	workOrder, ok := findWorkOrder(w, r)
+	if !ok {
+		return
+	}
+
+	var body workOrderUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if (body.Priority != nil && !validPriority(*body.Priority)) || (body.Status != nil && !validWorkOrderStatus(*body.Status)) {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	before := workOrder
+	before.Outages = nil
+	if body.Description != nil {
+		workOrder.Description = *body.Description
+	}
+	if body.Priority != nil {
+		workOrder.Priority = *body.Priority
+	}
+	if body.EmployeeID != nil {
+		workOrder.EmployeeID = body.EmployeeID
+	}
+	if body.Status != nil && *body.Status != workOrder.Status {
+		workOrder.Status = *body.Status
+		switch workOrder.Status {
+		case models.WorkOrderInProgress:
+			workOrder.StartedAt = &now
+		case models.WorkOrderResolved:
+			workOrder.ResolvedAt = &now
+		}
+	}
+	finished := workOrder.Status == models.WorkOrderResolved || workOrder.Status == models.WorkOrderCancelled
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkEmployee(tx, body.EmployeeID); err != nil {
+			return err
+		}
+		outages := workOrder.Outages
+		workOrder.Outages = nil
+		if err := tx.Omit("Room").Save(&workOrder).Error; err != nil {
+			return err
+		}
+		if err := audit.Record(tx, r, audit.ActionUpdate, audit.ResourceWorkOrder, workOrder.ID, &before, &workOrder); err != nil {
+			return err
+		}
+		if finished {
+			for _, outage := range outages {
+				if err := closeOutage(tx, r, outage); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Where("work_order_id = ?", workOrder.ID).Order("id asc").Find(&workOrder.Outages).Error
+	})
+	if err != nil {
+		writeWorkOrderError(w, err, "Failed to update work order")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&workOrder); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// GetRoomOutagesHandler obtiene los períodos fuera de servicio, opcionalmente de una habitación
+func GetRoomOutagesHandler(w http.ResponseWriter, r *http.Request) {
+	conn := db.DB.Order("start_date asc, id asc")
+	if roomID := r.URL.Query().Get("room_id"); roomID != "" {
+		conn = conn.Where("room_id = ?", roomID)
+	}
+
+	var outages []models.RoomOutage
+	if err := conn.Find(&outages).Error; err != nil {
+		http.Error(w, "Failed to retrieve room outages", http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&outages); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// CreateRoomOutageHandler programa un período fuera de servicio, por ejemplo por una reforma
+func CreateRoomOutageHandler(w http.ResponseWriter, r *http.Request) {
+	var outage models.RoomOutage
+	if err := json.NewDecoder(r.Body).Decode(&outage); err != nil || outage.RoomID == 0 || outage.StartDate.IsZero() {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	outage.WorkOrderID = nil
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		return createOutage(tx, r, &outage)
+	})
+	if err != nil {
+		writeWorkOrderError(w, err, "Failed to create room outage")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&outage); err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+	}
+}
+
+// EndRoomOutageHandler termina hoy un período fuera de servicio
+func EndRoomOutageHandler(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r) // Extraer parámetros de la URL
+	var outage models.RoomOutage
+	if err := db.DB.First(&outage, params["id"]).Error; err != nil {
+		if err.Error() == "record not found" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Room outage not found"))
+			return
+		}
+		http.Error(w, "Failed to retrieve room outage", http.StatusInternalServerError)
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		return closeOutage(tx, r, outage)
+	})
+	if err != nil {
+		http.Error(w, "Failed to end room outage", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,265 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/events"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cabeceras de las entregas
+const (
+	SignatureHeader = "X-Webhook-Signature" // "t=<unix>,v1=<hmac hex>"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// MaxAttempts es la cantidad de intentos antes de pasar una entrega a la lista de mensajes muertos
+const MaxAttempts = 8
+
+// DefaultTolerance es la antigüedad máxima aceptada de una firma al verificarla
+const DefaultTolerance = 5 * time.Minute
+
+// Errores de la verificación de firmas
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpiredSignature = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Client es el cliente HTTP con el que se hacen las entregas
+var Client = &http.Client{Timeout: 10 * time.Second}
+
+// Envelope es el cuerpo JSON de una entrega
+type Envelope struct {
+	ID            uint            `json:"id"` // Identificador del evento: se repite en los reintentos
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// NewSecret genera un secreto aleatorio para firmar las entregas
+func NewSecret() string {
+	buf := make([]byte, 24)
+	rand.Read(buf)
+	return "whsec_" + hex.EncodeToString(buf)
+}
+
+// Sign devuelve la cabecera de firma: un HMAC-SHA256 de "<timestamp>.<cuerpo>" con el secreto de la
+// suscripción. Incluir la marca de tiempo en la firma impide reutilizar entregas antiguas
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts + "."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify comprueba la cabecera de firma de una entrega recibida, tal como deben hacerlo los receptores
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signature = value
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(mac(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+// Backoff devuelve la espera antes del siguiente intento: 1, 2, 4, 8... minutos
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return time.Duration(1<<uint(attempts-1)) * time.Minute
+}
+
+// Subscribe registra los webhooks como suscriptores de todos los eventos de dominio
+func Subscribe() {
+	events.Subscribe(events.All, "webhooks", fanOut)
+}
+
+// fanOut crea una entrega del evento para cada suscripción activa que lo recibe. Si el evento se
+// procesa de nuevo, las entregas ya creadas no se duplican
+func fanOut(tx *gorm.DB, event models.DomainEvent) error {
+	var subscriptions []models.WebhookSubscription
+	if err := tx.Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(Envelope{
+		ID:            event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		CreatedAt:     event.CreatedAt,
+		Data:          json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event.Type) {
+			continue
+		}
+		delivery := models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Body:           string(body),
+			Status:         models.WebhookPending,
+			NextAttemptAt:  time.Now(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Post envía la entrega firmada a la URL indicada. Cualquier respuesta fuera del rango 2xx es un fallo
+func Post(client *http.Client, url, secret string, delivery models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Body)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hotel-api-webhooks")
+	req.Header.Set(SignatureHeader, Sign(secret, now, body))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// DeliveryLease es el tiempo que una entrega queda reservada para el proceso que la envía. Si el
+// proceso termina sin registrar el resultado, otro la vuelve a intentar cuando vence
+const DeliveryLease = 5 * time.Minute
+
+// Deliver intenta las entregas pendientes cuyo próximo intento venció antes de now. Las que agotan los
+// reintentos, o cuya suscripción ya no está activa, pasan a la lista de mensajes muertos. Cada entrega
+// se reserva por DeliveryLease en una transacción corta y el POST se hace fuera de ella, para no
+// mantener bloqueada la fila ni abierta la transacción mientras responde el suscriptor. La reserva, la
+// firma y el próximo intento usan la hora de cada entrega: las respuestas lentas del lote no dejan
+// vencidas las firmas ni las reservas de las entregas siguientes
+func Deliver(db *gorm.DB, client *http.Client, now time.Time) (int, error) {
+	var ids []uint
+	if err := db.Model(&models.WebhookDelivery{}).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookPending, now).
+		Order("id asc").Limit(100).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, id := range ids {
+		delivery, subscription, ok, err := claim(db, id)
+		if err != nil {
+			return delivered, err
+		}
+		if !ok {
+			continue
+		}
+
+		claimed := delivery.Attempts
+		if subscription == nil {
+			delivery.Status = models.WebhookDead
+			delivery.LastError = "subscription is not active"
+		} else {
+			code, err := Post(client, subscription.URL, subscription.Secret, delivery, time.Now())
+			done := time.Now()
+			delivery.LastStatusCode = code
+			if err != nil {
+				delivery.LastError = err.Error()
+				delivery.NextAttemptAt = done.Add(Backoff(delivery.Attempts))
+				if delivery.Attempts >= MaxAttempts {
+					delivery.Status = models.WebhookDead
+				}
+				log.Printf("webhook delivery %d to %s failed (attempt %d): %v", delivery.ID, subscription.URL, delivery.Attempts, err)
+			} else {
+				delivery.Status = models.WebhookDelivered
+				delivery.DeliveredAt = &done
+				delivery.LastError = ""
+				delivered++
+			}
+		}
+		// Si la reserva venció y otro proceso volvió a tomar la entrega, el resultado es suyo
+		if err := db.Model(&delivery).Where("attempts = ?", claimed).
+			Select("Status", "NextAttemptAt", "LastStatusCode", "LastError", "DeliveredAt").Updates(&delivery).Error; err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+// claim reserva la entrega si sigue pendiente y nadie la tomó, y devuelve su suscripción, o nil si ya
+// no está activa. Cuenta el intento y corre el próximo hasta que vence la reserva; una entrega sin
+// suscripción activa no cuenta intento. Devuelve false si otro proceso se adelantó
+func claim(db *gorm.DB, id uint) (models.WebhookDelivery, *models.WebhookSubscription, bool, error) {
+	var delivery models.WebhookDelivery
+	var active *models.WebhookSubscription
+	now := time.Now()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookPending, now).Limit(1).Find(&delivery, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var subscription models.WebhookSubscription
+		if err := tx.Unscoped().First(&subscription, delivery.SubscriptionID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if subscription.ID != 0 && !subscription.DeletedAt.Valid && subscription.Active {
+			active = &subscription
+			delivery.Attempts++
+		}
+		delivery.NextAttemptAt = now.Add(DeliveryLease)
+		return tx.Model(&delivery).Select("Attempts", "NextAttemptAt").Updates(&delivery).Error
+	})
+	return delivery, active, err == nil && delivery.ID != 0, err
+}
@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/db"
+	"github.com/germancaradec/Go-API-REST-PostgresSQL.git/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/clause"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1,"type":"ReservationCreated"}`)
+	header := Sign("secret", now, body)
+	assert.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, header)
+
+	assert.NoError(t, Verify("secret", header, body, now.Add(time.Minute), DefaultTolerance))
+	assert.ErrorIs(t, Verify("other", header, body, now, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{"id":2}`), now, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", "v1=abc", body, now, DefaultTolerance), ErrInvalidSignature)
+	// Una entrega antigua reenviada por un tercero se rechaza aunque la firma sea válida
+	assert.ErrorIs(t, Verify("secret", header, body, now.Add(time.Hour), DefaultTolerance), ErrExpiredSignature)
+}
+
+func TestPostToLocalReceiver(t *testing.T) {
+	now := time.Now()
+	var received *http.Request
+	var receivedBody []byte
+	status := http.StatusNoContent
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	delivery := models.WebhookDelivery{ID: 9, EventType: "ReservationCancelled", Body: `{"id":5,"type":"ReservationCancelled"}`}
+	code, err := Post(receiver.Client(), receiver.URL, "secret", delivery, now)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, delivery.Body, string(receivedBody))
+	assert.Equal(t, "ReservationCancelled", received.Header.Get(EventHeader))
+	assert.Equal(t, "9", received.Header.Get(DeliveryHeader))
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.NoError(t, Verify("secret", received.Header.Get(SignatureHeader), receivedBody, now, DefaultTolerance))
+
+	// Una respuesta fuera del rango 2xx se reintenta
+	status = http.StatusServiceUnavailable
+	code, err = Post(receiver.Client(), receiver.URL, "secret", delivery, now)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestSubscriptionWants(t *testing.T) {
+	subscription := models.WebhookSubscription{EventTypes: "ReservationCreated, ReservationCancelled"}
+	assert.True(t, subscription.Wants("ReservationCancelled"))
+	assert.False(t, subscription.Wants("GuestCheckedIn"))
+
+	subscription.EventTypes = "*"
+	assert.True(t, subscription.Wants("GuestCheckedIn"))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, Backoff(1))
+	assert.Equal(t, 8*time.Minute, Backoff(4))
+	assert.Equal(t, 64*time.Minute, Backoff(MaxAttempts-1))
+}
+
+func TestNewSecret(t *testing.T) {
+	secret := NewSecret()
+	assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, secret)
+	assert.NotEqual(t, secret, NewSecret())
+}
+
+func TestDeliverPostsOutsideTransaction(t *testing.T) {
+	if db.DSN == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	db.DBConnection()
+	db.DB.AutoMigrate(&models.WebhookSubscription{}, &models.WebhookDelivery{})
+	defer db.DB.Unscoped().Exec("DELETE FROM webhook_deliveries")
+	defer db.DB.Unscoped().Exec("DELETE FROM webhook_subscriptions")
+
+	now := time.Now()
+	var delivery models.WebhookDelivery
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Mientras se envía la fila no está bloqueada y otro proceso no vuelve a tomarla
+		var locked models.WebhookDelivery
+		assert.NoError(t, db.DB.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).First(&locked, delivery.ID).Error)
+		again, err := Deliver(db.DB, http.DefaultClient, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, again)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	subscription := models.WebhookSubscription{URL: receiver.URL, EventTypes: "*", Secret: "secret", Active: true}
+	assert.NoError(t, db.DB.Create(&subscription).Error)
+	delivery = models.WebhookDelivery{SubscriptionID: subscription.ID, EventID: 1, EventType: "ReservationCreated", Body: `{"id":1}`,
+		Status: models.WebhookPending, NextAttemptAt: now}
+	assert.NoError(t, db.DB.Create(&delivery).Error)
+
+	delivered, err := Deliver(db.DB, receiver.Client(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	assert.NoError(t, db.DB.First(&delivery, delivery.ID).Error)
+	assert.Equal(t, models.WebhookDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+}